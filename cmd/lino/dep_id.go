@@ -18,6 +18,7 @@
 package main
 
 import (
+	"io"
 	"os"
 
 	infra "github.com/cgi-fr/lino/internal/infra/id"
@@ -28,19 +29,45 @@ func idStorage() domain.Storage {
 	return infra.NewMultiStorage(infra.NewYAMLStorage(), infra.NewDOTStorage())
 }
 
-func idStorageFactory() func(string) domain.Storage {
-	return func(table string) domain.Storage {
-		if table == "" {
+func namedIDStorageFactory() func(string) domain.Storage {
+	return func(name string) domain.Storage {
+		if name == "" {
 			return idStorage()
 		}
+		return infra.NewMultiStorage(infra.NewNamedYAMLStorage(name), infra.NewNamedDOTStorage(name))
+	}
+}
+
+func idStorageFactory() func(string, string) domain.Storage {
+	return func(table string, name string) domain.Storage {
+		if table == "" {
+			return namedIDStorageFactory()(name)
+		}
 		return infra.NewTableStorage(domain.NewTable(table))
 	}
 }
 
-func idExporter() domain.Exporter {
-	return infra.NewGraphVizExporter()
+func idCatalog() domain.Catalog {
+	return infra.NewYAMLCatalog()
+}
+
+func idExporterFactories() map[string]func(io.Writer) domain.Exporter {
+	return map[string]func(io.Writer) domain.Exporter{
+		"dot":      infra.NewDOTExporter,
+		"mermaid":  infra.NewMermaidExporter,
+		"plantuml": infra.NewPlantUMLExporter,
+		"svg":      infra.NewGraphVizExporter,
+	}
 }
 
 func idJSONStorage(file os.File) domain.Storage {
 	return infra.NewJSONStorage(file)
 }
+
+func idStatisticsReaderFactory() map[string]domain.StatisticsReaderFactory {
+	return map[string]domain.StatisticsReaderFactory{
+		"postgres":   infra.NewPostgresStatisticsReaderFactory(),
+		"godror":     infra.NewOracleStatisticsReaderFactory(),
+		"godror-raw": infra.NewOracleStatisticsReaderFactory(),
+	}
+}
@@ -48,3 +48,7 @@ func pullRowReaderFactory() func(file io.ReadCloser) domain.RowReader {
 func traceListner(file *os.File) domain.TraceListener {
 	return infra.NewJSONTraceListener(file)
 }
+
+func pullStatsWriterFactory() func(file io.Writer) domain.StatsWriter {
+	return infra.NewJSONStatsWriter
+}
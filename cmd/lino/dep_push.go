@@ -39,3 +39,7 @@ func pushRowIteratorFactory() func(io.ReadCloser) domain.RowIterator {
 func pushRowExporterFactory() func(io.Writer) domain.RowWriter {
 	return infra.NewJSONRowWriter
 }
+
+func pushStatsWriterFactory() func(io.Writer) domain.StatsWriter {
+	return infra.NewJSONStatsWriter
+}
@@ -26,6 +26,22 @@ func relationStorage() domain.Storage {
 	return infra.NewYAMLStorage()
 }
 
+func relationInferenceReaderFactory() map[string]domain.InferenceReaderFactory {
+	return map[string]domain.InferenceReaderFactory{
+		"postgres":   infra.NewPostgresInferenceReaderFactory(),
+		"godror":     infra.NewOracleInferenceReaderFactory(),
+		"godror-raw": infra.NewOracleInferenceReaderFactory(),
+	}
+}
+
+func relationIntegrityCheckerFactory() map[string]domain.IntegrityCheckerFactory {
+	return map[string]domain.IntegrityCheckerFactory{
+		"postgres":   infra.NewPostgresIntegrityCheckerFactory(),
+		"godror":     infra.NewOracleIntegrityCheckerFactory(),
+		"godror-raw": infra.NewOracleIntegrityCheckerFactory(),
+	}
+}
+
 func relationExtractorFactory() map[string]domain.ExtractorFactory {
 	return map[string]domain.ExtractorFactory{
 		"postgres":   infra.NewPostgresExtractorFactory(),
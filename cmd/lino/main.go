@@ -24,13 +24,16 @@ import (
 	"strings"
 
 	over "github.com/Trendyol/overlog"
+	"github.com/cgi-fr/lino/internal/app/credentials"
 	"github.com/cgi-fr/lino/internal/app/dataconnector"
 	"github.com/cgi-fr/lino/internal/app/http"
 	"github.com/cgi-fr/lino/internal/app/id"
 	"github.com/cgi-fr/lino/internal/app/pull"
 	"github.com/cgi-fr/lino/internal/app/push"
 	"github.com/cgi-fr/lino/internal/app/relation"
+	"github.com/cgi-fr/lino/internal/app/schema"
 	"github.com/cgi-fr/lino/internal/app/table"
+	"github.com/cgi-fr/lino/internal/app/urlbuilder"
 	"github.com/mattn/go-isatty"
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
@@ -97,8 +100,10 @@ func init() {
 	rootCmd.PersistentFlags().BoolVar(&debug, "debug", false, "add debug information to logs (very slow)")
 	rootCmd.PersistentFlags().StringVar(&colormode, "color", "auto", "use colors in log outputs : yes, no or auto")
 	rootCmd.AddCommand(dataconnector.NewCommand("lino", os.Stderr, os.Stdout, os.Stdin))
+	rootCmd.AddCommand(credentials.NewCommand("lino", os.Stderr, os.Stdout, os.Stdin))
 	rootCmd.AddCommand(table.NewCommand("lino", os.Stderr, os.Stdout, os.Stdin))
 	rootCmd.AddCommand(relation.NewCommand("lino", os.Stderr, os.Stdout, os.Stdin))
+	rootCmd.AddCommand(schema.NewCommand("lino", os.Stderr, os.Stdout, os.Stdin))
 	rootCmd.AddCommand(id.NewCommand("lino", os.Stderr, os.Stdout, os.Stdin))
 	rootCmd.AddCommand(pull.NewCommand("lino", os.Stderr, os.Stdout, os.Stdin))
 	rootCmd.AddCommand(push.NewCommand("lino", os.Stderr, os.Stdout, os.Stdin))
@@ -118,10 +123,13 @@ func initConfig() {
 
 	var logger zerolog.Logger
 
+	// secrets resolved by the dataconnectors are redacted from the logs
+	logout := urlbuilder.RedactWriter(os.Stderr)
+
 	if jsonlog {
-		logger = zerolog.New(os.Stderr)
+		logger = zerolog.New(logout)
 	} else {
-		logger = log.Output(zerolog.ConsoleWriter{Out: os.Stderr, NoColor: !color})
+		logger = log.Output(zerolog.ConsoleWriter{Out: logout, NoColor: !color})
 	}
 	if debug {
 		logger = logger.With().Caller().Logger()
@@ -148,9 +156,10 @@ func initConfig() {
 	}
 
 	dataconnector.Inject(dataconnectorStorage(), dataPingerFactory())
-	relation.Inject(dataconnectorStorage(), relationStorage(), relationExtractorFactory())
+	relation.Inject(dataconnectorStorage(), relationStorage(), relationExtractorFactory(), tableStorage(), relationInferenceReaderFactory(), relationIntegrityCheckerFactory())
 	table.Inject(dataconnectorStorage(), tableStorage(), tableExtractorFactory())
-	id.Inject(idStorage(), relationStorage(), idExporter(), idJSONStorage(*os.Stdout))
-	pull.Inject(dataconnectorStorage(), relationStorage(), tableStorage(), idStorageFactory(), pullDataSourceFactory(), pullRowExporterFactory(), pullRowReaderFactory(), traceListner(os.Stderr))
-	push.Inject(dataconnectorStorage(), relationStorage(), tableStorage(), idStorageFactory(), pushDataDestinationFactory(), pushRowIteratorFactory(), pushRowExporterFactory())
+	schema.Inject(dataconnectorStorage(), schemaStorage(), schemaExtractorFactory(), schemaApplierFactory())
+	id.Inject(namedIDStorageFactory(), idCatalog(), relationStorage(), tableStorage(), idExporterFactories(), idJSONStorage(*os.Stdout), dataconnectorStorage(), idStatisticsReaderFactory())
+	pull.Inject(dataconnectorStorage(), relationStorage(), tableStorage(), idStorageFactory(), pullDataSourceFactory(), pullRowExporterFactory(), pullRowReaderFactory(), traceListner(os.Stderr), pullStatsWriterFactory())
+	push.Inject(dataconnectorStorage(), relationStorage(), tableStorage(), idStorageFactory(), pushDataDestinationFactory(), pushRowIteratorFactory(), pushRowExporterFactory(), tableExtractorFactory(), pushStatsWriterFactory())
 }
@@ -0,0 +1,43 @@
+// Copyright (C) 2021 CGI France
+//
+// This file is part of LINO.
+//
+// LINO is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// LINO is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with LINO.  If not, see <http://www.gnu.org/licenses/>.
+
+package credentials
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+// NewCommand implements the cli credentials command
+func NewCommand(fullName string, err *os.File, out *os.File, in *os.File) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "credentials {list,remove,rotate,migrate} [arguments ...]",
+		Short:   "Manage the passwords stored in the local credentials file",
+		Long:    "The local credentials file is used when no credential helper is available, the passwords are encrypted with a master password read from LINO_MASTER_KEY or asked on the terminal",
+		Example: fmt.Sprintf("  %[1]s credentials list\n  %[1]s credentials rotate", fullName),
+	}
+	cmd.AddCommand(newListCommand(fullName, err, out, in))
+	cmd.AddCommand(newRemoveCommand(fullName, err, out, in))
+	cmd.AddCommand(newRotateCommand(fullName, err, out, in))
+	cmd.AddCommand(newMigrateCommand(fullName, err, out, in))
+	cmd.SetOut(out)
+	cmd.SetErr(err)
+	cmd.SetIn(in)
+	return cmd
+}
@@ -0,0 +1,83 @@
+// Copyright (C) 2021 CGI France
+//
+// This file is part of LINO.
+//
+// LINO is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// LINO is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with LINO.  If not, see <http://www.gnu.org/licenses/>.
+
+package credentials
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/cgi-fr/lino/internal/app/localstorage"
+	"github.com/spf13/cobra"
+)
+
+type jsonEntry struct {
+	ServerURL string `json:"serverURL"`
+	Username  string `json:"username"`
+	Encrypted bool   `json:"encrypted"`
+}
+
+// newListCommand implements the cli credentials list command
+func newListCommand(fullName string, err *os.File, out *os.File, in *os.File) *cobra.Command {
+	var output string
+
+	cmd := &cobra.Command{
+		Use:     "list",
+		Short:   "List stored credentials, without the passwords",
+		Long:    "",
+		Example: fmt.Sprintf("  %[1]s credentials list\n  %[1]s credentials list --output json", fullName),
+		Args:    cobra.NoArgs,
+		Aliases: []string{"ls"},
+		Run: func(cmd *cobra.Command, args []string) {
+			entries, e := localstorage.Entries()
+			if e != nil {
+				fmt.Fprintln(err, e.Error())
+				os.Exit(1)
+			}
+
+			switch output {
+			case "json":
+				result := []jsonEntry{}
+				for _, entry := range entries {
+					result = append(result, jsonEntry(entry))
+				}
+				e2 := json.NewEncoder(out).Encode(result)
+				if e2 != nil {
+					fmt.Fprintln(err, e2.Error())
+					os.Exit(1)
+				}
+			case "text":
+				for _, entry := range entries {
+					status := "plaintext"
+					if entry.Encrypted {
+						status = "encrypted"
+					}
+					fmt.Fprintf(out, "%s (user: %s, %s)\n", entry.ServerURL, entry.Username, status)
+				}
+			default:
+				fmt.Fprintf(err, "unknown output format %s\n", output)
+				os.Exit(1)
+			}
+		},
+	}
+	cmd.Flags().StringVarP(&output, "output", "o", "text", "Output format (text|json)")
+	cmd.SetOut(out)
+	cmd.SetErr(err)
+	cmd.SetIn(in)
+	return cmd
+}
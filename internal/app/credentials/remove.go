@@ -0,0 +1,67 @@
+// Copyright (C) 2021 CGI France
+//
+// This file is part of LINO.
+//
+// LINO is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// LINO is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with LINO.  If not, see <http://www.gnu.org/licenses/>.
+
+package credentials
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/cgi-fr/lino/internal/app/localstorage"
+	"github.com/spf13/cobra"
+)
+
+// newRemoveCommand implements the cli credentials remove command
+func newRemoveCommand(fullName string, err *os.File, out *os.File, in *os.File) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "remove [Server URL]",
+		Short:   "Remove stored credentials",
+		Long:    "",
+		Example: fmt.Sprintf("  %[1]s credentials remove postgresql://postgres@localhost:5432/postgres?sslmode=disable", fullName),
+		Args:    cobra.ExactArgs(1),
+		Aliases: []string{"rm"},
+		Run: func(cmd *cobra.Command, args []string) {
+			entries, e1 := localstorage.Entries()
+			if e1 != nil {
+				fmt.Fprintln(err, e1.Error())
+				os.Exit(1)
+			}
+
+			found := false
+			for _, entry := range entries {
+				found = found || entry.ServerURL == args[0]
+			}
+
+			if !found {
+				fmt.Fprintln(err, "no credentials for "+args[0])
+				os.Exit(1)
+			}
+
+			e2 := localstorage.Delete(args[0])
+			if e2 != nil {
+				fmt.Fprintln(err, e2.Error())
+				os.Exit(1)
+			}
+
+			fmt.Fprintln(out, "successfully removed credentials")
+		},
+	}
+	cmd.SetOut(out)
+	cmd.SetErr(err)
+	cmd.SetIn(in)
+	return cmd
+}
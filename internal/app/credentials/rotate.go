@@ -0,0 +1,50 @@
+// Copyright (C) 2021 CGI France
+//
+// This file is part of LINO.
+//
+// LINO is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// LINO is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with LINO.  If not, see <http://www.gnu.org/licenses/>.
+
+package credentials
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/cgi-fr/lino/internal/app/localstorage"
+	"github.com/spf13/cobra"
+)
+
+// newRotateCommand implements the cli credentials rotate command
+func newRotateCommand(fullName string, err *os.File, out *os.File, in *os.File) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "rotate",
+		Short:   "Encrypt stored credentials with a new master password",
+		Long:    fmt.Sprintf("The current master password is read from %s and the new one from %s, or they are asked on the terminal", localstorage.MasterKeyEnv, localstorage.NewMasterKeyEnv),
+		Example: fmt.Sprintf("  %[1]s credentials rotate", fullName),
+		Args:    cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			e := localstorage.Rotate()
+			if e != nil {
+				fmt.Fprintln(err, e.Error())
+				os.Exit(1)
+			}
+
+			fmt.Fprintln(out, "successfully rotated master password")
+		},
+	}
+	cmd.SetOut(out)
+	cmd.SetErr(err)
+	cmd.SetIn(in)
+	return cmd
+}
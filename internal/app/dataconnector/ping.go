@@ -53,7 +53,7 @@ func newPingCommand(fullName string, err *os.File, out *os.File, in *os.File) *c
 				fmt.Fprintln(err, "no datadestination found for database type")
 				os.Exit(4)
 			}
-			pinger := dataPingerFactory.New(u.URL.String())
+			pinger := dataPingerFactory.New(u.URL.String(), dc.Options)
 			e = pinger.Ping()
 			if e != nil {
 				fmt.Fprintln(out, "ping failed")
@@ -0,0 +1,83 @@
+// Copyright (C) 2021 CGI France
+//
+// This file is part of LINO.
+//
+// LINO is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// LINO is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with LINO.  If not, see <http://www.gnu.org/licenses/>.
+
+package dataconnector
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/cgi-fr/lino/internal/app/urlbuilder"
+	"github.com/cgi-fr/lino/pkg/dataconnector"
+	"github.com/spf13/cobra"
+	"github.com/xo/dburl"
+)
+
+// newRemoveCommand implements the cli dataconnector remove command
+func newRemoveCommand(fullName string, err *os.File, out *os.File, in *os.File) *cobra.Command {
+	var flagDeleteCredentials bool
+
+	cmd := &cobra.Command{
+		Use:     "remove [Name]",
+		Short:   "Remove database aliases",
+		Long:    "",
+		Example: fmt.Sprintf("  %[1]s dataconnector remove mydatabase\n  %[1]s dataconnector remove mydatabase --delete-credentials", fullName),
+		Args:    cobra.ExactArgs(1),
+		Aliases: []string{"rm"},
+		Run: func(cmd *cobra.Command, args []string) {
+			alias, e1 := dataconnector.Remove(storage, args[0])
+			if e1 != nil {
+				fmt.Fprintln(err, e1.Description)
+				os.Exit(1)
+			}
+
+			if flagDeleteCredentials {
+				deleteCredentials(alias, err)
+			}
+
+			fmt.Fprintln(out, "successfully removed dataconnector")
+		},
+	}
+	cmd.Flags().BoolVar(&flagDeleteCredentials, "delete-credentials", false, "Delete the password stored for this dataconnector, unless another dataconnector uses the same url")
+	cmd.SetOut(out)
+	cmd.SetErr(err)
+	cmd.SetIn(in)
+	return cmd
+}
+
+// deleteCredentials deletes the password stored for the url of the removed dataconnector, if no other dataconnector uses it.
+func deleteCredentials(alias *dataconnector.DataConnector, err io.Writer) {
+	if others := sharedWith(alias.Name, alias.URL, err); len(others) > 0 {
+		fmt.Fprintf(err, "warn: the password has not been deleted, it is also used by dataconnector %s", strings.Join(others, ", "))
+		fmt.Fprintln(err)
+		return
+	}
+
+	u, e1 := dburl.Parse(alias.URL)
+	if e1 != nil {
+		fmt.Fprintln(err, e1.Error())
+		os.Exit(3)
+	}
+
+	e2 := urlbuilder.DeletePassword(u)
+	if e2 != nil {
+		fmt.Fprintln(err, e2.Error())
+		os.Exit(3)
+	}
+}
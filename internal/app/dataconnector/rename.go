@@ -0,0 +1,51 @@
+// Copyright (C) 2021 CGI France
+//
+// This file is part of LINO.
+//
+// LINO is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// LINO is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with LINO.  If not, see <http://www.gnu.org/licenses/>.
+
+package dataconnector
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/cgi-fr/lino/pkg/dataconnector"
+	"github.com/spf13/cobra"
+)
+
+// newRenameCommand implements the cli dataconnector rename command
+func newRenameCommand(fullName string, err *os.File, out *os.File, in *os.File) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "rename [Name] [New Name]",
+		Short:   "Rename database aliases",
+		Long:    "",
+		Example: fmt.Sprintf("  %[1]s dataconnector rename mydatabase source", fullName),
+		Args:    cobra.ExactArgs(2),
+		Aliases: []string{"mv"},
+		Run: func(cmd *cobra.Command, args []string) {
+			e := dataconnector.Rename(storage, args[0], args[1])
+			if e != nil {
+				fmt.Fprintln(err, e.Description)
+				os.Exit(1)
+			}
+
+			fmt.Fprintln(out, "successfully renamed dataconnector")
+		},
+	}
+	cmd.SetOut(out)
+	cmd.SetErr(err)
+	cmd.SetIn(in)
+	return cmd
+}
@@ -0,0 +1,199 @@
+// Copyright (C) 2021 CGI France
+//
+// This file is part of LINO.
+//
+// LINO is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// LINO is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with LINO.  If not, see <http://www.gnu.org/licenses/>.
+
+package dataconnector
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+
+	"github.com/cgi-fr/lino/internal/app/urlbuilder"
+	"github.com/cgi-fr/lino/pkg/dataconnector"
+	"github.com/spf13/cobra"
+)
+
+type jsonDataConnector struct {
+	Name           string `json:"name"`
+	URL            string `json:"url"`
+	ResolvedURL    string `json:"resolvedURL"`
+	Driver         string `json:"driver"`
+	ReadOnly       bool   `json:"readonly"`
+	Schema         string `json:"schema,omitempty"`
+	User           string `json:"user,omitempty"`
+	UserSource     string `json:"userSource,omitempty"`
+	Password       string `json:"password,omitempty"`
+	PasswordSource string `json:"passwordSource,omitempty"`
+
+	MaxOpenConns     int      `json:"maxOpenConns,omitempty"`
+	ConnectTimeout   string   `json:"connectTimeout,omitempty"`
+	StatementTimeout string   `json:"statementTimeout,omitempty"`
+	SessionInit      []string `json:"sessionInit,omitempty"`
+}
+
+// newShowCommand implements the cli dataconnector show command
+func newShowCommand(fullName string, err *os.File, out *os.File, in *os.File) *cobra.Command {
+	var output string
+
+	cmd := &cobra.Command{
+		Use:     "show [Name]",
+		Short:   "Show the resolved configuration of database aliases, secrets are masked",
+		Long:    "",
+		Example: fmt.Sprintf("  %[1]s dataconnector show mydatabase\n  %[1]s dataconnector show mydatabase --output json", fullName),
+		Args:    cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			alias, e1 := dataconnector.Get(storage, args[0])
+			if e1 != nil {
+				fmt.Fprintln(err, e1.Description)
+				os.Exit(1)
+			}
+
+			if alias == nil {
+				fmt.Fprintln(err, "no dataconnector named "+args[0])
+				os.Exit(1)
+			}
+
+			dc := resolve(alias, err)
+
+			switch output {
+			case "json":
+				e2 := json.NewEncoder(out).Encode(dc)
+				if e2 != nil {
+					fmt.Fprintln(err, e2.Error())
+					os.Exit(1)
+				}
+			case "text":
+				fmt.Fprintf(out, "name: %s\n", dc.Name)
+				fmt.Fprintf(out, "url: %s\n", dc.URL)
+				fmt.Fprintf(out, "resolved url: %s\n", dc.ResolvedURL)
+				fmt.Fprintf(out, "driver: %s\n", dc.Driver)
+				fmt.Fprintf(out, "read-only: %v\n", dc.ReadOnly)
+				if dc.Schema != "" {
+					fmt.Fprintf(out, "schema: %s\n", dc.Schema)
+				}
+				if dc.User != "" {
+					fmt.Fprintf(out, "user: %s (%s)\n", dc.User, dc.UserSource)
+				}
+				if dc.Password != "" {
+					fmt.Fprintf(out, "password: %s (%s)\n", dc.Password, dc.PasswordSource)
+				} else if dc.PasswordSource != "" {
+					fmt.Fprintf(out, "password: not set (%s)\n", dc.PasswordSource)
+				} else {
+					fmt.Fprintln(out, "password: not set")
+				}
+				if dc.MaxOpenConns > 0 {
+					fmt.Fprintf(out, "max open connections: %d\n", dc.MaxOpenConns)
+				}
+				if dc.ConnectTimeout != "" {
+					fmt.Fprintf(out, "connect timeout: %s\n", dc.ConnectTimeout)
+				}
+				if dc.StatementTimeout != "" {
+					fmt.Fprintf(out, "statement timeout: %s\n", dc.StatementTimeout)
+				}
+				for _, statement := range dc.SessionInit {
+					fmt.Fprintf(out, "session init: %s\n", statement)
+				}
+			default:
+				fmt.Fprintf(err, "unknown output format %s\n", output)
+				os.Exit(1)
+			}
+		},
+	}
+	cmd.Flags().StringVarP(&output, "output", "o", "text", "Output format (text|json)")
+	cmd.SetOut(out)
+	cmd.SetErr(err)
+	cmd.SetIn(in)
+	return cmd
+}
+
+// resolve the configuration of the dataconnector, with the password masked
+func resolve(alias *dataconnector.DataConnector, err io.Writer) jsonDataConnector {
+	u := urlbuilder.BuildURL(alias, err)
+
+	dc := jsonDataConnector{
+		Name:     alias.Name,
+		URL:      maskURL(alias.URL),
+		Driver:   u.Unaliased,
+		ReadOnly: alias.ReadOnly,
+		Schema:   alias.Schema,
+		User:     u.User.Username(),
+
+		MaxOpenConns: alias.Options.MaxOpenConns,
+		SessionInit:  alias.Options.SessionInit,
+	}
+
+	if alias.Options.ConnectTimeout > 0 {
+		dc.ConnectTimeout = alias.Options.ConnectTimeout.String()
+	}
+	if alias.Options.StatementTimeout > 0 {
+		dc.StatementTimeout = alias.Options.StatementTimeout.String()
+	}
+
+	switch {
+	case dc.User == "":
+	case urlbuilder.Source(alias.User) != "":
+		dc.UserSource = "from " + urlbuilder.Source(alias.User)
+	case alias.User.Value != "":
+		dc.UserSource = "from dataconnector"
+	default:
+		dc.UserSource = "from url"
+	}
+
+	_, passwordIsSet := u.User.Password()
+	switch {
+	case urlbuilder.Source(alias.Password) != "":
+		dc.PasswordSource = "from " + urlbuilder.Source(alias.Password)
+	case passwordIsSet && hasPassword(alias.URL):
+		dc.PasswordSource = "from url"
+	case passwordIsSet:
+		dc.PasswordSource = "from credential store"
+	}
+
+	resolved := u.URL
+	if passwordIsSet {
+		dc.Password = urlbuilder.Mask
+		resolved.User = url.UserPassword(dc.User, urlbuilder.Mask)
+	}
+	dc.ResolvedURL = resolved.String()
+
+	return dc
+}
+
+// maskURL masks the password written in the url of the dataconnector
+func maskURL(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return urlbuilder.Redact(raw)
+	}
+	if _, ok := u.User.Password(); !ok {
+		return raw
+	}
+	u.User = url.UserPassword(u.User.Username(), urlbuilder.Mask)
+	return u.String()
+}
+
+// hasPassword returns true if a password is written in the url of the dataconnector
+func hasPassword(raw string) bool {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return false
+	}
+	_, ok := u.User.Password()
+	return ok
+}
@@ -0,0 +1,216 @@
+// Copyright (C) 2021 CGI France
+//
+// This file is part of LINO.
+//
+// LINO is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// LINO is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with LINO.  If not, see <http://www.gnu.org/licenses/>.
+
+package dataconnector
+
+import (
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/cgi-fr/lino/internal/app/urlbuilder"
+	"github.com/cgi-fr/lino/pkg/dataconnector"
+	"github.com/spf13/cobra"
+	"github.com/xo/dburl"
+)
+
+// newUpdateCommand implements the cli dataconnector update command
+func newUpdateCommand(fullName string, err *os.File, out *os.File, in *os.File) *cobra.Command {
+	var flagURL string
+	var flagReadonly bool
+	var flagSchema string
+	var flagAskPassword bool
+	var flagPasswordFromEnv string
+	var flagPasswordFromFile string
+	var flagPasswordFromCommand string
+	var flagUserFromEnv string
+	var flagUserFromFile string
+	var flagUserFromCommand string
+	var flagUserValue string
+	var flagMaxOpenConns int
+	var flagConnectTimeout time.Duration
+	var flagStatementTimeout time.Duration
+	var flagSessionInit []string
+
+	cmd := &cobra.Command{
+		Use:     "update [Name]",
+		Short:   "Update database aliases",
+		Long:    "Only the given flags are changed, an empty value removes the schema, the user and password sources or the session init statements",
+		Example: fmt.Sprintf("  %[1]s dataconnector update mydatabase --url postgresql://postgres@localhost:5433/postgres?sslmode=disable\n  %[1]s dataconnector update mydatabase --read-only=false --schema sakila", fullName),
+		Args:    cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			alias, e1 := dataconnector.Get(storage, args[0])
+			if e1 != nil {
+				fmt.Fprintln(err, e1.Description)
+				os.Exit(1)
+			}
+
+			if alias == nil {
+				fmt.Fprintln(err, "no dataconnector named "+args[0])
+				os.Exit(1)
+			}
+
+			flags := cmd.Flags()
+			previousURL := alias.URL
+
+			if flags.Changed("url") {
+				u, e2 := dburl.Parse(flagURL)
+				if e2 != nil {
+					fmt.Fprintln(err, e2.Error())
+					os.Exit(3)
+				}
+
+				if _, isset := u.User.Password(); isset {
+					fmt.Fprintln(err, "warn: password should not be included in URI, use --password-from-env or --password")
+					u.User = url.User(u.User.Username())
+				}
+				alias.URL = u.URL.String()
+			}
+			if flags.Changed("read-only") {
+				alias.ReadOnly = flagReadonly
+			}
+			if flags.Changed("schema") {
+				alias.Schema = flagSchema
+			}
+			if flags.Changed("user") {
+				alias.User = dataconnector.ValueHolder{Value: flagUserValue}
+			}
+			if flags.Changed("user-from-env") {
+				alias.User = dataconnector.ValueHolder{ValueFromEnv: flagUserFromEnv}
+			}
+			if flags.Changed("user-from-file") {
+				alias.User = dataconnector.ValueHolder{ValueFromFile: flagUserFromFile}
+			}
+			if flags.Changed("user-from-command") {
+				alias.User = dataconnector.ValueHolder{ValueFromCommand: flagUserFromCommand}
+			}
+			if flags.Changed("password-from-env") {
+				alias.Password = dataconnector.ValueHolder{ValueFromEnv: flagPasswordFromEnv}
+			}
+			if flags.Changed("password-from-file") {
+				alias.Password = dataconnector.ValueHolder{ValueFromFile: flagPasswordFromFile}
+			}
+			if flags.Changed("password-from-command") {
+				alias.Password = dataconnector.ValueHolder{ValueFromCommand: flagPasswordFromCommand}
+			}
+
+			if flags.Changed("max-open-conns") {
+				alias.Options.MaxOpenConns = flagMaxOpenConns
+			}
+			if flags.Changed("connect-timeout") {
+				alias.Options.ConnectTimeout = flagConnectTimeout
+			}
+			if flags.Changed("statement-timeout") {
+				alias.Options.StatementTimeout = flagStatementTimeout
+			}
+			if flags.Changed("session-init") {
+				alias.Options.SessionInit = nil
+				for _, statement := range flagSessionInit {
+					if statement != "" {
+						alias.Options.SessionInit = append(alias.Options.SessionInit, statement)
+					}
+				}
+			}
+
+			checkStatementTimeout(alias.URL, alias.Options.StatementTimeout, err)
+
+			if flagAskPassword {
+				if alias.User.ValueFromEnv != "" || alias.User.ValueFromFile != "" || alias.User.ValueFromCommand != "" {
+					fmt.Fprintln(err, "error: cannot use --password with --user-from-env, --user-from-file or --user-from-command, use --password-from-env or specify a username")
+					os.Exit(1)
+				}
+
+				u, e2 := dburl.Parse(alias.URL)
+				if e2 != nil {
+					fmt.Fprintln(err, e2.Error())
+					os.Exit(3)
+				}
+
+				username := u.User.Username()
+				if alias.User.Value != "" {
+					username = alias.User.Value
+					u.User = url.User(username)
+					alias.URL = u.URL.String()
+				}
+				if username == "" {
+					fmt.Fprintln(err, "error: cannot use --password with empty username, use --password-from-env or specify a username")
+					os.Exit(1)
+				}
+
+				if password := askPassword(); password != "" {
+					e3 := urlbuilder.StorePassword(u, password, err)
+					if e3 != nil {
+						fmt.Fprintln(err, e3.Error())
+						os.Exit(3)
+					}
+				}
+			}
+
+			e := dataconnector.Update(storage, alias)
+			if e != nil {
+				fmt.Fprintln(err, e.Description)
+				os.Exit(1)
+			}
+
+			if alias.URL != previousURL && !flagAskPassword {
+				movePassword(alias.Name, previousURL, alias.URL, err)
+			}
+
+			fmt.Fprintln(out, "successfully updated dataconnector")
+		},
+	}
+	cmd.Flags().StringVar(&flagURL, "url", "", "Connection string of the database")
+	cmd.Flags().BoolVarP(&flagReadonly, "read-only", "r", false, "Write protection flag that prevents modification")
+	cmd.Flags().StringVarP(&flagSchema, "schema", "s", "", "Default schema to use with that dataconnector")
+	cmd.Flags().StringVarP(&flagPasswordFromEnv, "password-from-env", "P", "", "Name of environment variable containing password")
+	cmd.Flags().StringVar(&flagPasswordFromFile, "password-from-file", "", "Path of the file containing password")
+	cmd.Flags().StringVar(&flagPasswordFromCommand, "password-from-command", "", "Command printing password on its standard output")
+	cmd.Flags().StringVarP(&flagUserFromEnv, "user-from-env", "U", "", "Name of environment variable containing username")
+	cmd.Flags().StringVar(&flagUserFromFile, "user-from-file", "", "Path of the file containing username")
+	cmd.Flags().StringVar(&flagUserFromCommand, "user-from-command", "", "Command printing username on its standard output")
+	cmd.Flags().StringVarP(&flagUserValue, "user", "u", "", "Username to connect")
+	cmd.Flags().BoolVarP(&flagAskPassword, "password", "p", false, "Ask password from terminal prompt and store it")
+	cmd.Flags().IntVar(&flagMaxOpenConns, "max-open-conns", 0, "Maximum number of open connections to the database, 0 is unlimited")
+	cmd.Flags().DurationVar(&flagConnectTimeout, "connect-timeout", 0, "Timeout to open a connection (example: 10s)")
+	cmd.Flags().DurationVar(&flagStatementTimeout, "statement-timeout", 0, "Timeout of each statement, PostgreSQL only (example: 5m)")
+	cmd.Flags().StringArrayVar(&flagSessionInit, "session-init", nil, "Statement executed on each new connection, can be repeated")
+	cmd.SetOut(out)
+	cmd.SetErr(err)
+	cmd.SetIn(in)
+	return cmd
+}
+
+// movePassword moves the password stored for the previous url of the dataconnector to its new url,
+// it is copied if another dataconnector still uses the previous url.
+func movePassword(name string, previousURL string, newURL string, err io.Writer) {
+	from, e1 := dburl.Parse(previousURL)
+	if e1 != nil {
+		fmt.Fprintln(err, "warn: the stored password has not been moved to the new url: "+e1.Error())
+		return
+	}
+	to, e2 := dburl.Parse(newURL)
+	if e2 != nil {
+		fmt.Fprintln(err, e2.Error())
+		os.Exit(3)
+	}
+	keep := len(sharedWith(name, previousURL, err)) > 0
+	if _, e3 := urlbuilder.MovePassword(from, to, keep, err); e3 != nil {
+		fmt.Fprintln(err, "warn: the stored password has not been moved to the new url: "+e3.Error())
+	}
+}
@@ -19,44 +19,61 @@ package id
 
 import (
 	"fmt"
+	"io"
 	"os"
 
 	"github.com/spf13/cobra"
 
+	"github.com/cgi-fr/lino/pkg/dataconnector"
 	"github.com/cgi-fr/lino/pkg/id"
 	"github.com/cgi-fr/lino/pkg/relation"
+	"github.com/cgi-fr/lino/pkg/table"
 )
 
 var (
-	idStorage      id.Storage
-	relStorage     relation.Storage
-	idExporter     id.Exporter
-	idJSONExporter id.Storage
+	idStorageFactory     func(string) id.Storage
+	idCatalog            id.Catalog
+	relStorage           relation.Storage
+	tabStorage           table.Storage
+	idExporterFactories  map[string]func(io.Writer) id.Exporter
+	idJSONExporter       id.Storage
+	dataconnectorStorage dataconnector.Storage
+	statsReaderFactories map[string]id.StatisticsReaderFactory
 )
 
 // Inject dependencies
-func Inject(ids id.Storage, rels relation.Storage, ex id.Exporter, jSONEx id.Storage) {
-	idStorage = ids
+func Inject(idsf func(string) id.Storage, idc id.Catalog, rels relation.Storage, tabs table.Storage, exf map[string]func(io.Writer) id.Exporter, jSONEx id.Storage, dbas dataconnector.Storage, srfmap map[string]id.StatisticsReaderFactory) {
+	idStorageFactory = idsf
+	idCatalog = idc
 	relStorage = rels
-	idExporter = ex
+	tabStorage = tabs
+	idExporterFactories = exf
 	idJSONExporter = jSONEx
+	dataconnectorStorage = dbas
+	statsReaderFactories = srfmap
 }
 
 // NewCommand implements the cli id command
 func NewCommand(fullName string, err *os.File, out *os.File, in *os.File) *cobra.Command {
 	cmd := &cobra.Command{
-		Use:     "id {create,display-plan,show-graph,export,set-start-table,set-child-lookup,set-parent-lookup} [arguments ...]",
+		Use:     "id {create,refresh,list,validate,display-plan,explain,show-graph,export,set-start-table,set-child-lookup,set-parent-lookup,set-max-depth} [arguments ...]",
 		Short:   "Manage ingress descriptor",
 		Long:    "",
 		Example: fmt.Sprintf("  %[1]s id create mydatabase public.customer", fullName),
 	}
 	cmd.AddCommand(newCreateCommand(fullName, err, out, in))
+	cmd.AddCommand(newRefreshCommand(fullName, err, out, in))
+	cmd.AddCommand(newListCommand(fullName, err, out, in))
+	cmd.AddCommand(newValidateCommand(fullName, err, out, in))
 	cmd.AddCommand(newDisplayPlanCommand(fullName, err, out, in))
+	cmd.AddCommand(newExplainCommand(fullName, err, out, in))
 	cmd.AddCommand(newShowGraphCommand(fullName, err, out, in))
 	cmd.AddCommand(newExportCommand(fullName, err, out, in))
 	cmd.AddCommand(newSetStartTableCommand(fullName, err, out, in))
 	cmd.AddCommand(newSetChildLookupCommand(fullName, err, out, in))
 	cmd.AddCommand(newSetParentLookupCommand(fullName, err, out, in))
+	cmd.AddCommand(newSetMaxDepthCommand(fullName, err, out, in))
+	cmd.AddCommand(newSetReferenceTableCommand(fullName, err, out, in))
 	cmd.SetOut(out)
 	cmd.SetErr(err)
 	cmd.SetIn(in)
@@ -28,11 +28,13 @@ import (
 
 // newCreateCommand implements the cli id create command
 func newCreateCommand(fullName string, err *os.File, out *os.File, in *os.File) *cobra.Command {
+	var name string
+
 	cmd := &cobra.Command{
 		Use:     "create [Start table]",
 		Short:   "Create ingress descriptor",
 		Long:    "",
-		Example: fmt.Sprintf("  %[1]s id create public.customer", fullName),
+		Example: fmt.Sprintf("  %[1]s id create public.customer\n  %[1]s id create public.film --name film", fullName),
 		Args:    cobra.ExactArgs(1),
 		Run: func(cmd *cobra.Command, args []string) {
 			table := args[0]
@@ -45,7 +47,7 @@ func newCreateCommand(fullName string, err *os.File, out *os.File, in *os.File)
 
 			reader := infra.NewRelationReader(relations)
 
-			e := id.Create(table, reader, idStorage)
+			e := id.Create(table, reader, idStorageFactory(name))
 			if e != nil {
 				fmt.Fprintln(err, e.Description)
 				os.Exit(1)
@@ -54,6 +56,7 @@ func newCreateCommand(fullName string, err *os.File, out *os.File, in *os.File)
 			fmt.Fprintln(out, "successfully created ingress descriptor")
 		},
 	}
+	cmd.Flags().StringVar(&name, "name", "", "Store the ingress descriptor with this name in the ingress directory")
 	cmd.SetOut(out)
 	cmd.SetErr(err)
 	cmd.SetIn(in)
@@ -18,35 +18,133 @@
 package id
 
 import (
+	"encoding/json"
 	"fmt"
+	"io"
 	"os"
+	"sort"
 
 	"github.com/cgi-fr/lino/pkg/id"
 	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
 )
 
+// PlanVersion is the version of the JSON/YAML structure of the display-plan output
+const PlanVersion = "v1"
+
+// displayPlan is the JSON/YAML output of the display-plan command
+type displayPlan struct {
+	Version string        `json:"version" yaml:"version"`
+	Steps   []displayStep `json:"steps" yaml:"steps"`
+}
+
+// displayStep is a step of the puller plan
+type displayStep struct {
+	Index        uint       `json:"index" yaml:"index"`
+	Entry        string     `json:"entry" yaml:"entry"`
+	Follow       string     `json:"follow" yaml:"follow"`
+	Direction    string     `json:"direction" yaml:"direction"`
+	PreviousStep uint       `json:"previousStep" yaml:"previousStep"`
+	Tables       []string   `json:"tables" yaml:"tables"`
+	Cycles       [][]string `json:"cycles" yaml:"cycles"`
+}
+
 // newDisplayPlanCommand implements the cli id display-plan command
 func newDisplayPlanCommand(fullName string, err *os.File, out *os.File, in *os.File) *cobra.Command {
+	var name string
+	var output string
+
 	cmd := &cobra.Command{
 		Use:     "display-plan",
 		Short:   "Show ingress descriptor steps",
 		Long:    "",
-		Example: fmt.Sprintf("  %[1]s id display-plan", fullName),
+		Example: fmt.Sprintf("  %[1]s id display-plan\n  %[1]s id display-plan --output json", fullName),
 		Args:    cobra.NoArgs,
 		Run: func(cmd *cobra.Command, args []string) {
-			result, e := id.GetPullerPlan(idStorage)
+			result, e := id.GetPullerPlan(idStorageFactory(name))
 			if e != nil {
 				fmt.Fprintln(err, e.Description)
 				os.Exit(1)
 			}
 
-			for i := uint(0); i < result.Len(); i++ {
-				fmt.Fprintln(out, result.Step(i))
+			if e2 := printPlan(result, output, out); e2 != nil {
+				fmt.Fprintln(err, e2.Error())
+				os.Exit(1)
 			}
 		},
 	}
+	cmd.Flags().StringVar(&name, "id", "", "Name of the ingress descriptor (default ingress-descriptor.yaml)")
+	cmd.Flags().StringVarP(&output, "output", "o", "text", "Output format (text|json|yaml)")
 	cmd.SetOut(out)
 	cmd.SetErr(err)
 	cmd.SetIn(in)
 	return cmd
 }
+
+// printPlan writes the puller plan in the output format (text, json or yaml)
+func printPlan(plan id.PullerPlan, output string, out io.Writer) error {
+	switch output {
+	case "text":
+		for i := uint(0); i < plan.Len(); i++ {
+			fmt.Fprintln(out, plan.Step(i))
+		}
+	case "json":
+		res, err := json.MarshalIndent(toDisplayPlan(plan), "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(out, string(res))
+	case "yaml":
+		res, err := yaml.Marshal(toDisplayPlan(plan))
+		if err != nil {
+			return err
+		}
+		fmt.Fprint(out, string(res))
+	default:
+		return fmt.Errorf("unknown output format %s", output)
+	}
+	return nil
+}
+
+func toDisplayPlan(plan id.PullerPlan) displayPlan {
+	result := displayPlan{Version: PlanVersion, Steps: []displayStep{}}
+
+	for i := uint(0); i < plan.Len(); i++ {
+		step := plan.Step(i)
+
+		ds := displayStep{
+			Index:        step.Index(),
+			Entry:        step.Entry().Name(),
+			PreviousStep: step.PreviousStep(),
+			Tables:       []string{},
+			Cycles:       [][]string{},
+		}
+
+		if step.PreviousStep() != 0 {
+			ds.Follow = step.Following().Name()
+			if step.Following().Child().Name() == step.Entry().Name() {
+				ds.Direction = "child"
+			} else {
+				ds.Direction = "parent"
+			}
+		}
+
+		for t := uint(0); t < step.Tables().Len(); t++ {
+			ds.Tables = append(ds.Tables, step.Tables().Table(t).Name())
+		}
+		sort.Strings(ds.Tables)
+
+		for c := uint(0); c < step.Cycles().Len(); c++ {
+			cycle := step.Cycles().Cycle(c)
+			relations := []string{}
+			for r := uint(0); r < cycle.Len(); r++ {
+				relations = append(relations, cycle.Relation(r).Name())
+			}
+			ds.Cycles = append(ds.Cycles, relations)
+		}
+
+		result.Steps = append(result.Steps, ds)
+	}
+
+	return result
+}
@@ -0,0 +1,95 @@
+// Copyright (C) 2021 CGI France
+//
+// This file is part of LINO.
+//
+// LINO is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// LINO is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with LINO.  If not, see <http://www.gnu.org/licenses/>.
+
+package id
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/cgi-fr/lino/pkg/id"
+	"github.com/stretchr/testify/assert"
+)
+
+// testPlan pulls the customers, then their orders and loops over the orders replacing other orders
+func testPlan() id.PullerPlan {
+	customer := id.NewTable("customer")
+	order := id.NewTable("order")
+	item := id.NewTable("item")
+
+	orders := id.NewIngressRelation(id.NewRelation("order_customer", customer, order), false, true)
+	replaces := id.NewIngressRelation(id.NewRelation("order_replaces", order, order), true, true)
+	items := id.NewIngressRelation(id.NewRelation("item_order", order, item), false, true)
+
+	relations := id.NewIngressRelationList([]id.IngressRelation{orders, replaces, items})
+	step1 := id.NewStep(1, customer, nil, relations, id.NewTableList([]id.Table{customer}), id.NewCycleList(nil), 0)
+	step2 := id.NewStep(2, order, orders, relations, id.NewTableList([]id.Table{order, item}), id.NewCycleList([]id.IngressRelationList{id.NewIngressRelationList([]id.IngressRelation{replaces})}), 1)
+	step3 := id.NewStep(3, customer, orders, relations, id.NewTableList([]id.Table{customer}), id.NewCycleList(nil), 2)
+
+	return id.NewPullerPlan([]id.Step{step1, step2, step3}, relations, id.NewTableList([]id.Table{customer, order, item}))
+}
+
+func TestDisplayPlan(t *testing.T) {
+	plan := toDisplayPlan(testPlan())
+
+	assert.Equal(t, displayPlan{
+		Version: PlanVersion,
+		Steps: []displayStep{
+			{Index: 1, Entry: "customer", Tables: []string{"customer"}, Cycles: [][]string{}},
+			{Index: 2, Entry: "order", Follow: "order_customer", Direction: "child", PreviousStep: 1, Tables: []string{"item", "order"}, Cycles: [][]string{{"order_replaces"}}},
+			{Index: 3, Entry: "customer", Follow: "order_customer", Direction: "parent", PreviousStep: 2, Tables: []string{"customer"}, Cycles: [][]string{}},
+		},
+	}, plan)
+}
+
+func TestPrintPlanText(t *testing.T) {
+	out := &strings.Builder{}
+	assert.Nil(t, printPlan(testPlan(), "text", out))
+	assert.Equal(t, `step 1 - pull rows from customer
+step 2 - pull rows from order following →order_customer relationship for rows pulled at step 1, then follow ↔order_replaces relationship (round trip)
+step 3 - pull rows from customer following →order_customer relationship for rows pulled at step 2
+`, out.String())
+}
+
+func TestPrintPlanJSON(t *testing.T) {
+	out := &strings.Builder{}
+	assert.Nil(t, printPlan(testPlan(), "json", out))
+	assert.JSONEq(t, `{"version":"v1","steps":[
+		{"index":1,"entry":"customer","follow":"","direction":"","previousStep":0,"tables":["customer"],"cycles":[]},
+		{"index":2,"entry":"order","follow":"order_customer","direction":"child","previousStep":1,"tables":["item","order"],"cycles":[["order_replaces"]]},
+		{"index":3,"entry":"customer","follow":"order_customer","direction":"parent","previousStep":2,"tables":["customer"],"cycles":[]}
+	]}`, out.String())
+}
+
+func TestPrintPlanYAML(t *testing.T) {
+	out := &strings.Builder{}
+	assert.Nil(t, printPlan(testPlan(), "yaml", out))
+	assert.YAMLEq(t, `version: v1
+steps:
+  - {index: 1, entry: customer, follow: "", direction: "", previousStep: 0, tables: [customer], cycles: []}
+  - {index: 2, entry: order, follow: order_customer, direction: child, previousStep: 1, tables: [item, order], cycles: [[order_replaces]]}
+  - {index: 3, entry: customer, follow: order_customer, direction: parent, previousStep: 2, tables: [customer], cycles: []}
+`, out.String())
+}
+
+func TestPrintPlanUnknownFormat(t *testing.T) {
+	out := &strings.Builder{}
+	err := printPlan(testPlan(), "xml", out)
+	assert.NotNil(t, err)
+	assert.Equal(t, "unknown output format xml", err.Error())
+	assert.Empty(t, out.String())
+}
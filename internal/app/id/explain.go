@@ -0,0 +1,147 @@
+// Copyright (C) 2021 CGI France
+//
+// This file is part of LINO.
+//
+// LINO is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// LINO is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with LINO.  If not, see <http://www.gnu.org/licenses/>.
+
+package id
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/cgi-fr/lino/internal/app/urlbuilder"
+	infra "github.com/cgi-fr/lino/internal/infra/id"
+	"github.com/cgi-fr/lino/pkg/dataconnector"
+	"github.com/cgi-fr/lino/pkg/id"
+	"github.com/spf13/cobra"
+)
+
+// jsonExplanation is the JSON output of the explain command
+type jsonExplanation struct {
+	Steps []jsonStepEstimate `json:"steps"`
+	Rows  float64            `json:"rows"`
+	Bytes float64            `json:"bytes"`
+}
+
+type jsonStepEstimate struct {
+	Index     uint     `json:"index"`
+	Entry     string   `json:"entry"`
+	Follow    string   `json:"follow,omitempty"`
+	Direction string   `json:"direction,omitempty"`
+	FanOut    float64  `json:"fanOut"`
+	Rows      float64  `json:"rows"`
+	Bytes     float64  `json:"bytes"`
+	Warnings  []string `json:"warnings"`
+}
+
+// newExplainCommand implements the cli id explain command
+func newExplainCommand(fullName string, err *os.File, out *os.File, in *os.File) *cobra.Command {
+	var name string
+	var limit uint
+	var filter map[string]string
+	var maxFanOut float64
+	var output string
+
+	cmd := &cobra.Command{
+		Use:     "explain [DB Alias Name]",
+		Short:   "Estimate the rows pulled by each step from database statistics",
+		Long:    "",
+		Example: fmt.Sprintf("  %[1]s id explain mydatabase --limit 100\n  %[1]s id explain mydatabase --filter customer_id=1 --output json", fullName),
+		Args:    cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			alias, e1 := dataconnector.Get(dataconnectorStorage, args[0])
+			if e1 != nil {
+				fmt.Fprintln(err, e1.Description)
+				os.Exit(1)
+			}
+
+			if alias == nil {
+				fmt.Fprintln(err, "no dataconnector named "+args[0])
+				os.Exit(1)
+			}
+
+			u := urlbuilder.BuildURL(alias, err)
+
+			factory, ok := statsReaderFactories[u.Unaliased]
+			if !ok {
+				fmt.Fprintln(err, "no statistics reader found for database type")
+				os.Exit(1)
+			}
+
+			relations, e2 := relStorage.List()
+			if e2 != nil {
+				fmt.Fprintln(err, e2.Description)
+				os.Exit(1)
+			}
+
+			columns := []string{}
+			for column := range filter {
+				columns = append(columns, column)
+			}
+			sort.Strings(columns)
+
+			explanation, e3 := id.Explain(idStorageFactory(name), infra.NewRelationKeysReader(relations), factory.New(u.URL.String(), alias.Schema, alias.Options), limit, columns, maxFanOut)
+			if e3 != nil {
+				fmt.Fprintln(err, e3.Description)
+				os.Exit(1)
+			}
+
+			switch output {
+			case "json":
+				result := jsonExplanation{Steps: []jsonStepEstimate{}, Rows: explanation.Rows, Bytes: explanation.Bytes}
+				for _, s := range explanation.Steps {
+					result.Steps = append(result.Steps, jsonStepEstimate(s))
+				}
+				e4 := json.NewEncoder(out).Encode(result)
+				if e4 != nil {
+					fmt.Fprintln(err, e4.Error())
+					os.Exit(1)
+				}
+			case "text":
+				w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+				fmt.Fprintln(w, "STEP\tENTRY\tFOLLOW\tFAN-OUT\tROWS\tBYTES")
+				for _, s := range explanation.Steps {
+					follow := "-"
+					if s.Follow != "" {
+						follow = fmt.Sprintf("%s (%s)", s.Follow, s.Direction)
+					}
+					fmt.Fprintf(w, "%d\t%s\t%s\t%.1f\t%.0f\t%.0f\n", s.Index, s.Entry, follow, s.FanOut, s.Rows, s.Bytes)
+				}
+				fmt.Fprintf(w, "TOTAL\t\t\t\t%.0f\t%.0f\n", explanation.Rows, explanation.Bytes)
+				w.Flush()
+				for _, s := range explanation.Steps {
+					for _, warning := range s.Warnings {
+						fmt.Fprintf(out, "warning: step %d (%s): %s\n", s.Index, s.Entry, warning)
+					}
+				}
+			default:
+				fmt.Fprintf(err, "unknown output format %s\n", output)
+				os.Exit(1)
+			}
+		},
+	}
+	cmd.Flags().StringVar(&name, "id", "", "Name of the ingress descriptor (default ingress-descriptor.yaml)")
+	cmd.Flags().UintVarP(&limit, "limit", "l", 1, "limit the number of results")
+	cmd.Flags().StringToStringVarP(&filter, "filter", "f", map[string]string{}, "filter of start table")
+	cmd.Flags().Float64Var(&maxFanOut, "max-fanout", 100, "Flag steps whose fan-out is greater")
+	cmd.Flags().StringVarP(&output, "output", "o", "text", "Output format (text|json)")
+	cmd.SetOut(out)
+	cmd.SetErr(err)
+	cmd.SetIn(in)
+	return cmd
+}
@@ -26,6 +26,8 @@ import (
 
 // newExportCommand implements the cli id export command
 func newExportCommand(fullName string, err *os.File, out *os.File, in *os.File) *cobra.Command {
+	var name string
+
 	cmd := &cobra.Command{
 		Use:     "export",
 		Short:   "Export content of ingress descriptor in JSON format to stdout",
@@ -33,7 +35,7 @@ func newExportCommand(fullName string, err *os.File, out *os.File, in *os.File)
 		Example: fmt.Sprintf("  %[1]s id export", fullName),
 		Args:    cobra.NoArgs,
 		Run: func(cmd *cobra.Command, args []string) {
-			id, e := idStorage.Read()
+			id, e := idStorageFactory(name).Read()
 			if e != nil {
 				fmt.Fprintln(err, e.Description)
 				os.Exit(1)
@@ -45,6 +47,7 @@ func newExportCommand(fullName string, err *os.File, out *os.File, in *os.File)
 			}
 		},
 	}
+	cmd.Flags().StringVar(&name, "id", "", "Name of the ingress descriptor (default ingress-descriptor.yaml)")
 	cmd.SetOut(out)
 	cmd.SetErr(err)
 	cmd.SetIn(in)
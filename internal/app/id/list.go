@@ -0,0 +1,52 @@
+// Copyright (C) 2021 CGI France
+//
+// This file is part of LINO.
+//
+// LINO is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// LINO is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with LINO.  If not, see <http://www.gnu.org/licenses/>.
+
+package id
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/cgi-fr/lino/pkg/id"
+	"github.com/spf13/cobra"
+)
+
+// newListCommand implements the cli id list command
+func newListCommand(fullName string, err *os.File, out *os.File, in *os.File) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "list",
+		Short:   "List named ingress descriptors",
+		Long:    "",
+		Example: fmt.Sprintf("  %[1]s id list", fullName),
+		Args:    cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			names, e := id.List(idCatalog)
+			if e != nil {
+				fmt.Fprintln(err, e.Description)
+				os.Exit(1)
+			}
+
+			for _, name := range names {
+				fmt.Fprintln(out, name)
+			}
+		},
+	}
+	cmd.SetOut(out)
+	cmd.SetErr(err)
+	cmd.SetIn(in)
+	return cmd
+}
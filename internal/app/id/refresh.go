@@ -0,0 +1,63 @@
+// Copyright (C) 2021 CGI France
+//
+// This file is part of LINO.
+//
+// LINO is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// LINO is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with LINO.  If not, see <http://www.gnu.org/licenses/>.
+
+package id
+
+import (
+	"fmt"
+	"os"
+
+	infra "github.com/cgi-fr/lino/internal/infra/id"
+	"github.com/cgi-fr/lino/pkg/id"
+	"github.com/spf13/cobra"
+)
+
+// newRefreshCommand implements the cli id refresh command
+func newRefreshCommand(fullName string, err *os.File, out *os.File, in *os.File) *cobra.Command {
+	var name string
+
+	cmd := &cobra.Command{
+		Use:     "refresh",
+		Short:   "Merge ingress descriptor with the current relations, keeping lookups",
+		Long:    "",
+		Example: fmt.Sprintf("  %[1]s id refresh\n  %[1]s id refresh --id film", fullName),
+		Args:    cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			relations, e1 := relStorage.List()
+			if e1 != nil {
+				fmt.Fprintln(err, e1.Description)
+				os.Exit(1)
+			}
+
+			changes, e2 := id.Refresh(infra.NewRelationReader(relations), idStorageFactory(name))
+			if e2 != nil {
+				fmt.Fprintln(err, e2.Description)
+				os.Exit(1)
+			}
+
+			for _, change := range changes {
+				fmt.Fprintf(out, "%s: %s\n", change.Kind, change.Message)
+			}
+			fmt.Fprintf(out, "%d change(s)\n", len(changes))
+		},
+	}
+	cmd.Flags().StringVar(&name, "id", "", "Name of the ingress descriptor (default ingress-descriptor.yaml)")
+	cmd.SetOut(out)
+	cmd.SetErr(err)
+	cmd.SetIn(in)
+	return cmd
+}
@@ -27,6 +27,8 @@ import (
 
 // newSetChildLookupCommand implements the cli id set-child-lookup command
 func newSetChildLookupCommand(fullName string, err *os.File, out *os.File, in *os.File) *cobra.Command {
+	var name string
+
 	cmd := &cobra.Command{
 		Use:     "set-child-lookup [relation] [true|false]",
 		Short:   "set child lookup flag for relation [relation] in ingress descriptor",
@@ -41,7 +43,7 @@ func newSetChildLookupCommand(fullName string, err *os.File, out *os.File, in *o
 				os.Exit(1)
 			}
 
-			e := id.SetChildLookup(relation, flag, idStorage)
+			e := id.SetChildLookup(relation, flag, idStorageFactory(name))
 			if e != nil {
 				fmt.Fprintln(err, e.Description)
 				os.Exit(1)
@@ -50,6 +52,7 @@ func newSetChildLookupCommand(fullName string, err *os.File, out *os.File, in *o
 			fmt.Fprintf(out, "successfully update relation %s in ingress descriptor\n", relation)
 		},
 	}
+	cmd.Flags().StringVar(&name, "id", "", "Name of the ingress descriptor (default ingress-descriptor.yaml)")
 	cmd.SetOut(out)
 	cmd.SetErr(err)
 	cmd.SetIn(in)
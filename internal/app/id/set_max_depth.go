@@ -0,0 +1,61 @@
+// Copyright (C) 2021 CGI France
+//
+// This file is part of LINO.
+//
+// LINO is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// LINO is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with LINO.  If not, see <http://www.gnu.org/licenses/>.
+
+package id
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/cgi-fr/lino/pkg/id"
+	"github.com/spf13/cobra"
+)
+
+// newSetMaxDepthCommand implements the cli id set-max-depth command
+func newSetMaxDepthCommand(fullName string, err *os.File, out *os.File, in *os.File) *cobra.Command {
+	var name string
+
+	cmd := &cobra.Command{
+		Use:     "set-max-depth [relation] [depth]",
+		Short:   "set the maximum number of times relation [relation] is followed in a cycle (0 for no limit)",
+		Long:    "",
+		Example: fmt.Sprintf("  %[1]s id set-max-depth staff_store_id_fkey 2", fullName),
+		Args:    cobra.ExactArgs(2),
+		Run: func(cmd *cobra.Command, args []string) {
+			relation := args[0]
+			depth, e1 := strconv.ParseUint(args[1], 10, 32)
+			if e1 != nil {
+				fmt.Fprintln(err, "depth must be a positive integer")
+				os.Exit(1)
+			}
+
+			e := id.SetMaxDepth(relation, uint(depth), idStorageFactory(name))
+			if e != nil {
+				fmt.Fprintln(err, e.Description)
+				os.Exit(1)
+			}
+
+			fmt.Fprintf(out, "successfully update relation %s in ingress descriptor\n", relation)
+		},
+	}
+	cmd.Flags().StringVar(&name, "id", "", "Name of the ingress descriptor (default ingress-descriptor.yaml)")
+	cmd.SetOut(out)
+	cmd.SetErr(err)
+	cmd.SetIn(in)
+	return cmd
+}
@@ -27,6 +27,8 @@ import (
 
 // newSetParentLookupCommand implements the cli id set-parent-lookup command
 func newSetParentLookupCommand(fullName string, err *os.File, out *os.File, in *os.File) *cobra.Command {
+	var name string
+
 	cmd := &cobra.Command{
 		Use:     "set-parent-lookup [relation] [true|false]",
 		Short:   "set parent lookup flag for relation [relation] in ingress descriptor",
@@ -41,7 +43,7 @@ func newSetParentLookupCommand(fullName string, err *os.File, out *os.File, in *
 				os.Exit(1)
 			}
 
-			e := id.SetParentLookup(relation, flag, idStorage)
+			e := id.SetParentLookup(relation, flag, idStorageFactory(name))
 			if e != nil {
 				fmt.Fprintln(err, e.Description)
 				os.Exit(1)
@@ -50,6 +52,7 @@ func newSetParentLookupCommand(fullName string, err *os.File, out *os.File, in *
 			fmt.Fprintf(out, "successfully update relation %s in ingress descriptor\n", relation)
 		},
 	}
+	cmd.Flags().StringVar(&name, "id", "", "Name of the ingress descriptor (default ingress-descriptor.yaml)")
 	cmd.SetOut(out)
 	cmd.SetErr(err)
 	cmd.SetIn(in)
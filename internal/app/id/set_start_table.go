@@ -27,6 +27,8 @@ import (
 
 // newSetStartTableCommand implements the cli id set-start-table command
 func newSetStartTableCommand(fullName string, err *os.File, out *os.File, in *os.File) *cobra.Command {
+	var name string
+
 	cmd := &cobra.Command{
 		Use:     "set-start-table [Start table]",
 		Short:   "set new start table ingress descriptor",
@@ -36,7 +38,7 @@ func newSetStartTableCommand(fullName string, err *os.File, out *os.File, in *os
 		Run: func(cmd *cobra.Command, args []string) {
 			table := args[0]
 
-			e := id.SetStartTable(id.NewTable(table), idStorage)
+			e := id.SetStartTable(id.NewTable(table), idStorageFactory(name))
 			if e != nil {
 				fmt.Fprintln(err, e.Description)
 				os.Exit(1)
@@ -45,6 +47,7 @@ func newSetStartTableCommand(fullName string, err *os.File, out *os.File, in *os
 			fmt.Fprintln(out, "successfully update start table ingress descriptor")
 		},
 	}
+	cmd.Flags().StringVar(&name, "id", "", "Name of the ingress descriptor (default ingress-descriptor.yaml)")
 	cmd.SetOut(out)
 	cmd.SetErr(err)
 	cmd.SetIn(in)
@@ -19,32 +19,83 @@ package id
 
 import (
 	"fmt"
+	"io"
 	"os"
+	"path/filepath"
+	"sort"
+	"strings"
 
+	infra "github.com/cgi-fr/lino/internal/infra/id"
 	"github.com/cgi-fr/lino/pkg/id"
 	"github.com/spf13/cobra"
 )
 
 // newShowGraphCommand implements the cli id show-graph command
 func newShowGraphCommand(fullName string, err *os.File, out *os.File, in *os.File) *cobra.Command {
+	var name string
+	var format string
+	var outputFile string
+	var open bool
+
 	cmd := &cobra.Command{
-		Use:     "show-graph",
-		Short:   "Show ingress descriptor graph",
-		Long:    "",
-		Example: fmt.Sprintf("  %[1]s id show-graph", fullName),
-		Args:    cobra.NoArgs,
+		Use:   "show-graph",
+		Short: "Show ingress descriptor graph",
+		Long:  "",
+		Example: fmt.Sprintf("  %[1]s id show-graph\n", fullName) +
+			fmt.Sprintf("  %[1]s id show-graph --format mermaid -o graph.mmd\n", fullName) +
+			fmt.Sprintf("  %[1]s id show-graph --format svg --open", fullName),
+		Args: cobra.NoArgs,
 		Run: func(cmd *cobra.Command, args []string) {
-			e := id.Export(idStorage, idExporter)
+			exporterFactory, ok := idExporterFactories[format]
+			if !ok {
+				fmt.Fprintf(err, "unknown format %s, expected one of %s\n", format, strings.Join(exportFormats(), ", "))
+				os.Exit(1)
+			}
+
+			if outputFile == "" && open {
+				outputFile = filepath.Join(os.TempDir(), "lino-graph-export."+format)
+			}
+
+			var writer io.Writer = out
+			if outputFile != "" {
+				file, e1 := os.Create(outputFile)
+				if e1 != nil {
+					fmt.Fprintln(err, e1.Error())
+					os.Exit(1)
+				}
+				defer file.Close()
+				writer = file
+			}
+
+			e := id.Export(idStorageFactory(name), exporterFactory(writer))
 			if e != nil {
 				fmt.Fprintln(err, e.Description)
 				os.Exit(1)
 			}
 
-			fmt.Fprintln(out, "success")
+			if open {
+				if e2 := infra.OpenBrowser(outputFile); e2 != nil {
+					fmt.Fprintln(err, e2.Error())
+					os.Exit(1)
+				}
+			}
 		},
 	}
+	cmd.Flags().StringVar(&name, "id", "", "Name of the ingress descriptor (default ingress-descriptor.yaml)")
+	cmd.Flags().StringVar(&format, "format", "dot", "Output format ("+strings.Join(exportFormats(), "|")+")")
+	cmd.Flags().StringVarP(&outputFile, "output-file", "o", "", "Write the graph in file instead of stdout")
+	cmd.Flags().BoolVar(&open, "open", false, "Open the graph with the default application")
 	cmd.SetOut(out)
 	cmd.SetErr(err)
 	cmd.SetIn(in)
 	return cmd
 }
+
+func exportFormats() []string {
+	formats := []string{}
+	for format := range idExporterFactories {
+		formats = append(formats, format)
+	}
+	sort.Strings(formats)
+	return formats
+}
@@ -0,0 +1,121 @@
+// Copyright (C) 2021 CGI France
+//
+// This file is part of LINO.
+//
+// LINO is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// LINO is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with LINO.  If not, see <http://www.gnu.org/licenses/>.
+
+package id
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	infra "github.com/cgi-fr/lino/internal/infra/id"
+	"github.com/cgi-fr/lino/pkg/id"
+	"github.com/spf13/cobra"
+)
+
+// jsonValidation is the JSON output of the validate command
+type jsonValidation struct {
+	Valid  bool        `json:"valid"`
+	Issues []jsonIssue `json:"issues"`
+}
+
+type jsonIssue struct {
+	Level    string `json:"level"`
+	Kind     string `json:"kind"`
+	Relation string `json:"relation,omitempty"`
+	Table    string `json:"table,omitempty"`
+	Message  string `json:"message"`
+}
+
+// newValidateCommand implements the cli id validate command
+func newValidateCommand(fullName string, err *os.File, out *os.File, in *os.File) *cobra.Command {
+	var name string
+	var output string
+	var strict bool
+
+	cmd := &cobra.Command{
+		Use:     "validate",
+		Short:   "Check ingress descriptor against relations and tables",
+		Long:    "Exit with status 2 if an error is found (or a warning with --strict)",
+		Example: fmt.Sprintf("  %[1]s id validate\n  %[1]s id validate --id film --output json --strict", fullName),
+		Args:    cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			relations, e1 := relStorage.List()
+			if e1 != nil {
+				fmt.Fprintln(err, e1.Description)
+				os.Exit(1)
+			}
+
+			tables, e2 := tabStorage.List()
+			if e2 != nil {
+				fmt.Fprintln(err, e2.Description)
+				os.Exit(1)
+			}
+
+			issues, e3 := id.Validate(idStorageFactory(name), infra.NewRelationReader(relations), infra.NewPrimaryKeyReader(tables))
+			if e3 != nil {
+				fmt.Fprintln(err, e3.Description)
+				os.Exit(1)
+			}
+
+			valid := true
+			errors, warnings := 0, 0
+			for _, issue := range issues {
+				if issue.Level == id.IssueError {
+					errors++
+				} else {
+					warnings++
+				}
+				if issue.Level == id.IssueError || strict {
+					valid = false
+				}
+			}
+
+			switch output {
+			case "json":
+				result := jsonValidation{Valid: valid, Issues: []jsonIssue{}}
+				for _, issue := range issues {
+					result.Issues = append(result.Issues, jsonIssue{string(issue.Level), issue.Kind, issue.Relation, issue.Table, issue.Message})
+				}
+				e4 := json.NewEncoder(out).Encode(result)
+				if e4 != nil {
+					fmt.Fprintln(err, e4.Error())
+					os.Exit(1)
+				}
+			case "text":
+				for _, issue := range issues {
+					fmt.Fprintf(out, "%s: %s (%s)\n", issue.Level, issue.Message, issue.Kind)
+				}
+				fmt.Fprintf(out, "%d error(s), %d warning(s)\n", errors, warnings)
+			default:
+				fmt.Fprintf(err, "unknown output format %s\n", output)
+				os.Exit(1)
+			}
+
+			if !valid {
+				os.Exit(2)
+			}
+		},
+	}
+	cmd.Flags().StringVar(&name, "id", "", "Name of the ingress descriptor (default ingress-descriptor.yaml)")
+	cmd.Flags().StringVarP(&output, "output", "o", "text", "Output format (text|json)")
+	cmd.Flags().BoolVar(&strict, "strict", false, "Warnings make the validation fail")
+	cmd.SetOut(out)
+	cmd.SetErr(err)
+	cmd.SetIn(in)
+	return cmd
+}
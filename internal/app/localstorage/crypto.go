@@ -0,0 +1,134 @@
+// Copyright (C) 2021 CGI France
+//
+// This file is part of LINO.
+//
+// LINO is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// LINO is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with LINO.  If not, see <http://www.gnu.org/licenses/>.
+
+package localstorage
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/pbkdf2"
+)
+
+const (
+	// Algorithm used to encrypt the secrets.
+	Algorithm string = "aes-256-gcm"
+	// KDF used to derive the encryption key from the master password.
+	KDF string = "argon2id"
+	// Iterations of the KDF for new storages.
+	Iterations int = 3
+	// Memory in KiB used by the KDF for new storages.
+	Memory uint32 = 64 * 1024
+	// Threads used by the KDF for new storages.
+	Threads uint8 = 4
+
+	// PBKDF2 is the KDF of the storages written by previous versions, still read.
+	PBKDF2 string = "pbkdf2-sha256"
+
+	// weaker parameters are rejected when reading a storage
+	minIterations       = 2
+	minMemory           = 19 * 1024
+	minPBKDF2Iterations = 600000
+
+	keyLength  = 32
+	saltLength = 16
+	// checkValue is encrypted in the storage to verify the master password.
+	checkValue = "lino"
+)
+
+func newSalt() (string, error) {
+	salt := make([]byte, saltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(salt), nil
+}
+
+func deriveKey(password string, encryption *YAMLEncryption) ([]byte, error) {
+	if encryption.Algorithm != Algorithm {
+		return nil, fmt.Errorf("%w : unsupported encryption (%s, %s)", ErrInvalidStorage, encryption.Algorithm, encryption.KDF)
+	}
+
+	salt, err := base64.StdEncoding.DecodeString(encryption.Salt)
+	if err != nil || len(salt) < saltLength {
+		return nil, fmt.Errorf("%w : invalid salt", ErrInvalidStorage)
+	}
+
+	switch encryption.KDF {
+	case KDF:
+		if encryption.Iterations < minIterations || encryption.Memory < minMemory || encryption.Threads < 1 {
+			return nil, fmt.Errorf("%w : weak key derivation (%d iterations, %d KiB, %d threads)", ErrInvalidStorage, encryption.Iterations, encryption.Memory, encryption.Threads)
+		}
+		return argon2.IDKey([]byte(password), salt, uint32(encryption.Iterations), encryption.Memory, encryption.Threads, keyLength), nil
+	case PBKDF2:
+		if encryption.Iterations < minPBKDF2Iterations {
+			return nil, fmt.Errorf("%w : weak key derivation (%d iterations)", ErrInvalidStorage, encryption.Iterations)
+		}
+		return pbkdf2.Key([]byte(password), salt, encryption.Iterations, keyLength, sha256.New), nil
+	default:
+		return nil, fmt.Errorf("%w : unsupported encryption (%s, %s)", ErrInvalidStorage, encryption.Algorithm, encryption.KDF)
+	}
+}
+
+// encrypt the plaintext, the additional data is authenticated but not encrypted.
+func encrypt(key []byte, plaintext string, additionalData string) (string, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+
+	sealed := aead.Seal(nonce, nonce, []byte(plaintext), []byte(additionalData))
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// decrypt the ciphertext, it fails if the key or the additional data do not match.
+func decrypt(key []byte, ciphertext string, additionalData string) (string, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return "", err
+	}
+
+	sealed, err := base64.StdEncoding.DecodeString(ciphertext)
+	if err != nil || len(sealed) < aead.NonceSize() {
+		return "", fmt.Errorf("%w : invalid encrypted secret", ErrInvalidStorage)
+	}
+
+	plaintext, err := aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], []byte(additionalData))
+	if err != nil {
+		return "", ErrMasterPassword
+	}
+
+	return string(plaintext), nil
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
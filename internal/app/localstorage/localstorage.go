@@ -30,7 +30,9 @@ import (
 
 const (
 	// Version of the YAML strcuture.
-	Version string = "v1"
+	Version string = "v2"
+	// PlaintextVersion of the YAML structure, before the secrets were encrypted.
+	PlaintextVersion string = "v1"
 	// Path of the credentials storage file relative to the current user's home directory.
 	FilePath string = ".lino"
 	// Name of the credentials storage file.
@@ -39,13 +41,33 @@ const (
 
 type YAMLCredentialsStore struct {
 	Version         string            `yaml:"version"`
+	Encryption      *YAMLEncryption   `yaml:"encryption,omitempty"`
 	CredentialsList []YAMLCredentials `yaml:"credentials,omitempty"`
 }
 
+// YAMLEncryption holds the parameters to derive the key from the master password.
+type YAMLEncryption struct {
+	Algorithm  string `yaml:"algorithm"`
+	KDF        string `yaml:"kdf"`
+	Iterations int    `yaml:"iterations"`
+	Memory     uint32 `yaml:"memory,omitempty"`
+	Threads    uint8  `yaml:"threads,omitempty"`
+	Salt       string `yaml:"salt"`
+	Check      string `yaml:"check"`
+}
+
 type YAMLCredentials struct {
-	ServerURL string `yaml:"serverURL"`
-	Username  string `yaml:"username"`
-	Secret    string `yaml:"secret"`
+	ServerURL       string `yaml:"serverURL"`
+	Username        string `yaml:"username"`
+	Secret          string `yaml:"secret,omitempty"`
+	EncryptedSecret string `yaml:"encryptedSecret,omitempty"`
+}
+
+// Entry describes stored credentials without the secret.
+type Entry struct {
+	ServerURL string
+	Username  string
+	Encrypted bool
 }
 
 // Store credentials in a local file.
@@ -66,9 +88,101 @@ func Read(serverURL string) (*credentials.Credentials, error) {
 	if err != nil {
 		return nil, err
 	}
+	if username == "" && secret == "" {
+		return nil, credentials.NewErrCredentialsNotFound()
+	}
 	return &credentials.Credentials{ServerURL: serverURL, Username: username, Secret: secret}, nil
 }
 
+func Delete(serverURL string) error {
+	h := YAMLStorage{}
+	return h.Delete(serverURL)
+}
+
+// Entries lists the stored credentials, the master password is not needed.
+func Entries() ([]Entry, error) {
+	store, err := readFile()
+	if err != nil {
+		return nil, err
+	}
+
+	result := []Entry{}
+	for _, credential := range store.CredentialsList {
+		result = append(result, Entry{
+			ServerURL: credential.ServerURL,
+			Username:  credential.Username,
+			Encrypted: credential.EncryptedSecret != "",
+		})
+	}
+
+	return result, nil
+}
+
+// Migrate encrypts the plaintext secrets and returns the number of migrated credentials.
+func Migrate() (int, error) {
+	store, err := readFile()
+	if err != nil {
+		return 0, err
+	}
+
+	key, err := unlock(store)
+	if err != nil {
+		return 0, err
+	}
+
+	count, err := encryptAll(store, key)
+	if err != nil {
+		return 0, err
+	}
+
+	return count, writeFile(store)
+}
+
+// Rotate encrypts all secrets with a new master password, read from NewMasterKeyEnv or asked on the terminal.
+func Rotate() error {
+	store, err := readFile()
+	if err != nil {
+		return err
+	}
+
+	if store.Encryption != nil {
+		key, err := unlock(store)
+		if err != nil {
+			return err
+		}
+
+		for i, credential := range store.CredentialsList {
+			if credential.EncryptedSecret == "" {
+				continue
+			}
+
+			secret, err := decrypt(key, credential.EncryptedSecret, additionalData(credential))
+			if err != nil {
+				return err
+			}
+
+			store.CredentialsList[i].Secret = secret
+			store.CredentialsList[i].EncryptedSecret = ""
+		}
+	}
+
+	password, err := masterPassword(NewMasterKeyEnv, "enter new master password: ", true)
+	if err != nil {
+		return err
+	}
+
+	key, err := initEncryption(store, password)
+	if err != nil {
+		return err
+	}
+
+	if _, err := encryptAll(store, key); err != nil {
+		return err
+	}
+
+	return writeFile(store)
+}
+
 // Add adds new credentials to the storage.
 func (h YAMLStorage) Add(creds *credentials.Credentials) error {
 	store, err := readFile()
@@ -76,6 +190,11 @@ func (h YAMLStorage) Add(creds *credentials.Credentials) error {
 		return err
 	}
 
+	key, err := unlock(store)
+	if err != nil {
+		return err
+	}
+
 	yml := YAMLCredentials{
 		ServerURL: creds.ServerURL,
 		Username:  creds.Username,
@@ -97,6 +216,11 @@ func (h YAMLStorage) Add(creds *credentials.Credentials) error {
 	}
 	store.CredentialsList = newList
 
+	// plaintext secrets of previous versions are migrated at the same time
+	if _, err := encryptAll(store, key); err != nil {
+		return err
+	}
+
 	err = writeFile(store)
 	if err != nil {
 		return err
@@ -137,9 +261,25 @@ func (h YAMLStorage) Get(serverURL string) (string, string, error) {
 	}
 
 	for _, credential := range store.CredentialsList {
-		if credential.ServerURL == serverURL {
+		if credential.ServerURL != serverURL {
+			continue
+		}
+
+		if credential.EncryptedSecret == "" {
 			return credential.Username, credential.Secret, nil
 		}
+
+		key, err := unlock(store)
+		if err != nil {
+			return "", "", err
+		}
+
+		secret, err := decrypt(key, credential.EncryptedSecret, additionalData(credential))
+		if err != nil {
+			return "", "", err
+		}
+
+		return credential.Username, secret, nil
 	}
 	return "", "", nil
 }
@@ -159,6 +299,31 @@ func (h YAMLStorage) List() (map[string]string, error) {
 	return result, nil
 }
 
+// encryptAll encrypts the plaintext secrets and returns their number.
+func encryptAll(store *YAMLCredentialsStore, key []byte) (int, error) {
+	count := 0
+	for i, credential := range store.CredentialsList {
+		if credential.EncryptedSecret != "" {
+			continue
+		}
+
+		encrypted, err := encrypt(key, credential.Secret, additionalData(credential))
+		if err != nil {
+			return count, err
+		}
+
+		store.CredentialsList[i].Secret = ""
+		store.CredentialsList[i].EncryptedSecret = encrypted
+		count++
+	}
+	return count, nil
+}
+
+// additionalData binds the encrypted secret to its server URL and username.
+func additionalData(credential YAMLCredentials) string {
+	return credential.ServerURL + "\x00" + credential.Username
+}
+
 func readFile() (*YAMLCredentialsStore, error) {
 	store := &YAMLCredentialsStore{
 		Version: Version,
@@ -185,7 +350,7 @@ func readFile() (*YAMLCredentialsStore, error) {
 		return nil, err
 	}
 
-	if store.Version != Version {
+	if store.Version != Version && store.Version != PlaintextVersion {
 		return nil, fmt.Errorf("%w : invalid storage version (%s)", ErrInvalidStorage, store.Version)
 	}
 
@@ -193,6 +358,12 @@ func readFile() (*YAMLCredentialsStore, error) {
 }
 
 func writeFile(list *YAMLCredentialsStore) error {
+	// a storage with plaintext secrets only can still be read by previous versions
+	list.Version = PlaintextVersion
+	if list.Encryption != nil {
+		list.Version = Version
+	}
+
 	out, err := yaml.Marshal(list)
 	if err != nil {
 		return err
@@ -0,0 +1,152 @@
+// Copyright (C) 2021 CGI France
+//
+// This file is part of LINO.
+//
+// LINO is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// LINO is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with LINO.  If not, see <http://www.gnu.org/licenses/>.
+
+package localstorage
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+	"testing"
+
+	"github.com/docker/docker-credential-helpers/credentials"
+	"github.com/mitchellh/go-homedir"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/argon2"
+)
+
+func TestDeriveKey(t *testing.T) {
+	salt := base64.StdEncoding.EncodeToString([]byte("0123456789abcdef"))
+
+	// a salt shorter than 16 bytes is rejected
+	key, err := deriveKey("password", &YAMLEncryption{Algorithm: Algorithm, KDF: PBKDF2, Iterations: minPBKDF2Iterations, Salt: base64.StdEncoding.EncodeToString([]byte("salt"))})
+	assert.NotNil(t, err)
+	assert.Nil(t, key)
+
+	key, err = deriveKey("password", &YAMLEncryption{Algorithm: Algorithm, KDF: PBKDF2, Iterations: minPBKDF2Iterations, Salt: salt})
+	assert.Nil(t, err)
+	assert.Len(t, key, keyLength)
+
+	key, err = deriveKey("password", &YAMLEncryption{Algorithm: Algorithm, KDF: KDF, Iterations: minIterations, Memory: minMemory, Threads: 1, Salt: salt})
+	assert.Nil(t, err)
+	assert.Equal(t, hex.EncodeToString(argon2.IDKey([]byte("password"), []byte("0123456789abcdef"), minIterations, minMemory, 1, keyLength)), hex.EncodeToString(key))
+
+	weak := []*YAMLEncryption{
+		{Algorithm: Algorithm, KDF: PBKDF2, Iterations: 4096, Salt: salt},
+		{Algorithm: Algorithm, KDF: KDF, Iterations: 1, Memory: Memory, Threads: Threads, Salt: salt},
+		{Algorithm: Algorithm, KDF: KDF, Iterations: Iterations, Memory: 1024, Threads: Threads, Salt: salt},
+		{Algorithm: Algorithm, KDF: KDF, Iterations: Iterations, Memory: Memory, Salt: salt},
+		{Algorithm: Algorithm, KDF: "sha256", Iterations: Iterations, Salt: salt},
+	}
+	for _, encryption := range weak {
+		_, err := deriveKey("password", encryption)
+		assert.True(t, errors.Is(err, ErrInvalidStorage), encryption.KDF)
+	}
+}
+
+func setup(t *testing.T) (string, func()) {
+	home, err := ioutil.TempDir("", "lino")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	previous := os.Getenv("HOME")
+	os.Setenv("HOME", home)
+	homedir.DisableCache = true
+	keys = map[string][]byte{}
+
+	return path.Join(home, FilePath, FileName), func() {
+		os.Setenv("HOME", previous)
+		os.Unsetenv(MasterKeyEnv)
+		os.Unsetenv(NewMasterKeyEnv)
+		os.RemoveAll(home)
+	}
+}
+
+func TestStoreEncrypted(t *testing.T) {
+	storeFile, teardown := setup(t)
+	defer teardown()
+
+	os.Setenv(MasterKeyEnv, "master")
+
+	err := Store(&credentials.Credentials{ServerURL: "postgresql://postgres@localhost:5432/postgres", Username: "postgres", Secret: "sakila"})
+	assert.Nil(t, err)
+
+	dat, err := ioutil.ReadFile(storeFile)
+	assert.Nil(t, err)
+	assert.False(t, strings.Contains(string(dat), "sakila"))
+	assert.True(t, strings.HasPrefix(string(dat), "version: v2"))
+
+	creds, err := Read("postgresql://postgres@localhost:5432/postgres")
+	assert.Nil(t, err)
+	assert.Equal(t, "sakila", creds.Secret)
+
+	_, err = Read("postgresql://postgres@localhost:5433/postgres")
+	assert.True(t, credentials.IsErrCredentialsNotFound(err))
+
+	// a new process with a wrong master password
+	keys = map[string][]byte{}
+	os.Setenv(MasterKeyEnv, "wrong")
+
+	_, err = Read("postgresql://postgres@localhost:5432/postgres")
+	assert.True(t, errors.Is(err, ErrMasterPassword))
+}
+
+func TestMigrateAndRotate(t *testing.T) {
+	storeFile, teardown := setup(t)
+	defer teardown()
+
+	plaintext := `version: v1
+credentials:
+  - serverURL: postgresql://postgres@localhost:5432/postgres
+    username: postgres
+    secret: sakila
+`
+	assert.Nil(t, os.MkdirAll(path.Dir(storeFile), 0700))
+	assert.Nil(t, ioutil.WriteFile(storeFile, []byte(plaintext), 0600))
+
+	creds, err := Read("postgresql://postgres@localhost:5432/postgres")
+	assert.Nil(t, err)
+	assert.Equal(t, "sakila", creds.Secret)
+
+	_, err = Migrate()
+	assert.True(t, errors.Is(err, ErrMasterPassword))
+
+	os.Setenv(MasterKeyEnv, "master")
+
+	count, err := Migrate()
+	assert.Nil(t, err)
+	assert.Equal(t, 1, count)
+
+	entries, err := Entries()
+	assert.Nil(t, err)
+	assert.Equal(t, []Entry{{ServerURL: "postgresql://postgres@localhost:5432/postgres", Username: "postgres", Encrypted: true}}, entries)
+
+	os.Setenv(NewMasterKeyEnv, "rotated")
+	assert.Nil(t, Rotate())
+
+	keys = map[string][]byte{}
+	os.Setenv(MasterKeyEnv, "rotated")
+
+	creds, err = Read("postgresql://postgres@localhost:5432/postgres")
+	assert.Nil(t, err)
+	assert.Equal(t, "sakila", creds.Secret)
+}
@@ -0,0 +1,143 @@
+// Copyright (C) 2021 CGI France
+//
+// This file is part of LINO.
+//
+// LINO is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// LINO is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with LINO.  If not, see <http://www.gnu.org/licenses/>.
+
+package localstorage
+
+import (
+	"fmt"
+	"os"
+
+	"golang.org/x/term"
+)
+
+const (
+	// MasterKeyEnv is the environment variable containing the master password of the storage.
+	MasterKeyEnv string = "LINO_MASTER_KEY"
+	// NewMasterKeyEnv is the environment variable containing the new master password on rotation.
+	NewMasterKeyEnv string = "LINO_NEW_MASTER_KEY"
+)
+
+// keys derived by this process, by salt, to ask the master password only once
+var keys = map[string][]byte{}
+
+// masterPassword returns the content of the environment variable, or asks the master password on the terminal.
+// A new master password is asked twice.
+func masterPassword(env string, prompt string, confirm bool) (string, error) {
+	if password := os.Getenv(env); password != "" {
+		return password, nil
+	}
+
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		return "", fmt.Errorf("%w : set the environment variable %s", ErrMasterPassword, env)
+	}
+
+	password, err := readPassword(fd, prompt)
+	if err != nil {
+		return "", err
+	}
+
+	if confirm {
+		confirmation, err := readPassword(fd, "confirm master password: ")
+		if err != nil {
+			return "", err
+		}
+		if confirmation != password {
+			return "", fmt.Errorf("%w : passwords do not match", ErrMasterPassword)
+		}
+	}
+
+	if password == "" {
+		return "", fmt.Errorf("%w : empty password", ErrMasterPassword)
+	}
+
+	return password, nil
+}
+
+func readPassword(fd int, prompt string) (string, error) {
+	// the standard output may contain the data of a pull
+	os.Stderr.Write([]byte(prompt))
+	password, err := term.ReadPassword(fd)
+	os.Stderr.Write([]byte("\n"))
+	if err != nil {
+		return "", err
+	}
+	return string(password), nil
+}
+
+// unlock returns the key of the storage, the encryption is initialized if the storage is not encrypted yet.
+func unlock(store *YAMLCredentialsStore) ([]byte, error) {
+	if store.Encryption == nil {
+		password, err := masterPassword(MasterKeyEnv, "enter new master password: ", true)
+		if err != nil {
+			return nil, err
+		}
+		return initEncryption(store, password)
+	}
+
+	if key, ok := keys[store.Encryption.Salt]; ok {
+		return key, nil
+	}
+
+	password, err := masterPassword(MasterKeyEnv, "enter master password: ", false)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := deriveKey(password, store.Encryption)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := decrypt(key, store.Encryption.Check, checkValue); err != nil {
+		return nil, err
+	}
+
+	keys[store.Encryption.Salt] = key
+	return key, nil
+}
+
+// initEncryption sets a new salt and derives the key from the password.
+func initEncryption(store *YAMLCredentialsStore, password string) ([]byte, error) {
+	salt, err := newSalt()
+	if err != nil {
+		return nil, err
+	}
+
+	encryption := &YAMLEncryption{
+		Algorithm:  Algorithm,
+		KDF:        KDF,
+		Iterations: Iterations,
+		Memory:     Memory,
+		Threads:    Threads,
+		Salt:       salt,
+	}
+
+	key, err := deriveKey(password, encryption)
+	if err != nil {
+		return nil, err
+	}
+
+	encryption.Check, err = encrypt(key, checkValue, checkValue)
+	if err != nil {
+		return nil, err
+	}
+
+	store.Encryption = encryption
+	keys[salt] = key
+	return key, nil
+}
@@ -21,6 +21,7 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"time"
 
 	"github.com/rs/zerolog/log"
 	"github.com/spf13/cobra"
@@ -30,6 +31,7 @@ import (
 	"github.com/cgi-fr/lino/pkg/id"
 	"github.com/cgi-fr/lino/pkg/pull"
 	"github.com/cgi-fr/lino/pkg/relation"
+	"github.com/cgi-fr/lino/pkg/retry"
 	"github.com/cgi-fr/lino/pkg/table"
 )
 
@@ -37,10 +39,11 @@ var (
 	dataconnectorStorage dataconnector.Storage
 	relStorage           relation.Storage
 	tabStorage           table.Storage
-	idStorageFactory     func(string) id.Storage
+	idStorageFactory     func(string, string) id.Storage
 	dataSourceFactories  map[string]pull.DataSourceFactory
 	pullExporterFactory  func(io.Writer) pull.RowExporter
 	rowReaderFactory     func(io.ReadCloser) pull.RowReader
+	statsWriterFactory   func(io.Writer) pull.StatsWriter
 )
 
 var traceListener pull.TraceListener
@@ -50,11 +53,12 @@ func Inject(
 	dbas dataconnector.Storage,
 	rs relation.Storage,
 	ts table.Storage,
-	idsf func(string) id.Storage,
+	idsf func(string, string) id.Storage,
 	dsfmap map[string]pull.DataSourceFactory,
 	exporterFactory func(io.Writer) pull.RowExporter,
 	rrf func(io.ReadCloser) pull.RowReader,
-	tl pull.TraceListener) {
+	tl pull.TraceListener,
+	swf func(io.Writer) pull.StatsWriter) {
 	dataconnectorStorage = dbas
 	relStorage = rs
 	tabStorage = ts
@@ -63,6 +67,7 @@ func Inject(
 	pullExporterFactory = exporterFactory
 	rowReaderFactory = rrf
 	traceListener = tl
+	statsWriterFactory = swf
 }
 
 // NewCommand implements the cli pull command
@@ -75,6 +80,14 @@ func NewCommand(fullName string, err *os.File, out *os.File, in *os.File) *cobra
 	var initialFilters map[string]string
 	var diagnostic bool
 	var filters pull.RowReader
+	var retryAttempts uint
+	var retryBackoff time.Duration
+	var retryCodes []string
+	var statsFile string
+	var maxCycleDepth uint
+	var idName string
+	var referencesFile string
+	var refs bool
 
 	cmd := &cobra.Command{
 		Use:     "pull [DB Alias Name]",
@@ -89,12 +102,17 @@ func NewCommand(fullName string, err *os.File, out *os.File, in *os.File) *cobra
 				os.Exit(1)
 			}
 
-			plan, e2 := getPullerPlan(initialFilters, limit, where, idStorageFactory(table))
+			roots, e2 := getPullerPlans(initialFilters, limit, where, maxCycleDepth, idStorageFactory(table, idName))
 			if e2 != nil {
 				fmt.Fprintln(err, e2.Error())
 				os.Exit(1)
 			}
 
+			if len(roots) > 1 && filefilter != "" {
+				fmt.Fprintln(err, "filter from file is not supported with several roots")
+				os.Exit(1)
+			}
+
 			var tracer pull.TraceListener
 
 			tracer = pull.NoTraceListener{}
@@ -116,7 +134,34 @@ func NewCommand(fullName string, err *os.File, out *os.File, in *os.File) *cobra
 				}
 				filters = rowReaderFactory(filterReader)
 			}
-			e3 := pull.Pull(plan, filters, datasource, pullExporterFactory(out), tracer)
+			exporter := pullExporterFactory(out)
+			referenceExporter := exporter
+			var referenceFile *os.File
+			if referencesFile != "" {
+				var e4 error
+				referenceFile, e4 = os.Create(referencesFile)
+				if e4 != nil {
+					fmt.Fprintln(err, e4.Error())
+					os.Exit(1)
+				}
+				referenceExporter = pullExporterFactory(referenceFile)
+			}
+
+			policy := retry.NewPolicy(retryAttempts, retryBackoff, retryCodes)
+			stats, e3 := pullRoots(roots, filters, datasource, exporter, referenceExporter, tracer, policy, refs)
+
+			if referenceFile != nil {
+				referenceFile.Close()
+			}
+
+			if statsFile != "" {
+				e4 := writeStats(statsFile, stats)
+				if e4 != nil {
+					fmt.Fprintln(err, e4.Error())
+					os.Exit(1)
+				}
+			}
+
 			if e3 != nil {
 				fmt.Fprintln(err, e3.Error())
 				os.Exit(1)
@@ -129,12 +174,30 @@ func NewCommand(fullName string, err *os.File, out *os.File, in *os.File) *cobra
 	cmd.Flags().StringVarP(&filefilter, "filter-from-file", "F", "", "Use file to filter start table")
 	cmd.Flags().StringVarP(&table, "table", "t", "", "pull content of table without relations instead of ingress descriptor definition")
 	cmd.Flags().StringVarP(&where, "where", "w", "", "Advanced SQL where clause to filter")
+	cmd.Flags().StringVar(&idName, "id", "", "Name of the ingress descriptor (default ingress-descriptor.yaml)")
+	cmd.Flags().UintVar(&retryAttempts, "retry", 0, "Number of retries of a query failing with a retryable error, errors while reading the rows of a query are not retried")
+	cmd.Flags().DurationVar(&retryBackoff, "retry-backoff", 500*time.Millisecond, "Delay before the first retry, doubled at each retry")
+	cmd.Flags().StringSliceVar(&retryCodes, "retry-codes", retry.DefaultCodes, "Retryable database error codes")
+	cmd.Flags().StringVar(&statsFile, "stats", "", "Write run statistics as JSON in file")
+	cmd.Flags().BoolVar(&refs, "refs", false, "Replace parent rows already exported by a $ref marker with their primary key")
+	cmd.Flags().StringVar(&referencesFile, "references-file", "", "Write rows of reference tables in file instead of first in the output stream")
+	cmd.Flags().UintVar(&maxCycleDepth, "max-cycle-depth", 0, "Maximum number of times a cycle is followed when the ingress descriptor does not set maxDepth (0 for no limit)")
 	cmd.SetOut(out)
 	cmd.SetErr(err)
 	cmd.SetIn(in)
 	return cmd
 }
 
+func writeStats(filename string, stats pull.Stats) *pull.Error {
+	file, e1 := os.Create(filename)
+	if e1 != nil {
+		return &pull.Error{Description: e1.Error()}
+	}
+	defer file.Close()
+
+	return statsWriterFactory(file).Write(stats)
+}
+
 func getDataSource(dataconnectorName string, out io.Writer) (pull.DataSource, *pull.Error) {
 	alias, e1 := dataconnector.Get(dataconnectorStorage, dataconnectorName)
 	if e1 != nil {
@@ -151,10 +214,22 @@ func getDataSource(dataconnectorName string, out io.Writer) (pull.DataSource, *p
 		return nil, &pull.Error{Description: "no datasource found for database type"}
 	}
 
-	return datasourceFactory.New(u.URL.String(), alias.Schema), nil
+	return datasourceFactory.New(u.URL.String(), alias.Schema, alias.Options), nil
+}
+
+// rootPlan is the puller plan of a root of the ingress descriptor.
+type rootPlan struct {
+	name string
+	plan pull.Plan
 }
 
-func getPullerPlan(initialFilters map[string]string, limit uint, where string, idStorage id.Storage) (pull.Plan, *pull.Error) {
+// getPullerPlans returns a puller plan for each root of the ingress descriptor, filter, limit and where are used for roots that don't define them.
+func getPullerPlans(initialFilters map[string]string, limit uint, where string, maxCycleDepth uint, idStorage id.Storage) ([]rootPlan, *pull.Error) {
+	descriptor, err1 := idStorage.Read()
+	if err1 != nil {
+		return nil, &pull.Error{Description: err1.Error()}
+	}
+
 	ep, err1 := id.GetPullerPlan(idStorage)
 	if err1 != nil {
 		return nil, &pull.Error{Description: err1.Error()}
@@ -169,24 +244,115 @@ func getPullerPlan(initialFilters map[string]string, limit uint, where string, i
 	if err3 != nil {
 		return nil, &pull.Error{Description: err3.Error()}
 	}
-	var filter pull.Filter
 
-	stepList, err4 := getStepList(ep, relations, tables)
+	stepList, err4 := getStepList(ep, relations, tables, maxCycleDepth)
 
 	if err4 != nil {
 		return nil, &pull.Error{Description: err4.Error()}
 	}
 
-	row := pull.Row{}
-	for column, value := range initialFilters {
-		row[column] = value
+	tmap := map[string]table.Table{}
+	for _, table := range tables {
+		tmap[table.Name] = table
+	}
+
+	references := []pull.Table{}
+	for i := uint(0); i < descriptor.ReferenceTables().Len(); i++ {
+		name := descriptor.ReferenceTables().Table(i).Name()
+		references = append(references, pull.NewVirtualTable(name, tmap[name].Keys, tmap[name].View, tmap[name].Query))
 	}
-	filter = pull.NewFilter(limit, row, where)
 
-	return pull.NewPlan(filter, stepList), nil
+	roots := descriptor.Roots()
+	plans := []rootPlan{}
+	for idx := uint(0); idx < ep.Len(); idx++ {
+		if ep.Step(idx).PreviousStep() != 0 {
+			continue
+		}
+		root := roots[len(plans)]
+
+		row := pull.Row{}
+		filter := initialFilters
+		if len(root.Filter) > 0 {
+			filter = root.Filter
+		}
+		for column, value := range filter {
+			row[column] = value
+		}
+		rootLimit := limit
+		if root.Limit > 0 {
+			rootLimit = root.Limit
+		}
+		rootWhere := where
+		if root.Where != "" {
+			rootWhere = root.Where
+		}
+
+		name := root.Name
+		if name == "" {
+			name = root.Table
+		}
+
+		plans = append(plans, rootPlan{name, pull.NewPlanWithReferences(pull.NewFilter(rootLimit, row, rootWhere), pull.NewStepList([]pull.Step{stepList.Step(idx)}), references)})
+	}
+
+	return plans, nil
+}
+
+// pullRoots pull reference tables in referenceExporter then each root in sequence, rows are tagged with the name of their root if there are several roots.
+// A root row already exported by a previous root is not exported again.
+func pullRoots(roots []rootPlan, filters pull.RowReader, datasource pull.DataSource, exporter pull.RowExporter, referenceExporter pull.RowExporter, tracer pull.TraceListener, policy retry.Policy, refs bool) (pull.Stats, *pull.Error) {
+	stats := pull.NewStats()
+
+	for _, reference := range roots[0].plan.References() {
+		referenceStats, err := pull.PullReferences([]pull.Table{reference}, datasource, referenceTableExporter{reference.Name(), referenceExporter}, policy)
+		stats.Merge(referenceStats)
+		if err != nil {
+			return stats, err
+		}
+	}
+
+	if len(roots) == 1 {
+		rootStats, err := pull.Pull(roots[0].plan, filters, datasource, exporter, tracer, policy, refs)
+		stats.Merge(rootStats)
+		return stats, err
+	}
+
+	plans := []pull.Plan{}
+	exporters := []pull.RowExporter{}
+	for _, root := range roots {
+		plans = append(plans, root.plan)
+		exporters = append(exporters, rootExporter{root.name, exporter})
+	}
+
+	rootStats, err := pull.PullRoots(plans, exporters, datasource, tracer, policy, refs)
+	stats.Merge(rootStats)
+	return stats, err
+}
+
+// rootExporter tags the rows with the name of their root.
+type rootExporter struct {
+	name     string
+	exporter pull.RowExporter
+}
+
+func (e rootExporter) Export(row pull.Row) *pull.Error {
+	row[pull.RootKey] = e.name
+	return e.exporter.Export(row)
+}
+
+// referenceTableExporter tags the rows with the name of their reference table.
+type referenceTableExporter struct {
+	table    string
+	exporter pull.RowExporter
+}
+
+func (e referenceTableExporter) Export(row pull.Row) *pull.Error {
+	row[pull.ReferenceKey] = e.table
+	return e.exporter.Export(row)
 }
 
-func getStepList(ep id.PullerPlan, relations []relation.Relation, tables []table.Table) (pull.StepList, error) {
+// getStepList convert the puller plan, cycles without maximum depth in the ingress descriptor are bounded by maxCycleDepth (0 for no limit)
+func getStepList(ep id.PullerPlan, relations []relation.Relation, tables []table.Table, maxCycleDepth uint) (pull.StepList, error) {
 	rmap := map[string]relation.Relation{}
 	for _, relation := range relations {
 		rmap[relation.Name] = relation
@@ -211,6 +377,8 @@ func getStepList(ep id.PullerPlan, relations []relation.Relation, tables []table
 		exrmap: map[string]pull.Relation{},
 		extmap: map[string]pull.Table{},
 		exsmap: map[uint]pull.Step{},
+
+		maxCycleDepth: maxCycleDepth,
 	}
 	steps, err := converter.getSteps()
 	if err != nil {
@@ -228,6 +396,8 @@ type epToStepListConverter struct {
 	exrmap map[string]pull.Relation
 	extmap map[string]pull.Table
 	exsmap map[uint]pull.Step
+
+	maxCycleDepth uint
 }
 
 func (c epToStepListConverter) getTable(name string) pull.Table {
@@ -243,7 +413,7 @@ func (c epToStepListConverter) getTable(name string) pull.Table {
 
 	log.Trace().Msg(fmt.Sprintf("building table %v", table))
 
-	return pull.NewTable(table.Name, table.Keys)
+	return pull.NewVirtualTable(table.Name, table.Keys, table.View, table.Query)
 }
 
 func (c epToStepListConverter) getRelation(name string) (pull.Relation, error) {
@@ -288,11 +458,20 @@ func (c epToStepListConverter) getRelationList(relations id.IngressRelationList)
 func (c epToStepListConverter) getCycleList(cycles id.CycleList) (pull.CycleList, error) {
 	excycles := []pull.Cycle{}
 	for idx := uint(0); idx < cycles.Len(); idx++ {
-		rel, err := c.getRelationList(cycles.Cycle(idx))
-		if err != nil {
-			return nil, err
+		cycle := cycles.Cycle(idx)
+		exrelations := []pull.Relation{}
+		for relIdx := uint(0); relIdx < cycle.Len(); relIdx++ {
+			rel, err := c.getRelation(cycle.Relation(relIdx).Name())
+			if err != nil {
+				return nil, err
+			}
+			exrelations = append(exrelations, rel)
+		}
+		maxDepth := cycle.MaxDepth()
+		if maxDepth == 0 {
+			maxDepth = c.maxCycleDepth
 		}
-		excycles = append(excycles, rel)
+		excycles = append(excycles, pull.NewCycle(exrelations, maxDepth))
 	}
 	return pull.NewCycleList(excycles), nil
 }
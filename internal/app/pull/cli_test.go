@@ -0,0 +1,285 @@
+// Copyright (C) 2021 CGI France
+//
+// This file is part of LINO.
+//
+// LINO is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// LINO is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with LINO.  If not, see <http://www.gnu.org/licenses/>.
+
+package pull
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/cgi-fr/lino/pkg/id"
+	"github.com/cgi-fr/lino/pkg/pull"
+	"github.com/cgi-fr/lino/pkg/push"
+	"github.com/cgi-fr/lino/pkg/relation"
+	"github.com/cgi-fr/lino/pkg/retry"
+	"github.com/cgi-fr/lino/pkg/table"
+	"github.com/stretchr/testify/assert"
+)
+
+// memoryDataSource returns the rows of each table matching all the values of the filter.
+type memoryDataSource map[string][]pull.Row
+
+func (ds memoryDataSource) Open() *pull.Error  { return nil }
+func (ds memoryDataSource) Close() *pull.Error { return nil }
+
+func (ds memoryDataSource) RowReader(source pull.Table, filter pull.Filter) (pull.RowReader, *pull.Error) {
+	result := []pull.Row{}
+	for _, row := range ds[source.Name()] {
+		if filter.Limit() > 0 && uint(len(result)) == filter.Limit() {
+			break
+		}
+		match := true
+		for key, value := range filter.Values() {
+			match = match && fmt.Sprint(row[key]) == fmt.Sprint(value)
+		}
+		if match {
+			copy := pull.Row{}
+			for key, value := range row {
+				copy[key] = value
+			}
+			result = append(result, copy)
+		}
+	}
+	return &memoryRowReader{rows: result}, nil
+}
+
+type memoryRowReader struct {
+	rows    []pull.Row
+	current pull.Row
+}
+
+func (r *memoryRowReader) Next() bool {
+	if len(r.rows) == 0 {
+		return false
+	}
+	r.current, r.rows = r.rows[0], r.rows[1:]
+	return true
+}
+
+func (r *memoryRowReader) Value() pull.Row    { return r.current }
+func (r *memoryRowReader) Error() *pull.Error { return nil }
+
+type memoryRowExporter struct {
+	rows []pull.Row
+}
+
+func (e *memoryRowExporter) Export(row pull.Row) *pull.Error {
+	e.rows = append(e.rows, row)
+	return nil
+}
+
+func TestPullOverlappingRoots(t *testing.T) {
+	customer := id.NewTable("customer")
+	store := id.NewTable("store")
+	descriptor := id.NewMultiRootIngressDescriptor(
+		[]id.Root{
+			{Table: "customer", Limit: 2},
+			{Name: "active", Table: "customer", Filter: map[string]string{"active": "1"}},
+		},
+		id.NewTableList([]id.Table{}),
+		id.NewIngressRelationList([]id.IngressRelation{
+			id.NewIngressRelation(id.NewRelation("customer_store", store, customer), true, false),
+		}),
+	)
+
+	idStorage := &id.MockStorage{}
+	idStorage.On("Read").Return(descriptor, nil)
+	relations := &relation.MockStorage{}
+	relations.On("List").Return([]relation.Relation{{
+		Name:   "customer_store",
+		Parent: relation.Table{Name: "store", Keys: []string{"store_id"}},
+		Child:  relation.Table{Name: "customer", Keys: []string{"store_id"}},
+	}}, nil)
+	tables := &table.MockStorage{}
+	tables.On("List").Return([]table.Table{
+		{Name: "customer", Keys: []string{"customer_id"}},
+		{Name: "store", Keys: []string{"store_id"}},
+	}, nil)
+	relStorage = relations
+	tabStorage = tables
+
+	datasource := memoryDataSource{
+		"customer": {
+			{"customer_id": 1, "store_id": 1, "active": 1},
+			{"customer_id": 2, "store_id": 2, "active": 0},
+			{"customer_id": 3, "store_id": 1, "active": 1},
+		},
+		"store": {
+			{"store_id": 1},
+			{"store_id": 2},
+		},
+	}
+
+	roots, err := getPullerPlans(map[string]string{}, 0, "", 0, idStorage)
+	assert.Nil(t, err)
+	assert.Len(t, roots, 2)
+
+	exporter := &memoryRowExporter{}
+	stats, err := pullRoots(roots, pull.NewOneEmptyRowReader(), datasource, exporter, exporter, pull.NoTraceListener{}, retry.None, true)
+	assert.Nil(t, err)
+
+	// customer 1 is pulled by both roots but exported once, its store is a $ref in the document of customer 3
+	assert.Len(t, exporter.rows, 3)
+	assert.Equal(t, []interface{}{1, 2, 3}, []interface{}{exporter.rows[0]["customer_id"], exporter.rows[1]["customer_id"], exporter.rows[2]["customer_id"]})
+	assert.Equal(t, []interface{}{"customer", "customer", "active"}, []interface{}{exporter.rows[0][pull.RootKey], exporter.rows[1][pull.RootKey], exporter.rows[2][pull.RootKey]})
+	assert.Equal(t, pull.Row{"store_id": 1}, exporter.rows[0]["customer_store"])
+	assert.Equal(t, pull.Row{pull.RefKey: pull.Row{"table": "store", "key": pull.Row{"store_id": 1}}}, exporter.rows[2]["customer_store"])
+	assert.Equal(t, uint(3), stats.Tables["customer"].Exported)
+}
+
+func TestRootFilterReplacesFilterFlag(t *testing.T) {
+	descriptor := id.NewMultiRootIngressDescriptor(
+		[]id.Root{
+			{Table: "customer"},
+			{Name: "active", Table: "customer", Filter: map[string]string{"active": "1"}},
+		},
+		id.NewTableList([]id.Table{}),
+		id.NewIngressRelationList([]id.IngressRelation{}),
+	)
+
+	idStorage := &id.MockStorage{}
+	idStorage.On("Read").Return(descriptor, nil)
+	relations := &relation.MockStorage{}
+	relations.On("List").Return([]relation.Relation{}, nil)
+	tables := &table.MockStorage{}
+	tables.On("List").Return([]table.Table{{Name: "customer", Keys: []string{"customer_id"}}}, nil)
+	relStorage = relations
+	tabStorage = tables
+
+	roots, err := getPullerPlans(map[string]string{"store_id": "2"}, 0, "", 0, idStorage)
+	assert.Nil(t, err)
+	assert.Len(t, roots, 2)
+
+	assert.Equal(t, pull.Row{"store_id": "2"}, roots[0].plan.InitFilter().Values())
+	assert.Equal(t, pull.Row{"active": "1"}, roots[1].plan.InitFilter().Values())
+}
+
+// memoryDataDestination records the rows written in each table.
+type memoryDataDestination map[string][]push.Row
+
+func (d memoryDataDestination) Open(plan push.Plan, mode push.Mode, disableConstraints bool) *push.Error {
+	return nil
+}
+func (d memoryDataDestination) Commit() *push.Error   { return nil }
+func (d memoryDataDestination) Rollback() *push.Error { return nil }
+func (d memoryDataDestination) Close() *push.Error    { return nil }
+
+func (d memoryDataDestination) RowWriter(table push.Table) (push.RowWriter, *push.Error) {
+	return memoryRowWriter{d, table.Name()}, nil
+}
+
+type memoryRowWriter struct {
+	d     memoryDataDestination
+	table string
+}
+
+func (w memoryRowWriter) Write(row push.Row) *push.Error {
+	w.d[w.table] = append(w.d[w.table], row)
+	return nil
+}
+
+// jsonRowIterator decodes the JSON lines written by pull.
+type jsonRowIterator struct {
+	decoder *json.Decoder
+	current push.Row
+	err     *push.Error
+}
+
+func (ri *jsonRowIterator) Next() bool {
+	ri.current = push.Row{}
+	if err := ri.decoder.Decode(&ri.current); err != nil {
+		if err != io.EOF {
+			ri.err = &push.Error{Description: err.Error()}
+		}
+		return false
+	}
+	return true
+}
+
+func (ri *jsonRowIterator) Value() *push.Row   { return &ri.current }
+func (ri *jsonRowIterator) Error() *push.Error { return ri.err }
+func (ri *jsonRowIterator) Close() *push.Error { return nil }
+
+// jsonRowExporter writes the rows as JSON lines.
+type jsonRowExporter struct {
+	encoder *json.Encoder
+}
+
+func (e jsonRowExporter) Export(row pull.Row) *pull.Error {
+	if err := e.encoder.Encode(row); err != nil {
+		return &pull.Error{Description: err.Error()}
+	}
+	return nil
+}
+
+func TestPullPushReferences(t *testing.T) {
+	descriptor := id.NewMultiRootIngressDescriptor(
+		[]id.Root{{Table: "customer"}},
+		id.NewTableList([]id.Table{id.NewTable("store")}),
+		id.NewIngressRelationList([]id.IngressRelation{}),
+	)
+
+	idStorage := &id.MockStorage{}
+	idStorage.On("Read").Return(descriptor, nil)
+	relations := &relation.MockStorage{}
+	relations.On("List").Return([]relation.Relation{}, nil)
+	tables := &table.MockStorage{}
+	tables.On("List").Return([]table.Table{
+		{Name: "customer", Keys: []string{"customer_id"}},
+		{Name: "store", Keys: []string{"store_id"}},
+	}, nil)
+	relStorage = relations
+	tabStorage = tables
+
+	datasource := memoryDataSource{
+		"customer": {{"customer_id": 1, "store_id": 1}},
+		"store":    {{"store_id": 1}, {"store_id": 2}},
+	}
+
+	customer := push.NewTable("customer", []string{"customer_id"})
+	store := push.NewTable("store", []string{"store_id"})
+	plan := push.NewPlanWithRoots(customer, []push.Relation{}, map[string]push.Table{"customer": customer}, []push.Table{store})
+
+	roots, err := getPullerPlans(map[string]string{}, 0, "", 0, idStorage)
+	assert.Nil(t, err)
+
+	// the reference rows are first in the output stream by default
+	stream := &bytes.Buffer{}
+	exporter := jsonRowExporter{json.NewEncoder(stream)}
+	_, err = pullRoots(roots, pull.NewOneEmptyRowReader(), datasource, exporter, exporter, pull.NoTraceListener{}, retry.None, false)
+	assert.Nil(t, err)
+
+	destination := memoryDataDestination{}
+	_, e := push.Push(&jsonRowIterator{decoder: json.NewDecoder(stream)}, destination, plan, push.Insert, 10, false, push.NoErrorCaptureRowWriter{}, retry.None)
+	assert.Nil(t, e)
+	assert.Equal(t, []push.Row{{"store_id": float64(1)}, {"store_id": float64(2)}}, destination["store"])
+	assert.Equal(t, []push.Row{{"customer_id": float64(1), "store_id": float64(1)}}, destination["customer"])
+
+	// the references file can be pushed on its own
+	stream, references := &bytes.Buffer{}, &bytes.Buffer{}
+	_, err = pullRoots(roots, pull.NewOneEmptyRowReader(), datasource, jsonRowExporter{json.NewEncoder(stream)}, jsonRowExporter{json.NewEncoder(references)}, pull.NoTraceListener{}, retry.None, false)
+	assert.Nil(t, err)
+
+	destination = memoryDataDestination{}
+	_, e = push.Push(&jsonRowIterator{decoder: json.NewDecoder(references)}, destination, plan, push.Insert, 10, false, push.NoErrorCaptureRowWriter{}, retry.None)
+	assert.Nil(t, e)
+	assert.Equal(t, []push.Row{{"store_id": float64(1)}, {"store_id": float64(2)}}, destination["store"])
+	assert.Empty(t, destination["customer"])
+}
@@ -23,6 +23,7 @@ import (
 	"strings"
 
 	"github.com/cgi-fr/lino/pkg/pull"
+	"github.com/cgi-fr/lino/pkg/retry"
 	"github.com/gorilla/mux"
 	"github.com/rs/zerolog/log"
 )
@@ -105,7 +106,7 @@ func Handler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	plan, e2 := getPullerPlan(filter, limit, where, idStorageFactory(query.Get("table")))
+	roots, e2 := getPullerPlans(filter, limit, where, 0, idStorageFactory(query.Get("table"), query.Get("id")))
 	if e2 != nil {
 		log.Error().Err(e2).Msg("")
 		w.WriteHeader(http.StatusInternalServerError)
@@ -119,7 +120,17 @@ func Handler(w http.ResponseWriter, r *http.Request) {
 
 	pullExporter := pullExporterFactory(w)
 
-	e3 := pull.Pull(plan, pull.NewOneEmptyRowReader(), datasource, pullExporter, pull.NoTraceListener{})
+	// statistics are sent after the rows in a trailer
+	w.Header().Set("Trailer", "X-Lino-Stats")
+
+	stats, e3 := pullRoots(roots, pull.NewOneEmptyRowReader(), datasource, pullExporter, pullExporter, pull.NoTraceListener{}, retry.None, query.Get("refs") == "true")
+
+	jsonStats := &strings.Builder{}
+	if ew := statsWriterFactory(jsonStats).Write(stats); ew != nil {
+		log.Error().Err(ew).Msg("can't write stats")
+	}
+	w.Header().Set("X-Lino-Stats", strings.TrimSpace(jsonStats.String()))
+
 	if e3 != nil {
 		log.Error().Err(e3).Msg("")
 		w.WriteHeader(http.StatusInternalServerError)
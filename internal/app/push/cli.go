@@ -21,15 +21,18 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"time"
 
 	"github.com/rs/zerolog/log"
 	"github.com/spf13/cobra"
 
 	"github.com/cgi-fr/lino/internal/app/urlbuilder"
+	infra "github.com/cgi-fr/lino/internal/infra/push"
 	"github.com/cgi-fr/lino/pkg/dataconnector"
 	"github.com/cgi-fr/lino/pkg/id"
 	"github.com/cgi-fr/lino/pkg/push"
 	"github.com/cgi-fr/lino/pkg/relation"
+	"github.com/cgi-fr/lino/pkg/retry"
 	"github.com/cgi-fr/lino/pkg/table"
 )
 
@@ -37,10 +40,12 @@ var (
 	dataconnectorStorage     dataconnector.Storage
 	relStorage               relation.Storage
 	tabStorage               table.Storage
-	idStorageFactory         func(string) id.Storage
+	idStorageFactory         func(string, string) id.Storage
 	datadestinationFactories map[string]push.DataDestinationFactory
 	rowIteratorFactory       func(io.ReadCloser) push.RowIterator
 	rowExporterFactory       func(io.Writer) push.RowWriter
+	tableExtractorFactories  map[string]table.ExtractorFactory
+	statsWriterFactory       func(io.Writer) push.StatsWriter
 )
 
 // Inject dependencies
@@ -48,10 +53,12 @@ func Inject(
 	dbas dataconnector.Storage,
 	rs relation.Storage,
 	ts table.Storage,
-	idsf func(string) id.Storage,
+	idsf func(string, string) id.Storage,
 	dsfmap map[string]push.DataDestinationFactory,
 	rif func(io.ReadCloser) push.RowIterator,
 	ref func(io.Writer) push.RowWriter,
+	tefmap map[string]table.ExtractorFactory,
+	swf func(io.Writer) push.StatsWriter,
 ) {
 	dataconnectorStorage = dbas
 	relStorage = rs
@@ -60,6 +67,8 @@ func Inject(
 	datadestinationFactories = dsfmap
 	rowIteratorFactory = rif
 	rowExporterFactory = ref
+	tableExtractorFactories = tefmap
+	statsWriterFactory = swf
 }
 
 // NewCommand implements the cli pull command
@@ -70,13 +79,20 @@ func NewCommand(fullName string, err *os.File, out *os.File, in *os.File) *cobra
 		catchErrors        string
 		table              string
 		rowExporter        push.RowWriter
+		validate           bool
+		validateLimit      uint
+		retryAttempts      uint
+		retryBackoff       time.Duration
+		retryCodes         []string
+		statsFile          string
+		idName             string
 	)
 
 	cmd := &cobra.Command{
 		Use:     "push {<truncate>|<insert>|<update>|<delete>} [Data Connector Name]",
 		Short:   "Push data to a database with a pushing mode (insert by default)",
 		Long:    "",
-		Example: fmt.Sprintf("  %[1]s push truncate dstdatabase\n  %[1]s push dstdatabase", fullName),
+		Example: fmt.Sprintf("  %[1]s push truncate dstdatabase\n  %[1]s push dstdatabase\n  %[1]s push dstdatabase --validate < data.jsonl", fullName),
 		Args: func(cmd *cobra.Command, args []string) error {
 			if len(args) == 1 {
 				return nil
@@ -104,11 +120,33 @@ func NewCommand(fullName string, err *os.File, out *os.File, in *os.File) *cobra
 				os.Exit(1)
 			}
 
-			plan, e2 := getPlan(idStorageFactory(table))
+			plan, e2 := getPlan(idStorageFactory(table, idName))
 			if e2 != nil {
 				fmt.Fprintln(err, e2.Error())
 				os.Exit(2)
 			}
+
+			if validate {
+				reader, e5 := getMetadataReader(dcDestination)
+				if e5 != nil {
+					fmt.Fprintln(err, e5.Error())
+					os.Exit(1)
+				}
+				incompatibilities, e6 := push.Validate(rowIteratorFactory(in), plan, mode, reader, validateLimit)
+				if e6 != nil {
+					fmt.Fprintln(err, e6.Error())
+					os.Exit(1)
+				}
+				for _, i := range incompatibilities {
+					fmt.Fprintf(out, "table %s, column %s: %s (%d row(s), first at line %d)\n", i.Table, i.Column, i.Reason, i.Count, i.FirstLine)
+				}
+				if len(incompatibilities) > 0 {
+					fmt.Fprintf(err, "%d incompatibility(ies) found\n", len(incompatibilities))
+					os.Exit(5)
+				}
+				fmt.Fprintln(out, "no incompatibility found")
+				return
+			}
 			log.Debug().Msg(fmt.Sprintf("call Push with mode %s", mode))
 
 			if catchErrors != "" {
@@ -122,7 +160,17 @@ func NewCommand(fullName string, err *os.File, out *os.File, in *os.File) *cobra
 			} else {
 				rowExporter = push.NoErrorCaptureRowWriter{}
 			}
-			e3 := push.Push(rowIteratorFactory(in), datadestination, plan, mode, commitSize, disableConstraints, rowExporter)
+			policy := retry.NewPolicy(retryAttempts, retryBackoff, retryCodes)
+			stats, e3 := push.Push(rowIteratorFactory(in), datadestination, plan, mode, commitSize, disableConstraints, rowExporter, policy)
+
+			if statsFile != "" {
+				e5 := writeStats(statsFile, stats)
+				if e5 != nil {
+					fmt.Fprintln(err, e5.Error())
+					os.Exit(1)
+				}
+			}
+
 			if e3 != nil {
 				fmt.Fprintln(err, e3.Error())
 				os.Exit(1)
@@ -133,12 +181,29 @@ func NewCommand(fullName string, err *os.File, out *os.File, in *os.File) *cobra
 	cmd.Flags().BoolVarP(&disableConstraints, "disable-constraints", "d", false, "Disable constraint during push")
 	cmd.Flags().StringVarP(&catchErrors, "catch-errors", "e", "", "Catch errors and write line in file")
 	cmd.Flags().StringVarP(&table, "table", "t", "", "Table to writes json")
+	cmd.Flags().StringVar(&idName, "id", "", "Name of the ingress descriptor (default ingress-descriptor.yaml)")
+	cmd.Flags().BoolVar(&validate, "validate", false, "Validate input against destination columns metadata without pushing")
+	cmd.Flags().UintVar(&validateLimit, "validate-limit", 0, "Number of input rows to validate (0 to validate all rows)")
+	cmd.Flags().UintVar(&retryAttempts, "retry", 0, "Number of replays since the last commit on a retryable error")
+	cmd.Flags().DurationVar(&retryBackoff, "retry-backoff", 500*time.Millisecond, "Delay before the first replay, doubled at each replay")
+	cmd.Flags().StringSliceVar(&retryCodes, "retry-codes", retry.DefaultCodes, "Retryable database error codes")
+	cmd.Flags().StringVar(&statsFile, "stats", "", "Write run statistics as JSON in file")
 	cmd.SetOut(out)
 	cmd.SetErr(err)
 	cmd.SetIn(in)
 	return cmd
 }
 
+func writeStats(filename string, stats push.Stats) *push.Error {
+	file, e1 := os.Create(filename)
+	if e1 != nil {
+		return &push.Error{Description: e1.Error()}
+	}
+	defer file.Close()
+
+	return statsWriterFactory(file).Write(stats)
+}
+
 func getDataDestination(dataconnectorName string) (push.DataDestination, *push.Error) {
 	alias, e1 := dataconnector.Get(dataconnectorStorage, dataconnectorName)
 	if e1 != nil {
@@ -158,7 +223,31 @@ func getDataDestination(dataconnectorName string) (push.DataDestination, *push.E
 		return nil, &push.Error{Description: "no datadestination found for database type " + u.Unaliased}
 	}
 
-	return datadestinationFactory.New(u.URL.String(), alias.Schema), nil
+	return datadestinationFactory.New(u.URL.String(), alias.Schema, alias.Options), nil
+}
+
+func getMetadataReader(dataconnectorName string) (push.MetadataReader, *push.Error) {
+	alias, e1 := dataconnector.Get(dataconnectorStorage, dataconnectorName)
+	if e1 != nil {
+		return nil, &push.Error{Description: e1.Error()}
+	}
+	if alias == nil {
+		return nil, &push.Error{Description: fmt.Sprintf("'%s' dataconnector not found", dataconnectorName)}
+	}
+
+	u := urlbuilder.BuildURL(alias, nil)
+
+	tableExtractorFactory, ok := tableExtractorFactories[u.Unaliased]
+	if !ok {
+		return nil, &push.Error{Description: "no extractor found for database type " + u.Unaliased}
+	}
+
+	tables, e2 := tableExtractorFactory.New(u.URL.String(), alias.Schema, alias.Options).ExtractColumns()
+	if e2 != nil {
+		return nil, &push.Error{Description: e2.Error()}
+	}
+
+	return infra.NewTableMetadataReader(tables), nil
 }
 
 func getPlan(idStorage id.Storage) (push.Plan, *push.Error) {
@@ -187,6 +276,10 @@ func getPlan(idStorage id.Storage) (push.Plan, *push.Error) {
 		tmap[table.Name] = table
 	}
 
+	if name := virtualTable(id, rmap, tmap); name != "" {
+		return nil, &push.Error{Description: fmt.Sprintf("table %s is a virtual table backed by a view or a query, it can't be pushed", name)}
+	}
+
 	converter := idToPushConverter{
 		rmap:     rmap,
 		tmap:     tmap,
@@ -197,6 +290,28 @@ func getPlan(idStorage id.Storage) (push.Plan, *push.Error) {
 	return converter.getPlan(id), nil
 }
 
+// virtualTable returns the name of the first table of the ingress descriptor backed by a view or a query, or an empty string.
+func virtualTable(id id.IngressDescriptor, rmap map[string]relation.Relation, tmap map[string]table.Table) string {
+	names := []string{id.StartTable().Name()}
+	for _, root := range id.Roots() {
+		names = append(names, root.Table)
+	}
+	for idx := uint(0); idx < id.ReferenceTables().Len(); idx++ {
+		names = append(names, id.ReferenceTables().Table(idx).Name())
+	}
+	for idx := uint(0); idx < id.Relations().Len(); idx++ {
+		if rel, ok := rmap[id.Relations().Relation(idx).Name()]; ok {
+			names = append(names, rel.Parent.Name, rel.Child.Name)
+		}
+	}
+	for _, name := range names {
+		if t, ok := tmap[name]; ok && (t.View != "" || t.Query != "") {
+			return name
+		}
+	}
+	return ""
+}
+
 type idToPushConverter struct {
 	rmap map[string]relation.Relation
 	tmap map[string]table.Table
@@ -249,5 +364,19 @@ func (c idToPushConverter) getPlan(id id.IngressDescriptor) push.Plan {
 		relations = append(relations, c.getRelation(rel.Name()))
 	}
 
-	return push.NewPlan(c.getTable(id.StartTable().Name()), relations)
+	roots := map[string]push.Table{}
+	for _, root := range id.Roots() {
+		name := root.Name
+		if name == "" {
+			name = root.Table
+		}
+		roots[name] = c.getTable(root.Table)
+	}
+
+	references := []push.Table{}
+	for idx := uint(0); idx < id.ReferenceTables().Len(); idx++ {
+		references = append(references, c.getTable(id.ReferenceTables().Table(idx).Name()))
+	}
+
+	return push.NewPlanWithRoots(c.getTable(id.StartTable().Name()), relations, roots, references)
 }
@@ -38,10 +38,12 @@ func Test_getDataDestination(t *testing.T) {
 		&dcStorage,
 		&relation.MockStorage{},
 		&table.MockStorage{},
-		func(string) id.Storage { return &id.MockStorage{} },
+		func(string, string) id.Storage { return &id.MockStorage{} },
 		map[string]push.DataDestinationFactory{},
 		func(io.ReadCloser) push.RowIterator { return &push.MockRowIterator{} },
 		func(io.Writer) push.RowWriter { return &push.MockRowWriter{} },
+		map[string]table.ExtractorFactory{},
+		func(io.Writer) push.StatsWriter { return &push.MockStatsWriter{} },
 	)
 
 	type args struct {
@@ -72,3 +74,45 @@ func Test_getDataDestination(t *testing.T) {
 		})
 	}
 }
+
+func Test_getPlanRejectsVirtualTable(t *testing.T) {
+	relStorage := relation.MockStorage{}
+	relStorage.On("List").Return([]relation.Relation{
+		{Name: "claim_customer", Parent: relation.Table{Name: "customer", Keys: []string{"id"}}, Child: relation.Table{Name: "open_claim", Keys: []string{"customer_id"}}},
+	}, nil)
+	tabStorage := table.MockStorage{}
+	tabStorage.On("List").Return([]table.Table{
+		{Name: "customer", Keys: []string{"id"}},
+		{Name: "open_claim", Keys: []string{"id"}, Query: "SELECT * FROM claim WHERE status = 'open'"},
+	}, nil)
+
+	Inject(
+		&dataconnector.MockStorage{},
+		&relStorage,
+		&tabStorage,
+		func(string, string) id.Storage { return &id.MockStorage{} },
+		map[string]push.DataDestinationFactory{},
+		func(io.ReadCloser) push.RowIterator { return &push.MockRowIterator{} },
+		func(io.Writer) push.RowWriter { return &push.MockRowWriter{} },
+		map[string]table.ExtractorFactory{},
+		func(io.Writer) push.StatsWriter { return &push.MockStatsWriter{} },
+	)
+
+	customer := id.NewTable("customer")
+	claims := id.NewIngressRelation(id.NewRelation("claim_customer", customer, id.NewTable("open_claim")), false, true)
+
+	idStorage := id.MockStorage{}
+	idStorage.On("Read").Return(id.NewIngressDescriptor(customer, id.NewIngressRelationList(nil)), nil).Once()
+	idStorage.On("Read").Return(id.NewIngressDescriptor(customer, id.NewIngressRelationList([]id.IngressRelation{claims})), nil).Once()
+
+	plan, err := getPlan(&idStorage)
+	if err != nil || plan == nil {
+		t.Errorf("getPlan() got error %v, want a plan of the physical table", err)
+	}
+
+	_, err = getPlan(&idStorage)
+	want := &push.Error{Description: "table open_claim is a virtual table backed by a view or a query, it can't be pushed"}
+	if !reflect.DeepEqual(err, want) {
+		t.Errorf("getPlan() got = %v, want %v", err, want)
+	}
+}
@@ -22,8 +22,10 @@ import (
 	"html"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/cgi-fr/lino/pkg/push"
+	"github.com/cgi-fr/lino/pkg/retry"
 	"github.com/gorilla/mux"
 	"github.com/rs/zerolog/log"
 )
@@ -74,7 +76,7 @@ func Handler(w http.ResponseWriter, r *http.Request, mode push.Mode) {
 		return
 	}
 
-	plan, e2 := getPlan(idStorageFactory(query.Get("table")))
+	plan, e2 := getPlan(idStorageFactory(query.Get("table"), query.Get("id")))
 	if e2 != nil {
 		log.Error().Err(e2).Msg("")
 		w.WriteHeader(http.StatusNotFound)
@@ -118,18 +120,26 @@ func Handler(w http.ResponseWriter, r *http.Request, mode push.Mode) {
 
 	log.Debug().Msg(fmt.Sprintf("call Push with mode %s", mode))
 
-	e3 := push.Push(rowIteratorFactory(r.Body), datadestination, plan, mode, commitSize, disableConstraints, push.NoErrorCaptureRowWriter{})
+	stats, e3 := push.Push(rowIteratorFactory(r.Body), datadestination, plan, mode, commitSize, disableConstraints, push.NoErrorCaptureRowWriter{}, retry.None)
+
+	jsonStats := &strings.Builder{}
+	if ew := statsWriterFactory(jsonStats).Write(stats); ew != nil {
+		log.Error().Err(ew).Msg("can't write stats")
+		jsonStats.Reset()
+		jsonStats.WriteString("{}")
+	}
+
 	if e3 != nil {
 		log.Error().Err(e3).Msg("")
 		w.WriteHeader(http.StatusNotFound)
-		_, ew := w.Write([]byte("{\"error\": \"" + e3.Description + "\"}"))
+		_, ew := w.Write([]byte("{\"error\": \"" + e3.Description + "\", \"stats\": " + strings.TrimSpace(jsonStats.String()) + "}"))
 		if ew != nil {
 			log.Error().Err(ew).Msg("Write failed")
 			return
 		}
 		return
 	}
-	_, ew := w.Write([]byte("{\"error\": \"\"}"))
+	_, ew := w.Write([]byte("{\"error\": \"\", \"stats\": " + strings.TrimSpace(jsonStats.String()) + "}"))
 	if ew != nil {
 		log.Error().Err(ew).Msg("Write failed")
 		return
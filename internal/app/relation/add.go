@@ -0,0 +1,64 @@
+// Copyright (C) 2021 CGI France
+//
+// This file is part of LINO.
+//
+// LINO is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// LINO is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with LINO.  If not, see <http://www.gnu.org/licenses/>.
+
+package relation
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/cgi-fr/lino/pkg/relation"
+	"github.com/spf13/cobra"
+)
+
+// newAddCommand implements the cli relation add command
+func newAddCommand(fullName string, err *os.File, out *os.File, in *os.File) *cobra.Command {
+	var parent, child string
+	var parentKeys, childKeys []string
+
+	cmd := &cobra.Command{
+		Use:     "add [Relation Name]",
+		Short:   "Add a manual relation to relations.yaml",
+		Long:    "",
+		Example: fmt.Sprintf("  %[1]s relation add film_language --parent public.language --parent-keys language_id --child public.film --child-keys language_id", fullName),
+		Args:    cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			rel := relation.Relation{
+				Name:   args[0],
+				Parent: relation.Table{Name: parent, Keys: parentKeys},
+				Child:  relation.Table{Name: child, Keys: childKeys},
+				Source: relation.Manual,
+			}
+
+			e := relation.Add(relationStorage, rel)
+			if e != nil {
+				fmt.Fprintln(err, e.Description)
+				os.Exit(1)
+			}
+
+			fmt.Fprintln(out, "successfully added relation")
+		},
+	}
+	cmd.Flags().StringVar(&parent, "parent", "", "Name of the parent table")
+	cmd.Flags().StringSliceVar(&parentKeys, "parent-keys", []string{}, "Key columns of the parent table")
+	cmd.Flags().StringVar(&child, "child", "", "Name of the child table")
+	cmd.Flags().StringSliceVar(&childKeys, "child-keys", []string{}, "Columns of the child table referencing the parent keys")
+	cmd.SetOut(out)
+	cmd.SetErr(err)
+	cmd.SetIn(in)
+	return cmd
+}
@@ -0,0 +1,98 @@
+// Copyright (C) 2021 CGI France
+//
+// This file is part of LINO.
+//
+// LINO is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// LINO is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with LINO.  If not, see <http://www.gnu.org/licenses/>.
+
+package relation
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/cgi-fr/lino/internal/app/urlbuilder"
+	"github.com/cgi-fr/lino/pkg/dataconnector"
+	"github.com/cgi-fr/lino/pkg/relation"
+	"github.com/spf13/cobra"
+)
+
+// newCheckCommand implements the cli relation check command
+func newCheckCommand(fullName string, err *os.File, out *os.File, in *os.File) *cobra.Command {
+	var sampleSize uint
+
+	cmd := &cobra.Command{
+		Use:     "check [DB Alias Name]",
+		Short:   "Check the relations against the data of the database",
+		Long:    "Count the child rows whose keys have no parent and the child rows with a null key for each relation of relations.yaml",
+		Example: fmt.Sprintf("  %[1]s relation check mydatabase\n  %[1]s relation check mydatabase --sample 10", fullName),
+		Args:    cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			alias, e1 := dataconnector.Get(dataconnectorStorage, args[0])
+			if e1 != nil {
+				fmt.Fprintln(err, e1.Description)
+				os.Exit(1)
+			}
+
+			if alias == nil {
+				fmt.Fprintln(err, "no dataconnector named "+args[0])
+				os.Exit(1)
+			}
+
+			u := urlbuilder.BuildURL(alias, err)
+
+			factory, ok := integrityCheckerFactories[u.Unaliased]
+			if !ok {
+				fmt.Fprintln(err, "no integrity checker found for database type")
+				os.Exit(1)
+			}
+
+			result, e2 := relation.Check(factory.New(u.URL.String(), alias.Schema, alias.Options), relationStorage, sampleSize)
+			if e2 != nil {
+				fmt.Fprintln(err, e2.Description)
+				os.Exit(1)
+			}
+
+			broken := 0
+			for _, integrity := range result {
+				rel := integrity.Relation
+				if integrity.KeyMismatch {
+					broken++
+					fmt.Fprintf(out, "%s: key column count mismatch between child %s %v and parent %s %v\n", rel.Name, rel.Child.Name, rel.Child.Keys, rel.Parent.Name, rel.Parent.Keys)
+					continue
+				}
+				if integrity.Orphans > 0 {
+					broken++
+				}
+				fmt.Fprintf(out, "%s: %v orphan(s), %v null key(s) on %v row(s) (%s)\n", rel.Name, integrity.Orphans, integrity.NullKeys, integrity.Rows, nullRate(integrity))
+				for _, keys := range integrity.Samples {
+					fmt.Fprintf(out, "  orphan %s %v\n", rel.Child.Name, keys)
+				}
+			}
+			fmt.Fprintf(out, "lino checks %v relation(s), %v not honoured by the data\n", len(result), broken)
+		},
+	}
+	cmd.Flags().UintVar(&sampleSize, "sample", 5, "Number of orphan keys printed for each relation")
+	cmd.SetOut(out)
+	cmd.SetErr(err)
+	cmd.SetIn(in)
+	return cmd
+}
+
+// nullRate returns the percentage of child rows with a null key
+func nullRate(integrity relation.Integrity) string {
+	if integrity.Rows == 0 {
+		return "0.0%"
+	}
+	return fmt.Sprintf("%.1f%%", 100*float64(integrity.NullKeys)/float64(integrity.Rows))
+}
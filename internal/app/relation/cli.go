@@ -23,30 +23,43 @@ import (
 
 	"github.com/cgi-fr/lino/pkg/dataconnector"
 	"github.com/cgi-fr/lino/pkg/relation"
+	"github.com/cgi-fr/lino/pkg/table"
 	"github.com/spf13/cobra"
 )
 
 var dataconnectorStorage dataconnector.Storage
 var relationStorage relation.Storage
 var relationExtractorFactories map[string]relation.ExtractorFactory
+var tableStorage table.Storage
+var inferenceReaderFactories map[string]relation.InferenceReaderFactory
+var integrityCheckerFactories map[string]relation.IntegrityCheckerFactory
 
 // Inject dependencies
-func Inject(dbas dataconnector.Storage, rs relation.Storage, exmap map[string]relation.ExtractorFactory) {
+func Inject(dbas dataconnector.Storage, rs relation.Storage, exmap map[string]relation.ExtractorFactory, ts table.Storage, irfmap map[string]relation.InferenceReaderFactory, icfmap map[string]relation.IntegrityCheckerFactory) {
 	dataconnectorStorage = dbas
 	relationStorage = rs
 	relationExtractorFactories = exmap
+	tableStorage = ts
+	inferenceReaderFactories = irfmap
+	integrityCheckerFactories = icfmap
 }
 
 // NewCommand implements the cli dataconnector command
 func NewCommand(fullName string, err *os.File, out *os.File, in *os.File) *cobra.Command {
 	cmd := &cobra.Command{
-		Use:     "relation {extract} [arguments ...]",
+		Use:     "relation {extract | infer | check | add | remove | show | list} [arguments ...]",
 		Short:   "Manage relations",
 		Long:    "",
 		Example: fmt.Sprintf("  %[1]s relation extract mydatabase", fullName),
 		Aliases: []string{"rel"},
 	}
 	cmd.AddCommand(newExtractCommand(fullName, err, out, in))
+	cmd.AddCommand(newInferCommand(fullName, err, out, in))
+	cmd.AddCommand(newCheckCommand(fullName, err, out, in))
+	cmd.AddCommand(newAddCommand(fullName, err, out, in))
+	cmd.AddCommand(newRemoveCommand(fullName, err, out, in))
+	cmd.AddCommand(newShowCommand(fullName, err, out, in))
+	cmd.AddCommand(newListCommand(fullName, err, out, in))
 	cmd.SetOut(out)
 	cmd.SetErr(err)
 	cmd.SetIn(in)
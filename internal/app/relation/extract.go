@@ -29,11 +29,14 @@ import (
 
 // newExtractCommand implements the cli relation extract command
 func newExtractCommand(fullName string, err *os.File, out *os.File, in *os.File) *cobra.Command {
+	var filter relation.Filter
+	var merge bool
+
 	cmd := &cobra.Command{
 		Use:     "extract [DB Alias Name]",
 		Short:   "Extract relations from database",
 		Long:    "",
-		Example: fmt.Sprintf("  %[1]s relation extract mydatabase", fullName),
+		Example: fmt.Sprintf("  %[1]s relation extract mydatabase\n  %[1]s relation extract mydatabase --schemas sales,stock --include 'order*' --exclude '/_(tmp|bak)$/'", fullName),
 		Args:    cobra.ExactArgs(1),
 		Run: func(cmd *cobra.Command, args []string) {
 			alias, e1 := dataconnector.Get(dataconnectorStorage, args[0])
@@ -55,12 +58,25 @@ func newExtractCommand(fullName string, err *os.File, out *os.File, in *os.File)
 				os.Exit(1)
 			}
 
-			extractor := factory.New(u.URL.String(), alias.Schema)
+			extractor := factory.New(u.URL.String(), alias.Schema, alias.Options)
 
-			e2 := relation.Extract(extractor, relationStorage)
-			if e2 != nil {
-				fmt.Fprintln(err, e2.Description)
-				os.Exit(1)
+			if merge {
+				changes, e2 := relation.Merge(extractor, relationStorage, filter)
+				if e2 != nil {
+					fmt.Fprintln(err, e2.Description)
+					os.Exit(1)
+				}
+
+				for _, change := range changes {
+					fmt.Fprintf(out, "%s: %s\n", change.Kind, change.Message)
+				}
+				fmt.Fprintf(out, "%d change(s)\n", len(changes))
+			} else {
+				e2 := relation.Extract(extractor, relationStorage, filter)
+				if e2 != nil {
+					fmt.Fprintln(err, e2.Description)
+					os.Exit(1)
+				}
 			}
 
 			relations, e2 := relationStorage.List()
@@ -72,6 +88,10 @@ func newExtractCommand(fullName string, err *os.File, out *os.File, in *os.File)
 			fmt.Fprintf(out, "lino finds %v relations from constraints\n", len(relations))
 		},
 	}
+	cmd.Flags().BoolVar(&merge, "merge", false, "Update the extracted relations of relations.yaml and keep the manual ones")
+	cmd.Flags().StringSliceVar(&filter.Schemas, "schemas", []string{}, "Schemas to extract instead of the dataconnector schema, table names are prefixed by their schema")
+	cmd.Flags().StringArrayVar(&filter.Include, "include", []string{}, "Extract only relations of tables matching this glob or /regular expression/ (repeatable)")
+	cmd.Flags().StringArrayVar(&filter.Exclude, "exclude", []string{}, "Don't extract relations of tables matching this glob or /regular expression/ (repeatable)")
 	cmd.SetOut(out)
 	cmd.SetErr(err)
 	cmd.SetIn(in)
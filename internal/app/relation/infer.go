@@ -0,0 +1,94 @@
+// Copyright (C) 2021 CGI France
+//
+// This file is part of LINO.
+//
+// LINO is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// LINO is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with LINO.  If not, see <http://www.gnu.org/licenses/>.
+
+package relation
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/cgi-fr/lino/internal/app/urlbuilder"
+	"github.com/cgi-fr/lino/pkg/dataconnector"
+	"github.com/cgi-fr/lino/pkg/relation"
+	"github.com/spf13/cobra"
+)
+
+// newInferCommand implements the cli relation infer command
+func newInferCommand(fullName string, err *os.File, out *os.File, in *os.File) *cobra.Command {
+	var patterns []string
+	var sampleSize uint
+	var minConfidence float64
+	var dryRun bool
+
+	cmd := &cobra.Command{
+		Use:     "infer [DB Alias Name]",
+		Short:   "Propose relations from the names of columns matching primary keys",
+		Long:    "Columns named after the primary key of a table in tables.yaml are proposed as relations and stored as manual relations with a confidence score",
+		Example: fmt.Sprintf("  %[1]s relation infer mydatabase\n  %[1]s relation infer mydatabase --sample 1000 --min-confidence 0.8 --pattern 'fk_{table}'", fullName),
+		Args:    cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			alias, e1 := dataconnector.Get(dataconnectorStorage, args[0])
+			if e1 != nil {
+				fmt.Fprintln(err, e1.Description)
+				os.Exit(1)
+			}
+
+			if alias == nil {
+				fmt.Fprintln(err, "no dataconnector named "+args[0])
+				os.Exit(1)
+			}
+
+			u := urlbuilder.BuildURL(alias, err)
+
+			factory, ok := inferenceReaderFactories[u.Unaliased]
+			if !ok {
+				fmt.Fprintln(err, "no inference reader found for database type")
+				os.Exit(1)
+			}
+
+			tables, e2 := tableStorage.List()
+			if e2 != nil {
+				fmt.Fprintln(err, e2.Description)
+				os.Exit(1)
+			}
+
+			keys := map[string][]string{}
+			for _, table := range tables {
+				keys[table.Name] = table.Keys
+			}
+
+			proposed, e3 := relation.Infer(factory.New(u.URL.String(), alias.Schema, alias.Options), keys, patterns, sampleSize, minConfidence, relationStorage, !dryRun)
+			if e3 != nil {
+				fmt.Fprintln(err, e3.Description)
+				os.Exit(1)
+			}
+
+			for _, rel := range proposed {
+				fmt.Fprintf(out, "%s: %s %v -> %s %v (confidence %.2f)\n", rel.Name, rel.Parent.Name, rel.Parent.Keys, rel.Child.Name, rel.Child.Keys, rel.Confidence)
+			}
+			fmt.Fprintf(out, "lino proposes %v relation(s)\n", len(proposed))
+		},
+	}
+	cmd.Flags().StringArrayVar(&patterns, "pattern", relation.DefaultPatterns, "Name of a column referencing a primary key, {table} is the table name and {key} the primary key column (repeatable)")
+	cmd.Flags().UintVar(&sampleSize, "sample", 0, "Number of values sampled to confirm each candidate (0 to rely on names only)")
+	cmd.Flags().Float64Var(&minConfidence, "min-confidence", 0.5, "Minimum confidence score of a proposed relation")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Print proposed relations without storing them in relations.yaml")
+	cmd.SetOut(out)
+	cmd.SetErr(err)
+	cmd.SetIn(in)
+	return cmd
+}
@@ -0,0 +1,97 @@
+// Copyright (C) 2021 CGI France
+//
+// This file is part of LINO.
+//
+// LINO is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// LINO is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with LINO.  If not, see <http://www.gnu.org/licenses/>.
+
+package relation
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/cgi-fr/lino/pkg/relation"
+	"github.com/spf13/cobra"
+)
+
+// jsonRelation is the JSON output of the show and list commands
+type jsonRelation struct {
+	Name       string    `json:"name"`
+	Source     string    `json:"source"`
+	Confidence float64   `json:"confidence,omitempty"`
+	Parent     jsonTable `json:"parent"`
+	Child      jsonTable `json:"child"`
+}
+
+type jsonTable struct {
+	Name string   `json:"name"`
+	Keys []string `json:"keys"`
+}
+
+func toJSONRelation(rel relation.Relation) jsonRelation {
+	return jsonRelation{
+		Name:       rel.Name,
+		Source:     rel.Source,
+		Confidence: rel.Confidence,
+		Parent:     jsonTable{Name: rel.Parent.Name, Keys: rel.Parent.Keys},
+		Child:      jsonTable{Name: rel.Child.Name, Keys: rel.Child.Keys},
+	}
+}
+
+// newListCommand implements the cli relation list command
+func newListCommand(fullName string, err *os.File, out *os.File, in *os.File) *cobra.Command {
+	var output string
+
+	cmd := &cobra.Command{
+		Use:     "list",
+		Short:   "List relations of relations.yaml",
+		Long:    "",
+		Example: fmt.Sprintf("  %[1]s relation list\n  %[1]s relation list --output json", fullName),
+		Args:    cobra.NoArgs,
+		Aliases: []string{"ls"},
+		Run: func(cmd *cobra.Command, args []string) {
+			relations, e1 := relation.List(relationStorage)
+			if e1 != nil {
+				fmt.Fprintln(err, e1.Description)
+				os.Exit(1)
+			}
+
+			switch output {
+			case "json":
+				result := []jsonRelation{}
+				for _, rel := range relations {
+					result = append(result, toJSONRelation(rel))
+				}
+				e2 := json.NewEncoder(out).Encode(result)
+				if e2 != nil {
+					fmt.Fprintln(err, e2.Error())
+					os.Exit(1)
+				}
+			case "text":
+				for _, rel := range relations {
+					fmt.Fprintf(out, "%s: %s %v -> %s %v (%s)\n", rel.Name, rel.Parent.Name, rel.Parent.Keys, rel.Child.Name, rel.Child.Keys, rel.Source)
+				}
+			default:
+				fmt.Fprintf(err, "unknown output format %s\n", output)
+				os.Exit(1)
+			}
+		},
+	}
+	cmd.Flags().StringVarP(&output, "output", "o", "text", "Output format (text|json)")
+	cmd.SetOut(out)
+	cmd.SetErr(err)
+	cmd.SetIn(in)
+	return cmd
+}
@@ -0,0 +1,77 @@
+// Copyright (C) 2021 CGI France
+//
+// This file is part of LINO.
+//
+// LINO is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// LINO is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with LINO.  If not, see <http://www.gnu.org/licenses/>.
+
+package relation
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/cgi-fr/lino/pkg/relation"
+	"github.com/spf13/cobra"
+)
+
+// newShowCommand implements the cli relation show command
+func newShowCommand(fullName string, err *os.File, out *os.File, in *os.File) *cobra.Command {
+	var output string
+
+	cmd := &cobra.Command{
+		Use:     "show [Relation Name]",
+		Short:   "Show a relation of relations.yaml",
+		Long:    "",
+		Example: fmt.Sprintf("  %[1]s relation show film_language\n  %[1]s relation show film_language --output json", fullName),
+		Args:    cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			rel, e1 := relation.Get(relationStorage, args[0])
+			if e1 != nil {
+				fmt.Fprintln(err, e1.Description)
+				os.Exit(1)
+			}
+
+			if rel == nil {
+				fmt.Fprintln(err, "no relation named "+args[0])
+				os.Exit(1)
+			}
+
+			switch output {
+			case "json":
+				e2 := json.NewEncoder(out).Encode(toJSONRelation(*rel))
+				if e2 != nil {
+					fmt.Fprintln(err, e2.Error())
+					os.Exit(1)
+				}
+			case "text":
+				fmt.Fprintf(out, "name: %s\n", rel.Name)
+				fmt.Fprintf(out, "source: %s\n", rel.Source)
+				if rel.Confidence > 0 {
+					fmt.Fprintf(out, "confidence: %.2f\n", rel.Confidence)
+				}
+				fmt.Fprintf(out, "parent: %s %v\n", rel.Parent.Name, rel.Parent.Keys)
+				fmt.Fprintf(out, "child: %s %v\n", rel.Child.Name, rel.Child.Keys)
+			default:
+				fmt.Fprintf(err, "unknown output format %s\n", output)
+				os.Exit(1)
+			}
+		},
+	}
+	cmd.Flags().StringVarP(&output, "output", "o", "text", "Output format (text|json)")
+	cmd.SetOut(out)
+	cmd.SetErr(err)
+	cmd.SetIn(in)
+	return cmd
+}
@@ -0,0 +1,86 @@
+// Copyright (C) 2021 CGI France
+//
+// This file is part of LINO.
+//
+// LINO is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// LINO is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with LINO.  If not, see <http://www.gnu.org/licenses/>.
+
+package schema
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/cgi-fr/lino/internal/app/urlbuilder"
+	"github.com/cgi-fr/lino/pkg/dataconnector"
+	"github.com/cgi-fr/lino/pkg/schema"
+	"github.com/spf13/cobra"
+)
+
+// newApplyCommand implements the cli schema apply command
+func newApplyCommand(fullName string, err *os.File, out *os.File, in *os.File) *cobra.Command {
+	var dryRun bool
+
+	cmd := &cobra.Command{
+		Use:     "apply [DB Alias Name]",
+		Short:   "Create the tables and sequences of schema.yaml in database",
+		Long:    "",
+		Example: fmt.Sprintf("  %[1]s schema apply mydatabase\n  %[1]s schema apply mydatabase --dry-run", fullName),
+		Args:    cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			alias, e1 := dataconnector.Get(dataconnectorStorage, args[0])
+			if e1 != nil {
+				fmt.Fprintln(err, e1.Description)
+				os.Exit(1)
+			}
+
+			if alias == nil {
+				fmt.Fprintln(err, "no dataconnector named "+args[0])
+				os.Exit(1)
+			}
+
+			if alias.ReadOnly && !dryRun {
+				fmt.Fprintf(err, "'%s' is a read only dataconnector\n", alias.Name)
+				os.Exit(1)
+			}
+
+			u := urlbuilder.BuildURL(alias, err)
+
+			factory, ok := schemaApplierFactories[u.Unaliased]
+			if !ok {
+				fmt.Fprintln(err, "no applier found for database type")
+				os.Exit(1)
+			}
+
+			statements, e2 := schema.Apply(schemaStorage, factory.New(u.URL.String(), alias.Schema, alias.Options, dryRun))
+			if dryRun {
+				for _, statement := range statements {
+					fmt.Fprintf(out, "%s;\n", statement)
+				}
+			}
+			if e2 != nil {
+				fmt.Fprintln(err, e2.Description)
+				os.Exit(1)
+			}
+
+			if !dryRun {
+				fmt.Fprintf(out, "lino executes %v statement(s)\n", len(statements))
+			}
+		},
+	}
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Print the statements without executing them")
+	cmd.SetOut(out)
+	cmd.SetErr(err)
+	cmd.SetIn(in)
+	return cmd
+}
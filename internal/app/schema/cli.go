@@ -0,0 +1,56 @@
+// Copyright (C) 2021 CGI France
+//
+// This file is part of LINO.
+//
+// LINO is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// LINO is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with LINO.  If not, see <http://www.gnu.org/licenses/>.
+
+package schema
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/cgi-fr/lino/pkg/dataconnector"
+	"github.com/cgi-fr/lino/pkg/schema"
+	"github.com/spf13/cobra"
+)
+
+var dataconnectorStorage dataconnector.Storage
+var schemaStorage schema.Storage
+var schemaExtractorFactories map[string]schema.ExtractorFactory
+var schemaApplierFactories map[string]schema.ApplierFactory
+
+// Inject dependencies
+func Inject(dbas dataconnector.Storage, ss schema.Storage, exmap map[string]schema.ExtractorFactory, apmap map[string]schema.ApplierFactory) {
+	dataconnectorStorage = dbas
+	schemaStorage = ss
+	schemaExtractorFactories = exmap
+	schemaApplierFactories = apmap
+}
+
+// NewCommand implements the cli schema command
+func NewCommand(fullName string, err *os.File, out *os.File, in *os.File) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "schema {extract | apply} [arguments ...]",
+		Short:   "Manage database structure",
+		Long:    "",
+		Example: fmt.Sprintf("  %[1]s schema extract source\n  %[1]s schema apply target", fullName),
+	}
+	cmd.AddCommand(newExtractCommand(fullName, err, out, in))
+	cmd.AddCommand(newApplyCommand(fullName, err, out, in))
+	cmd.SetOut(out)
+	cmd.SetErr(err)
+	cmd.SetIn(in)
+	return cmd
+}
@@ -0,0 +1,57 @@
+// Copyright (C) 2021 CGI France
+//
+// This file is part of LINO.
+//
+// LINO is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// LINO is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with LINO.  If not, see <http://www.gnu.org/licenses/>.
+
+package table
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/cgi-fr/lino/pkg/table"
+	"github.com/spf13/cobra"
+)
+
+// newAddCommand implements the cli table add command
+func newAddCommand(fullName string, err *os.File, out *os.File, in *os.File) *cobra.Command {
+	var keys []string
+	var view string
+	var query string
+
+	cmd := &cobra.Command{
+		Use:     "add [Table Name]",
+		Short:   "Add a table to tables.yaml",
+		Long:    "",
+		Example: fmt.Sprintf("  %[1]s table add public.film --keys film_id\n  %[1]s table add film_actor --keys film_id,actor_id\n  %[1]s table add customer_open_claim --keys customer_id --query \"SELECT c.* FROM customer c WHERE c.claim_status = 'open'\"", fullName),
+		Args:    cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			e := table.Add(tableStorage, table.Table{Name: args[0], Keys: keys, View: view, Query: query})
+			if e != nil {
+				fmt.Fprintln(err, e.Description)
+				os.Exit(1)
+			}
+
+			fmt.Fprintln(out, "successfully added table")
+		},
+	}
+	cmd.Flags().StringSliceVar(&keys, "keys", []string{}, "Key columns of the table")
+	cmd.Flags().StringVar(&view, "view", "", "Name of the view backing a virtual table")
+	cmd.Flags().StringVar(&query, "query", "", "SQL query backing a virtual table")
+	cmd.SetOut(out)
+	cmd.SetErr(err)
+	cmd.SetIn(in)
+	return cmd
+}
@@ -40,13 +40,17 @@ func Inject(dbas dataconnector.Storage, rs table.Storage, exmap map[string]table
 // NewCommand implements the cli dataconnector command
 func NewCommand(fullName string, err *os.File, out *os.File, in *os.File) *cobra.Command {
 	cmd := &cobra.Command{
-		Use:     "table {extract} [arguments ...]",
+		Use:     "table {extract | add | remove | show | list} [arguments ...]",
 		Short:   "Manage tables",
 		Long:    "",
 		Example: fmt.Sprintf("  %[1]s table extract mydatabase", fullName),
 		Aliases: []string{"tab"},
 	}
 	cmd.AddCommand(newExtractCommand(fullName, err, out, in))
+	cmd.AddCommand(newAddCommand(fullName, err, out, in))
+	cmd.AddCommand(newRemoveCommand(fullName, err, out, in))
+	cmd.AddCommand(newShowCommand(fullName, err, out, in))
+	cmd.AddCommand(newListCommand(fullName, err, out, in))
 	cmd.SetOut(out)
 	cmd.SetErr(err)
 	cmd.SetIn(in)
@@ -29,11 +29,13 @@ import (
 
 // newExtractCommand implements the cli relation extract command
 func newExtractCommand(fullName string, err *os.File, out *os.File, in *os.File) *cobra.Command {
+	var filter table.Filter
+
 	cmd := &cobra.Command{
 		Use:     "extract [DB Alias Name]",
 		Short:   "Extract tables metadatas from database",
 		Long:    "",
-		Example: fmt.Sprintf("  %[1]s table extract mydatabase", fullName),
+		Example: fmt.Sprintf("  %[1]s table extract mydatabase\n  %[1]s table extract mydatabase --schemas sales,stock --include 'order*' --exclude '/_(tmp|bak)$/'", fullName),
 		Args:    cobra.ExactArgs(1),
 		Run: func(cmd *cobra.Command, args []string) {
 			alias, e1 := dataconnector.Get(dataconnectorStorage, args[0])
@@ -55,9 +57,9 @@ func newExtractCommand(fullName string, err *os.File, out *os.File, in *os.File)
 				os.Exit(1)
 			}
 
-			extractor := factory.New(u.URL.String(), alias.Schema)
+			extractor := factory.New(u.URL.String(), alias.Schema, alias.Options)
 
-			e2 := table.Extract(extractor, tableStorage)
+			report, e2 := table.Extract(extractor, tableStorage, filter)
 			if e2 != nil {
 				fmt.Fprintln(err, e2.Description)
 				os.Exit(1)
@@ -70,8 +72,17 @@ func newExtractCommand(fullName string, err *os.File, out *os.File, in *os.File)
 			}
 
 			fmt.Fprintf(out, "lino finds %v table(s)\n", len(tables))
+			for _, name := range report.UniqueKeyTables {
+				fmt.Fprintf(out, "table %s has no primary key, its unique key is used\n", name)
+			}
+			for _, name := range report.NoKeyTables {
+				fmt.Fprintf(out, "table %s has neither primary nor unique key and is skipped\n", name)
+			}
 		},
 	}
+	cmd.Flags().StringSliceVar(&filter.Schemas, "schemas", []string{}, "Schemas to extract instead of the dataconnector schema, table names are prefixed by their schema")
+	cmd.Flags().StringArrayVar(&filter.Include, "include", []string{}, "Extract only tables matching this glob or /regular expression/ (repeatable)")
+	cmd.Flags().StringArrayVar(&filter.Exclude, "exclude", []string{}, "Don't extract tables matching this glob or /regular expression/ (repeatable)")
 	cmd.SetOut(out)
 	cmd.SetErr(err)
 	cmd.SetIn(in)
@@ -0,0 +1,105 @@
+// Copyright (C) 2021 CGI France
+//
+// This file is part of LINO.
+//
+// LINO is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// LINO is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with LINO.  If not, see <http://www.gnu.org/licenses/>.
+
+package table
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/cgi-fr/lino/pkg/table"
+	"github.com/spf13/cobra"
+)
+
+// jsonTable is the JSON output of the show and list commands
+type jsonTable struct {
+	Name          string       `json:"name"`
+	Keys          []string     `json:"keys"`
+	AlternateKeys [][]string   `json:"alternateKeys,omitempty"`
+	Columns       []jsonColumn `json:"columns,omitempty"`
+	View          string       `json:"view,omitempty"`
+	Query         string       `json:"query,omitempty"`
+}
+
+type jsonColumn struct {
+	Name       string `json:"name"`
+	Type       string `json:"type,omitempty"`
+	Nullable   bool   `json:"nullable"`
+	HasDefault bool   `json:"hasDefault"`
+}
+
+func toJSONTable(t table.Table) jsonTable {
+	result := jsonTable{Name: t.Name, Keys: t.Keys, AlternateKeys: t.AlternateKeys, View: t.View, Query: t.Query}
+	for _, column := range t.Columns {
+		result.Columns = append(result.Columns, jsonColumn{Name: column.Name, Type: column.Type, Nullable: column.Nullable, HasDefault: column.HasDefault})
+	}
+	return result
+}
+
+// newListCommand implements the cli table list command
+func newListCommand(fullName string, err *os.File, out *os.File, in *os.File) *cobra.Command {
+	var output string
+
+	cmd := &cobra.Command{
+		Use:     "list",
+		Short:   "List tables of tables.yaml",
+		Long:    "",
+		Example: fmt.Sprintf("  %[1]s table list\n  %[1]s table list --output json", fullName),
+		Args:    cobra.NoArgs,
+		Aliases: []string{"ls"},
+		Run: func(cmd *cobra.Command, args []string) {
+			tables, e1 := table.List(tableStorage)
+			if e1 != nil {
+				fmt.Fprintln(err, e1.Description)
+				os.Exit(1)
+			}
+
+			switch output {
+			case "json":
+				result := []jsonTable{}
+				for _, t := range tables {
+					result = append(result, toJSONTable(t))
+				}
+				e2 := json.NewEncoder(out).Encode(result)
+				if e2 != nil {
+					fmt.Fprintln(err, e2.Error())
+					os.Exit(1)
+				}
+			case "text":
+				for _, t := range tables {
+					switch {
+					case t.View != "":
+						fmt.Fprintf(out, "%s %v (view %s)\n", t.Name, t.Keys, t.View)
+					case t.Query != "":
+						fmt.Fprintf(out, "%s %v (query)\n", t.Name, t.Keys)
+					default:
+						fmt.Fprintf(out, "%s %v\n", t.Name, t.Keys)
+					}
+				}
+			default:
+				fmt.Fprintf(err, "unknown output format %s\n", output)
+				os.Exit(1)
+			}
+		},
+	}
+	cmd.Flags().StringVarP(&output, "output", "o", "text", "Output format (text|json)")
+	cmd.SetOut(out)
+	cmd.SetErr(err)
+	cmd.SetIn(in)
+	return cmd
+}
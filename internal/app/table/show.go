@@ -0,0 +1,91 @@
+// Copyright (C) 2021 CGI France
+//
+// This file is part of LINO.
+//
+// LINO is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// LINO is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with LINO.  If not, see <http://www.gnu.org/licenses/>.
+
+package table
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/cgi-fr/lino/pkg/table"
+	"github.com/spf13/cobra"
+)
+
+// newShowCommand implements the cli table show command
+func newShowCommand(fullName string, err *os.File, out *os.File, in *os.File) *cobra.Command {
+	var output string
+
+	cmd := &cobra.Command{
+		Use:     "show [Table Name]",
+		Short:   "Show a table of tables.yaml",
+		Long:    "",
+		Example: fmt.Sprintf("  %[1]s table show public.film\n  %[1]s table show public.film --output json", fullName),
+		Args:    cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			t, e1 := table.Get(tableStorage, args[0])
+			if e1 != nil {
+				fmt.Fprintln(err, e1.Description)
+				os.Exit(1)
+			}
+
+			if t == nil {
+				fmt.Fprintln(err, "no table named "+args[0])
+				os.Exit(1)
+			}
+
+			switch output {
+			case "json":
+				e2 := json.NewEncoder(out).Encode(toJSONTable(*t))
+				if e2 != nil {
+					fmt.Fprintln(err, e2.Error())
+					os.Exit(1)
+				}
+			case "text":
+				fmt.Fprintf(out, "name: %s\n", t.Name)
+				fmt.Fprintf(out, "keys: %v\n", t.Keys)
+				if t.View != "" {
+					fmt.Fprintf(out, "view: %s\n", t.View)
+				}
+				if t.Query != "" {
+					fmt.Fprintf(out, "query: %s\n", t.Query)
+				}
+				for _, keys := range t.AlternateKeys {
+					fmt.Fprintf(out, "alternate keys: %v\n", keys)
+				}
+				for _, column := range t.Columns {
+					fmt.Fprintf(out, "column: %s %s", column.Name, column.Type)
+					if !column.Nullable {
+						fmt.Fprint(out, " not null")
+					}
+					if column.HasDefault {
+						fmt.Fprint(out, " default")
+					}
+					fmt.Fprintln(out)
+				}
+			default:
+				fmt.Fprintf(err, "unknown output format %s\n", output)
+				os.Exit(1)
+			}
+		},
+	}
+	cmd.Flags().StringVarP(&output, "output", "o", "text", "Output format (text|json)")
+	cmd.SetOut(out)
+	cmd.SetErr(err)
+	cmd.SetIn(in)
+	return cmd
+}
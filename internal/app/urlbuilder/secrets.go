@@ -0,0 +1,178 @@
+// Copyright (C) 2021 CGI France
+//
+// This file is part of LINO.
+//
+// LINO is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// LINO is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with LINO.  If not, see <http://www.gnu.org/licenses/>.
+
+package urlbuilder
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/cgi-fr/lino/pkg/dataconnector"
+	"github.com/rs/zerolog/log"
+)
+
+// Mask replaces the resolved secrets in redacted texts.
+const Mask = "xxxxx"
+
+var (
+	// cache of the values read from files and commands, they are read once per process
+	cache   = map[string]string{}
+	secrets = map[string]struct{}{}
+	mutex   sync.Mutex
+)
+
+// Source describes where the value of the holder is read from, or returns an empty string if no source is configured.
+func Source(vh dataconnector.ValueHolder) string {
+	switch {
+	case vh.ValueFromEnv != "":
+		return "environment variable " + vh.ValueFromEnv
+	case vh.ValueFromFile != "":
+		return "file " + vh.ValueFromFile
+	case vh.ValueFromCommand != "":
+		return "command " + vh.ValueFromCommand
+	default:
+		return ""
+	}
+}
+
+// resolve the value of the holder from its source, warnings are printed to out if it is not nil.
+func resolve(vh dataconnector.ValueHolder, out io.Writer) (string, bool) {
+	var value string
+	var err error
+
+	switch {
+	case vh.ValueFromEnv != "":
+		value = os.Getenv(vh.ValueFromEnv)
+		if value == "" {
+			err = fmt.Errorf("missing environment variable %s", vh.ValueFromEnv)
+		}
+	case vh.ValueFromFile != "":
+		value, err = cached("file:"+vh.ValueFromFile, func() (string, error) { return readFile(vh.ValueFromFile) })
+	case vh.ValueFromCommand != "":
+		value, err = cached("command:"+vh.ValueFromCommand, func() (string, error) { return runCommand(vh.ValueFromCommand) })
+	default:
+		return vh.Value, vh.Value != ""
+	}
+
+	if err != nil {
+		if out != nil {
+			fmt.Fprintf(out, "warn: %s", err.Error())
+			fmt.Fprintln(out)
+		}
+		return "", false
+	}
+
+	log.Debug().Str("source", Source(vh)).Msg("value resolved")
+
+	return value, true
+}
+
+func cached(key string, read func() (string, error)) (string, error) {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	if value, ok := cache[key]; ok {
+		return value, nil
+	}
+
+	value, err := read()
+	if err != nil {
+		return "", err
+	}
+
+	cache[key] = value
+	return value, nil
+}
+
+func readFile(filename string) (string, error) {
+	dat, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return "", fmt.Errorf("failed to read file %s: %w", filename, err)
+	}
+
+	value := strings.TrimRight(string(dat), "\r\n")
+	if value == "" {
+		return "", fmt.Errorf("empty file %s", filename)
+	}
+
+	return value, nil
+}
+
+func runCommand(command string) (string, error) {
+	var stdout, stderr bytes.Buffer
+
+	cmd := shellCommand(command)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		// only the error output is shown, the standard output may contain the secret
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("failed to run command %s: %w (%s)", command, err, msg)
+		}
+		return "", fmt.Errorf("failed to run command %s: %w", command, err)
+	}
+
+	value := strings.TrimRight(stdout.String(), "\r\n")
+	if value == "" {
+		return "", fmt.Errorf("empty output of command %s", command)
+	}
+
+	return value, nil
+}
+
+// register a secret to redact.
+func register(secret string) {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	secrets[secret] = struct{}{}
+}
+
+// Redact replaces the secrets resolved by this process in the text.
+func Redact(text string) string {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	for secret := range secrets {
+		text = strings.ReplaceAll(text, secret, Mask)
+	}
+
+	return text
+}
+
+// RedactWriter wraps a writer to redact the secrets resolved by this process, it is used for the logs output.
+func RedactWriter(w io.Writer) io.Writer {
+	return redactWriter{w}
+}
+
+type redactWriter struct {
+	w io.Writer
+}
+
+func (rw redactWriter) Write(p []byte) (int, error) {
+	_, err := io.WriteString(rw.w, Redact(string(p)))
+	if err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
@@ -75,50 +75,45 @@ func BuildURL(dc *dataconnector.DataConnector, out io.Writer) *dburl.URL {
 		fmt.Fprintln(out, e2.Error())
 		os.Exit(3)
 	}
-	// get user from env
-	if dc.User.ValueFromEnv != "" {
-		userFromEnv := os.Getenv(dc.User.ValueFromEnv)
-		if userFromEnv == "" {
-			if out != nil {
-				fmt.Fprintf(out, "warn: missing environment variable %s", dc.User.ValueFromEnv)
-				fmt.Fprintln(out)
-			}
-		} else {
-			u.User = url.User(userFromEnv)
-		}
-	} else if dc.User.Value != "" {
-		// set user from dc
-		u.User = url.User(dc.User.Value)
+	// get user from env, file or command
+	if user, ok := resolve(dc.User, out); ok {
+		u.User = url.User(user)
 	}
-	// get password from env
-	if dc.Password.ValueFromEnv != "" {
-		passwordFromEnv := os.Getenv(dc.Password.ValueFromEnv)
-		if passwordFromEnv == "" {
-			if out != nil {
-				fmt.Fprintf(out, "warn: missing environment variable %s", dc.Password.ValueFromEnv)
-				fmt.Fprintln(out)
-			}
-		} else {
-			u.User = url.UserPassword(u.User.Username(), passwordFromEnv)
-		}
+	// get password from env, file or command
+	if password, ok := resolve(dc.Password, out); ok {
+		u.User = url.UserPassword(u.User.Username(), password)
 	}
 	// if credentials still missing, check default store
 	username := u.User.Username()
 	_, passwordIsSet := u.User.Password()
 	if username == "" || !passwordIsSet {
-		store := defaultCredentialsStore()
-		creds, err := client.Get(store, u.String())
-		if err != nil {
-			// failed to use credential store backend, fallback to local storage
-			creds, err = localstorage.Read(u.String())
-		}
+		creds, err := readPassword(u, out)
 		if err == nil {
 			u.User = url.UserPassword(creds.Username, creds.Secret)
 		}
 	}
+	// the password must not appear in the logs
+	if password, ok := u.User.Password(); ok && password != "" {
+		register(password)
+	}
 	return u
 }
 
+// readPassword reads the credentials of the URL from the default credentials store, or from the local storage.
+func readPassword(u *dburl.URL, out io.Writer) (*credentials.Credentials, error) {
+	store := defaultCredentialsStore()
+	creds, err := client.Get(store, u.String())
+	if err != nil {
+		// failed to use credential store backend, fallback to local storage
+		creds, err = localstorage.Read(u.String())
+		if err != nil && !credentials.IsErrCredentialsNotFound(err) && out != nil {
+			fmt.Fprintf(out, "warn: cannot read password from %s: %s", localstorage.GetFileLocation(), err.Error())
+			fmt.Fprintln(out)
+		}
+	}
+	return creds, err
+}
+
 func StorePassword(u *dburl.URL, password string, out io.Writer) error {
 	store := defaultCredentialsStore()
 	creds := &credentials.Credentials{ServerURL: u.URL.String(), Username: u.URL.User.Username(), Secret: password}
@@ -129,9 +124,45 @@ func StorePassword(u *dburl.URL, password string, out io.Writer) error {
 			return err
 		}
 		// fall back to local storage
-		fmt.Fprintf(out, "warn: password will be stored unencrypted in %s, configure a credential helper to remove this warning. See https://github.com/docker/docker-credential-helpers", localstorage.GetFileLocation())
+		fmt.Fprintf(out, "warn: password will be stored in %s encrypted with a master password, configure a credential helper to remove this warning. See https://github.com/docker/docker-credential-helpers", localstorage.GetFileLocation())
 		fmt.Fprintln(out)
 		return localstorage.Store(creds)
 	}
 	return nil
 }
+
+// DeletePassword removes the credentials of the URL from the default credentials store and from the local storage.
+func DeletePassword(u *dburl.URL) error {
+	store := defaultCredentialsStore()
+	for _, serverURL := range []string{u.URL.String(), u.String()} {
+		// the credential store backend may be missing, errors are ignored as the credentials can be in local storage
+		_ = client.Erase(store, serverURL)
+
+		err := localstorage.Delete(serverURL)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// MovePassword stores the password of the from URL under the to URL, then deletes it from the from URL unless keep is set.
+// The password is not moved if the URLs have different usernames, it returns false when no password has been moved.
+func MovePassword(from *dburl.URL, to *dburl.URL, keep bool, out io.Writer) (bool, error) {
+	creds, err := readPassword(from, out)
+	if err != nil {
+		return false, nil
+	}
+	if creds.Username != to.User.Username() {
+		fmt.Fprintf(out, "warn: the password stored for user %s has not been moved to the new url of user %s", creds.Username, to.User.Username())
+		fmt.Fprintln(out)
+		return false, nil
+	}
+	if err := StorePassword(to, creds.Secret, out); err != nil {
+		return false, err
+	}
+	if keep {
+		return true, nil
+	}
+	return true, DeletePassword(from)
+}
@@ -0,0 +1,176 @@
+// Copyright (C) 2021 CGI France
+//
+// This file is part of LINO.
+//
+// LINO is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// LINO is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with LINO.  If not, see <http://www.gnu.org/licenses/>.
+
+package connection
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+
+	"github.com/cgi-fr/lino/pkg/dataconnector"
+	"github.com/rs/zerolog/log"
+	"github.com/xo/dburl"
+)
+
+// Open a database from the URL, the options are applied to each connection of the pool.
+func Open(rawurl string, options dataconnector.Options) (*sql.DB, error) {
+	u, err := dburl.Parse(rawurl)
+	if err != nil {
+		return nil, err
+	}
+
+	options.SessionInit = sessionInit(u.Driver, options)
+	return open(u.Driver, u.DSN, options)
+}
+
+// sessionInit returns the statements run on each connection, with the statement timeout first if the driver supports it.
+func sessionInit(driverName string, options dataconnector.Options) []string {
+	if options.StatementTimeout <= 0 {
+		return options.SessionInit
+	}
+	if driverName != "postgres" {
+		// dataconnector add and update refuse the statement timeout for other drivers
+		log.Warn().Str("driver", driverName).Msg("statement timeout is not supported by this driver and is ignored")
+		return options.SessionInit
+	}
+	statement := fmt.Sprintf("SET statement_timeout = %d", options.StatementTimeout.Milliseconds())
+	return append([]string{statement}, options.SessionInit...)
+}
+
+func open(driverName string, dsn string, options dataconnector.Options) (*sql.DB, error) {
+	db, err := sql.Open(driverName, dsn)
+	if err != nil {
+		return nil, err
+	}
+
+	if options.ConnectTimeout == 0 && len(options.SessionInit) == 0 {
+		db.SetMaxOpenConns(options.MaxOpenConns)
+		return db, nil
+	}
+
+	// the driver is only needed to open the connections with the options
+	drv := db.Driver()
+	db.Close()
+
+	c := &connector{drv: drv, dsn: dsn, options: options}
+	if dc, ok := drv.(driver.DriverContext); ok {
+		c.inner, err = dc.OpenConnector(dsn)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	db = sql.OpenDB(c)
+	db.SetMaxOpenConns(options.MaxOpenConns)
+	return db, nil
+}
+
+// connector opens connections with a timeout and runs the session init statements on each of them.
+type connector struct {
+	drv     driver.Driver
+	inner   driver.Connector
+	dsn     string
+	options dataconnector.Options
+}
+
+func (c *connector) Connect(ctx context.Context) (driver.Conn, error) {
+	if c.options.ConnectTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.options.ConnectTimeout)
+		defer cancel()
+	}
+
+	conn, err := c.connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, statement := range c.options.SessionInit {
+		log.Debug().Str("statement", statement).Msg("session init")
+		if err := exec(ctx, conn, statement); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("session init (%s): %w", statement, err)
+		}
+	}
+
+	return conn, nil
+}
+
+func (c *connector) Driver() driver.Driver {
+	return c.drv
+}
+
+func (c *connector) connect(ctx context.Context) (driver.Conn, error) {
+	if c.inner != nil {
+		return c.inner.Connect(ctx)
+	}
+
+	type result struct {
+		conn driver.Conn
+		err  error
+	}
+
+	// the driver does not support contexts, the connection is closed when it is opened after the timeout
+	results := make(chan result, 1)
+	go func() {
+		conn, err := c.drv.Open(c.dsn)
+		results <- result{conn, err}
+	}()
+
+	select {
+	case r := <-results:
+		return r.conn, r.err
+	case <-ctx.Done():
+		go func() {
+			if r := <-results; r.conn != nil {
+				r.conn.Close()
+			}
+		}()
+		return nil, fmt.Errorf("connect timeout: %w", ctx.Err())
+	}
+}
+
+func exec(ctx context.Context, conn driver.Conn, statement string) error {
+	if execer, ok := conn.(driver.ExecerContext); ok {
+		_, err := execer.ExecContext(ctx, statement, nil)
+		if err != driver.ErrSkip {
+			return err
+		}
+	}
+
+	var stmt driver.Stmt
+	var err error
+	if preparer, ok := conn.(driver.ConnPrepareContext); ok {
+		stmt, err = preparer.PrepareContext(ctx, statement)
+	} else {
+		stmt, err = conn.Prepare(statement)
+	}
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	if execer, ok := stmt.(driver.StmtExecContext); ok {
+		_, err = execer.ExecContext(ctx, nil)
+		return err
+	}
+
+	_, err = stmt.Exec(nil)
+	return err
+}
@@ -0,0 +1,113 @@
+// Copyright (C) 2021 CGI France
+//
+// This file is part of LINO.
+//
+// LINO is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// LINO is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with LINO.  If not, see <http://www.gnu.org/licenses/>.
+
+package connection
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cgi-fr/lino/pkg/dataconnector"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeDriver records the statements executed on its connections
+type fakeDriver struct {
+	mutex      sync.Mutex
+	statements []string
+	delay      time.Duration
+}
+
+func (d *fakeDriver) Open(name string) (driver.Conn, error) {
+	time.Sleep(d.delay)
+	return &fakeConn{d}, nil
+}
+
+type fakeConn struct {
+	d *fakeDriver
+}
+
+func (c *fakeConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	if query == "fail" {
+		return nil, errors.New("syntax error")
+	}
+	c.d.mutex.Lock()
+	defer c.d.mutex.Unlock()
+	c.d.statements = append(c.d.statements, query)
+	return driver.RowsAffected(0), nil
+}
+
+func (c *fakeConn) Prepare(query string) (driver.Stmt, error) {
+	return nil, errors.New("not implemented")
+}
+func (c *fakeConn) Close() error              { return nil }
+func (c *fakeConn) Begin() (driver.Tx, error) { return nil, errors.New("not implemented") }
+
+var (
+	fake     = &fakeDriver{}
+	slowFake = &fakeDriver{delay: time.Second}
+)
+
+func init() {
+	sql.Register("lino-fake", fake)
+	sql.Register("lino-slow-fake", slowFake)
+}
+
+func TestOpenWithSessionInit(t *testing.T) {
+	db, err := open("lino-fake", "", dataconnector.Options{MaxOpenConns: 2, SessionInit: []string{"SET search_path TO sakila", "SET TIME ZONE 'UTC'"}})
+	assert.Nil(t, err)
+	defer db.Close()
+
+	assert.Equal(t, 2, db.Stats().MaxOpenConnections)
+
+	// each new connection of the pool runs the statements
+	c1, err := db.Conn(context.Background())
+	assert.Nil(t, err)
+	c2, err := db.Conn(context.Background())
+	assert.Nil(t, err)
+	c1.Close()
+	c2.Close()
+
+	assert.Equal(t, []string{"SET search_path TO sakila", "SET TIME ZONE 'UTC'", "SET search_path TO sakila", "SET TIME ZONE 'UTC'"}, fake.statements)
+
+	db2, err := open("lino-fake", "", dataconnector.Options{SessionInit: []string{"fail"}})
+	assert.Nil(t, err)
+	defer db2.Close()
+
+	assert.EqualError(t, db2.Ping(), "session init (fail): syntax error")
+}
+
+func TestOpenWithConnectTimeout(t *testing.T) {
+	db, err := open("lino-slow-fake", "", dataconnector.Options{ConnectTimeout: 10 * time.Millisecond})
+	assert.Nil(t, err)
+	defer db.Close()
+
+	assert.True(t, errors.Is(db.Ping(), context.DeadlineExceeded))
+}
+
+func TestStatementTimeout(t *testing.T) {
+	options := dataconnector.Options{StatementTimeout: time.Minute, SessionInit: []string{"SET search_path TO sakila"}}
+
+	assert.Equal(t, []string{"SET statement_timeout = 60000", "SET search_path TO sakila"}, sessionInit("postgres", options))
+	assert.Equal(t, []string{"SET search_path TO sakila"}, sessionInit("godror", options))
+	assert.Equal(t, []string{"SET search_path TO sakila"}, sessionInit("postgres", dataconnector.Options{SessionInit: options.SessionInit}))
+}
@@ -43,3 +43,25 @@ func (s *MemoryStorage) Store(m *dataconnector.DataConnector) *dataconnector.Err
 	s.repo = append(s.repo, *m)
 	return nil
 }
+
+// Update a dataconnector in memory
+func (s *MemoryStorage) Update(name string, m *dataconnector.DataConnector) *dataconnector.Error {
+	for i, alias := range s.repo {
+		if alias.Name == name {
+			s.repo[i] = *m
+		}
+	}
+	return nil
+}
+
+// Remove a dataconnector from memory
+func (s *MemoryStorage) Remove(name string) *dataconnector.Error {
+	repo := []dataconnector.DataConnector{}
+	for _, alias := range s.repo {
+		if alias.Name != name {
+			repo = append(repo, alias)
+		}
+	}
+	s.repo = repo
+	return nil
+}
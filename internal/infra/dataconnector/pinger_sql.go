@@ -18,6 +18,7 @@
 package dataconnector
 
 import (
+	"github.com/cgi-fr/lino/internal/infra/connection"
 	"github.com/cgi-fr/lino/pkg/dataconnector"
 	"github.com/jmoiron/sqlx"
 	"github.com/xo/dburl"
@@ -30,20 +31,21 @@ func NewSQLDataPingerFactory() *SQLDataPingerFactory {
 	return &SQLDataPingerFactory{}
 }
 
-func (pdpf SQLDataPingerFactory) New(url string) dataconnector.DataPinger {
-	return NewSQLDataPinger(url)
+func (pdpf SQLDataPingerFactory) New(url string, options dataconnector.Options) dataconnector.DataPinger {
+	return NewSQLDataPinger(url, options)
 }
 
-func NewSQLDataPinger(url string) SQLDataPinger {
-	return SQLDataPinger{url}
+func NewSQLDataPinger(url string, options dataconnector.Options) SQLDataPinger {
+	return SQLDataPinger{url, options}
 }
 
 type SQLDataPinger struct {
-	url string
+	url     string
+	options dataconnector.Options
 }
 
 func (pdp SQLDataPinger) Ping() *dataconnector.Error {
-	db, err := dburl.Open(pdp.url)
+	db, err := connection.Open(pdp.url, pdp.options)
 	if err != nil {
 		return &dataconnector.Error{Description: err.Error()}
 	}
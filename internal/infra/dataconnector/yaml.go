@@ -20,6 +20,7 @@ package dataconnector
 import (
 	"io/ioutil"
 	"os"
+	"time"
 
 	"gopkg.in/yaml.v3"
 
@@ -43,11 +44,18 @@ type YAMLDataConnector struct {
 	Schema   string           `yaml:"schema,omitempty"`
 	User     *YAMLValueHolder `yaml:"user,omitempty"`
 	Password *YAMLValueHolder `yaml:"password,omitempty"`
+
+	MaxOpenConns     int      `yaml:"maxOpenConns,omitempty"`
+	ConnectTimeout   string   `yaml:"connectTimeout,omitempty"`
+	StatementTimeout string   `yaml:"statementTimeout,omitempty"`
+	SessionInit      []string `yaml:"sessionInit,omitempty"`
 }
 
 type YAMLValueHolder struct {
-	Value        *string `yaml:"value,omitempty"`
-	ValueFromEnv *string `yaml:"valueFromEnv,omitempty"`
+	Value            *string `yaml:"value,omitempty"`
+	ValueFromEnv     *string `yaml:"valueFromEnv,omitempty"`
+	ValueFromFile    *string `yaml:"valueFromFile,omitempty"`
+	ValueFromCommand *string `yaml:"valueFromCommand,omitempty"`
 }
 
 // NewYAMLStorage create a new YAML storage
@@ -81,11 +89,28 @@ func (s YAMLStorage) List() ([]dataconnector.DataConnector, *dataconnector.Error
 			if ym.User.ValueFromEnv != nil {
 				m.User.ValueFromEnv = *ym.User.ValueFromEnv
 			}
+			if ym.User.ValueFromFile != nil {
+				m.User.ValueFromFile = *ym.User.ValueFromFile
+			}
+			if ym.User.ValueFromCommand != nil {
+				m.User.ValueFromCommand = *ym.User.ValueFromCommand
+			}
+		}
+		options, err := toOptions(ym)
+		if err != nil {
+			return nil, err
 		}
+		m.Options = options
 		if ym.Password != nil {
 			if ym.Password.ValueFromEnv != nil {
 				m.Password.ValueFromEnv = *ym.Password.ValueFromEnv
 			}
+			if ym.Password.ValueFromFile != nil {
+				m.Password.ValueFromFile = *ym.Password.ValueFromFile
+			}
+			if ym.Password.ValueFromCommand != nil {
+				m.Password.ValueFromCommand = *ym.Password.ValueFromCommand
+			}
 		}
 		result = append(result, m)
 	}
@@ -100,6 +125,51 @@ func (s YAMLStorage) Store(m *dataconnector.DataConnector) *dataconnector.Error
 		return err
 	}
 
+	list.DataConnectors = append(list.DataConnectors, toYAML(m))
+
+	err = writeFile(list)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Update the dataconnector named name in the YAML file
+func (s YAMLStorage) Update(name string, m *dataconnector.DataConnector) *dataconnector.Error {
+	list, err := readFile()
+	if err != nil {
+		return err
+	}
+
+	for i, ym := range list.DataConnectors {
+		if ym.Name == name {
+			list.DataConnectors[i] = toYAML(m)
+		}
+	}
+
+	return writeFile(list)
+}
+
+// Remove the dataconnector named name from the YAML file
+func (s YAMLStorage) Remove(name string) *dataconnector.Error {
+	list, err := readFile()
+	if err != nil {
+		return err
+	}
+
+	dataconnectors := []YAMLDataConnector{}
+	for _, ym := range list.DataConnectors {
+		if ym.Name != name {
+			dataconnectors = append(dataconnectors, ym)
+		}
+	}
+	list.DataConnectors = dataconnectors
+
+	return writeFile(list)
+}
+
+func toYAML(m *dataconnector.DataConnector) YAMLDataConnector {
 	yml := YAMLDataConnector{
 		Name:     m.Name,
 		URL:      m.URL,
@@ -107,9 +177,19 @@ func (s YAMLStorage) Store(m *dataconnector.DataConnector) *dataconnector.Error
 		Schema:   m.Schema,
 		User:     nil,
 		Password: nil,
+
+		MaxOpenConns: m.Options.MaxOpenConns,
+		SessionInit:  m.Options.SessionInit,
 	}
 
-	if m.User.ValueFromEnv != "" || m.User.Value != "" {
+	if m.Options.ConnectTimeout > 0 {
+		yml.ConnectTimeout = m.Options.ConnectTimeout.String()
+	}
+	if m.Options.StatementTimeout > 0 {
+		yml.StatementTimeout = m.Options.StatementTimeout.String()
+	}
+
+	if m.User.ValueFromEnv != "" || m.User.Value != "" || m.User.ValueFromFile != "" || m.User.ValueFromCommand != "" {
 		yml.User = &YAMLValueHolder{}
 		if m.User.ValueFromEnv != "" {
 			yml.User.ValueFromEnv = &m.User.ValueFromEnv
@@ -117,23 +197,49 @@ func (s YAMLStorage) Store(m *dataconnector.DataConnector) *dataconnector.Error
 		if m.User.Value != "" {
 			yml.User.Value = &m.User.Value
 		}
+		if m.User.ValueFromFile != "" {
+			yml.User.ValueFromFile = &m.User.ValueFromFile
+		}
+		if m.User.ValueFromCommand != "" {
+			yml.User.ValueFromCommand = &m.User.ValueFromCommand
+		}
 	}
 
-	if m.Password.ValueFromEnv != "" {
+	if m.Password.ValueFromEnv != "" || m.Password.ValueFromFile != "" || m.Password.ValueFromCommand != "" {
 		yml.Password = &YAMLValueHolder{}
 		if m.Password.ValueFromEnv != "" {
 			yml.Password.ValueFromEnv = &m.Password.ValueFromEnv
 		}
+		if m.Password.ValueFromFile != "" {
+			yml.Password.ValueFromFile = &m.Password.ValueFromFile
+		}
+		if m.Password.ValueFromCommand != "" {
+			yml.Password.ValueFromCommand = &m.Password.ValueFromCommand
+		}
 	}
 
-	list.DataConnectors = append(list.DataConnectors, yml)
+	return yml
+}
 
-	err = writeFile(list)
-	if err != nil {
-		return err
+func toOptions(ym YAMLDataConnector) (dataconnector.Options, *dataconnector.Error) {
+	options := dataconnector.Options{
+		MaxOpenConns: ym.MaxOpenConns,
+		SessionInit:  ym.SessionInit,
 	}
 
-	return nil
+	var err error
+	if ym.ConnectTimeout != "" {
+		if options.ConnectTimeout, err = time.ParseDuration(ym.ConnectTimeout); err != nil {
+			return options, &dataconnector.Error{Description: "invalid connectTimeout of dataconnector " + ym.Name + ": " + err.Error()}
+		}
+	}
+	if ym.StatementTimeout != "" {
+		if options.StatementTimeout, err = time.ParseDuration(ym.StatementTimeout); err != nil {
+			return options, &dataconnector.Error{Description: "invalid statementTimeout of dataconnector " + ym.Name + ": " + err.Error()}
+		}
+	}
+
+	return options, nil
 }
 
 func readFile() (*YAMLStructure, *dataconnector.Error) {
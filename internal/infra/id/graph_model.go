@@ -0,0 +1,119 @@
+// Copyright (C) 2021 CGI France
+//
+// This file is part of LINO.
+//
+// LINO is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// LINO is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with LINO.  If not, see <http://www.gnu.org/licenses/>.
+
+package id
+
+import (
+	"sort"
+	"strconv"
+
+	"github.com/cgi-fr/lino/pkg/id"
+)
+
+// graphModel is a format independent representation of a puller plan graph.
+type graphModel struct {
+	tables   []string
+	steps    map[string]uint // step number of each table
+	clusters []graphCluster  // components of the plan with more than one table (contains cycles)
+	edges    []graphEdge
+}
+
+type graphCluster struct {
+	step   uint
+	tables []string
+}
+
+type graphEdge struct {
+	parent string
+	child  string
+	name   string
+	arrow  string // lookup direction
+}
+
+func newGraphModel(ep id.PullerPlan) graphModel {
+	g := graphModel{
+		tables:   []string{},
+		steps:    map[string]uint{},
+		clusters: []graphCluster{},
+		edges:    []graphEdge{},
+	}
+
+	for i := uint(0); i < ep.Tables().Len(); i++ {
+		g.tables = append(g.tables, ep.Tables().Table(i).Name())
+	}
+	sort.Strings(g.tables)
+
+	for i := uint(0); i < ep.Len(); i++ {
+		step := ep.Step(i)
+		tables := []string{}
+		for j := uint(0); j < step.Tables().Len(); j++ {
+			table := step.Tables().Table(j).Name()
+			if _, ok := g.steps[table]; !ok {
+				g.steps[table] = step.Index()
+			}
+			tables = append(tables, table)
+		}
+		if _, ok := g.steps[step.Entry().Name()]; !ok {
+			g.steps[step.Entry().Name()] = step.Index()
+		}
+		if len(tables) > 1 {
+			sort.Strings(tables)
+			g.clusters = append(g.clusters, graphCluster{step: step.Index(), tables: tables})
+		}
+	}
+
+	for i := uint(0); i < ep.Relations().Len(); i++ {
+		rel := ep.Relations().Relation(i)
+		edge := graphEdge{parent: rel.Parent().Name(), child: rel.Child().Name(), name: rel.Name()}
+		switch {
+		case rel.LookUpChild() && rel.LookUpParent():
+			edge.arrow = `↔`
+		case rel.LookUpChild():
+			edge.arrow = `→`
+		case rel.LookUpParent():
+			edge.arrow = `←`
+		}
+		g.edges = append(g.edges, edge)
+	}
+	sort.Slice(g.edges, func(i, j int) bool { return g.edges[i].name < g.edges[j].name })
+
+	return g
+}
+
+// nodeIDs returns a short identifier for each table, usable in all formats
+func (g graphModel) nodeIDs() map[string]string {
+	result := map[string]string{}
+	for i, table := range g.tables {
+		result[table] = "t" + strconv.Itoa(i)
+	}
+	for _, edge := range g.edges {
+		for _, table := range []string{edge.parent, edge.child} {
+			if _, ok := result[table]; !ok {
+				result[table] = "t" + strconv.Itoa(len(result))
+			}
+		}
+	}
+	return result
+}
+
+// label of a relation of a relation with its lookup direction
+func (e graphEdge) label() string {
+	if e.arrow == "" {
+		return e.name
+	}
+	return e.arrow + " " + e.name
+}
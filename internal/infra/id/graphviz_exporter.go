@@ -18,101 +18,127 @@
 package id
 
 import (
+	"bytes"
 	"fmt"
-	"os"
+	"io"
 	"os/exec"
-	"path/filepath"
 	"runtime"
 	"strconv"
-	"strings"
 
 	"github.com/awalterschulze/gographviz"
 
 	"github.com/cgi-fr/lino/pkg/id"
 )
 
-// GraphVizExporter export to SVG graph representation and open browser.
-type GraphVizExporter struct{}
+// DOTExporter export to graphviz DOT format.
+type DOTExporter struct {
+	out io.Writer
+}
+
+// NewDOTExporter create a new DOTExporter
+func NewDOTExporter(out io.Writer) id.Exporter {
+	return &DOTExporter{out: out}
+}
+
+// Export the puller plan in DOT format.
+func (e *DOTExporter) Export(ep id.PullerPlan) *id.Error {
+	graph, err := dotGraph(ep)
+	if err != nil {
+		return err
+	}
+
+	_, e2 := fmt.Fprint(e.out, graph)
+	if e2 != nil {
+		return &id.Error{Description: e2.Error()}
+	}
+
+	return nil
+}
+
+// GraphVizExporter export to SVG graph representation, it requires the graphviz dot binary.
+type GraphVizExporter struct {
+	out io.Writer
+}
 
 // NewGraphVizExporter create a new GraphVizExporter
-func NewGraphVizExporter() *GraphVizExporter {
-	return &GraphVizExporter{}
+func NewGraphVizExporter(out io.Writer) id.Exporter {
+	return &GraphVizExporter{out: out}
 }
 
-// Export to a temporary svg file and open it.
+// Export the puller plan in SVG format.
 func (e *GraphVizExporter) Export(ep id.PullerPlan) *id.Error {
 	dotexe, err := exec.LookPath("dot")
 	if err != nil {
 		return &id.Error{Description: err.Error()}
 	}
 
+	graph, e2 := dotGraph(ep)
+	if e2 != nil {
+		return e2
+	}
+
+	stderr := &bytes.Buffer{}
+	cmd := exec.Command(dotexe, "-Tsvg")
+	cmd.Stdin = bytes.NewBufferString(graph)
+	cmd.Stdout = e.out
+	cmd.Stderr = stderr
+	if err := cmd.Run(); err != nil {
+		return &id.Error{Description: fmt.Sprintf("%s %s", err.Error(), stderr.String())}
+	}
+
+	return nil
+}
+
+func dotGraph(ep id.PullerPlan) (string, *id.Error) {
+	model := newGraphModel(ep)
+	nodes := model.nodeIDs()
+
 	graphName := "G"
 	graphViz := gographviz.NewGraph()
-	err = graphViz.SetName(graphName)
+	err := graphViz.SetName(graphName)
 	if err != nil {
-		return &id.Error{Description: err.Error()}
+		return "", &id.Error{Description: err.Error()}
 	}
 	err = graphViz.SetDir(true)
 	if err != nil {
-		return &id.Error{Description: err.Error()}
+		return "", &id.Error{Description: err.Error()}
 	}
-	for i := uint(0); i < ep.Tables().Len(); i++ {
-		err = graphViz.AddNode(graphName, strconv.Quote(ep.Tables().Table(i).Name()), nil)
+
+	clustered := map[string]string{}
+	for i, cluster := range model.clusters {
+		compName := "cluster" + fmt.Sprint(i)
+		err = graphViz.AddSubGraph(graphName, compName, map[string]string{"label": strconv.Quote(fmt.Sprintf("step %d (cycles)", cluster.step))})
 		if err != nil {
-			return &id.Error{Description: err.Error()}
+			return "", &id.Error{Description: err.Error()}
 		}
-	}
-	for i := uint(0); i < ep.Len(); i++ {
-		if ep.Step(i).Tables().Len() > 1 {
-			compName := strconv.Quote("cluster" + fmt.Sprint(i))
-			err = graphViz.AddSubGraph(graphName, compName, nil)
-			if err != nil {
-				return &id.Error{Description: err.Error()}
-			}
-			for j := uint(0); j < ep.Step(i).Tables().Len(); j++ {
-				table := ep.Step(i).Tables().Table(j)
-				err = graphViz.AddNode(compName, strconv.Quote(table.Name()), nil)
-				if err != nil {
-					return &id.Error{Description: err.Error()}
-				}
-			}
+		for _, table := range cluster.tables {
+			clustered[table] = compName
 		}
 	}
-	for i := uint(0); i < ep.Relations().Len(); i++ {
-		rel := ep.Relations().Relation(i)
-		var relname string
-		switch {
-		case rel.LookUpChild() && rel.LookUpParent():
-			relname = `↔`
-		case rel.LookUpChild():
-			relname = `→`
-		case rel.LookUpParent():
-			relname = `←`
-		}
 
-		err = graphViz.AddEdge(strconv.Quote(rel.Parent().Name()), strconv.Quote(rel.Child().Name()), true, map[string]string{"label": relname})
+	for _, table := range model.tables {
+		parent, ok := clustered[table]
+		if !ok {
+			parent = graphName
+		}
+		err = graphViz.AddNode(parent, nodes[table], map[string]string{"label": strconv.Quote(fmt.Sprintf("%s\nstep %d", table, model.steps[table]))})
 		if err != nil {
-			return &id.Error{Description: err.Error()}
+			return "", &id.Error{Description: err.Error()}
 		}
 	}
 
-	file := filepath.Join(os.TempDir(), "lino-graph-export.svg")
-
-	cmd := exec.Command(dotexe, "-Tsvg", "-o", file)
-	cmd.Stdin = strings.NewReader(graphViz.String())
-	if err := cmd.Run(); err != nil {
-		return &id.Error{Description: err.Error()}
-	}
-
-	err = open(file)
-	if err != nil {
-		return &id.Error{Description: err.Error()}
+	for _, edge := range model.edges {
+		err = graphViz.AddEdge(nodes[edge.parent], nodes[edge.child], true, map[string]string{"label": strconv.Quote(edge.label())})
+		if err != nil {
+			return "", &id.Error{Description: err.Error()}
+		}
 	}
 
-	return nil
+	return graphViz.String(), nil
 }
 
-func open(url string) error {
+// OpenBrowser open the file with the default application of the platform.
+func OpenBrowser(url string) error {
 	var err error
 
 	switch runtime.GOOS {
@@ -0,0 +1,58 @@
+// Copyright (C) 2021 CGI France
+//
+// This file is part of LINO.
+//
+// LINO is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// LINO is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with LINO.  If not, see <http://www.gnu.org/licenses/>.
+
+package id
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/cgi-fr/lino/pkg/id"
+)
+
+func TestDOTExporter_Export(t *testing.T) {
+	a := id.NewTable("A")
+	b := id.NewTable("B")
+	c := id.NewTable("C")
+	storage := memoryStorage{id.NewIngressDescriptor(a, id.NewIngressRelationList([]id.IngressRelation{
+		id.NewIngressRelation(id.NewRelation("a_b", a, b), true, true),
+		id.NewIngressRelation(id.NewRelation("c_a", c, a), true, false),
+	}))}
+
+	want := `digraph G {
+	t0->t1[ label="↔ a_b" ];
+	t2->t0[ label="← c_a" ];
+	subgraph cluster0 {
+	label="step 1 (cycles)";
+	t0 [ label="A\nstep 1" ];
+	t1 [ label="B\nstep 1" ];
+
+}
+;
+	t2 [ label="C\nstep 2" ];
+
+}
+`
+
+	out := &bytes.Buffer{}
+	if err := id.Export(storage, NewDOTExporter(out)); err != nil {
+		t.Fatalf("DOTExporter.Export() error = %v", err.Description)
+	}
+	if got := out.String(); got != want {
+		t.Errorf("DOTExporter.Export() = %v, want %v", got, want)
+	}
+}
@@ -19,6 +19,7 @@ package id
 
 import (
 	"io/ioutil"
+	"os"
 	"strconv"
 
 	"github.com/awalterschulze/gographviz"
@@ -26,13 +27,20 @@ import (
 )
 
 // DOTStorage provides storage in a graphviz DOT format
-type DOTStorage struct{}
+type DOTStorage struct {
+	name string
+}
 
 // NewDOTStorage create a new DOT storage
 func NewDOTStorage() *DOTStorage {
 	return &DOTStorage{}
 }
 
+// NewNamedDOTStorage create a new DOT storage for the named ingress descriptor in the ingress directory
+func NewNamedDOTStorage(name string) *DOTStorage {
+	return &DOTStorage{name: name}
+}
+
 // Store ingress descriptor in the DOT file
 func (s *DOTStorage) Store(idef id.IngressDescriptor) *id.Error {
 	graphName := strconv.Quote(idef.StartTable().Name())
@@ -69,7 +77,19 @@ func (s *DOTStorage) Store(idef id.IngressDescriptor) *id.Error {
 		}
 	}
 
-	err = ioutil.WriteFile("ingress-descriptor.dot", []byte(graph.String()), 0600)
+	file, e := filename(s.name, ".dot")
+	if e != nil {
+		return e
+	}
+
+	if s.name != "" {
+		err = os.MkdirAll(IngressDirectory, 0700)
+		if err != nil {
+			return &id.Error{Description: err.Error()}
+		}
+	}
+
+	err = ioutil.WriteFile(file, []byte(graph.String()), 0600)
 	if err != nil {
 		return &id.Error{Description: err.Error()}
 	}
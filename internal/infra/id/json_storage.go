@@ -32,15 +32,27 @@ type JSONStructure struct {
 
 // JSONIngressDescriptor defines how to store an ingress descriptor in JSON format.
 type JSONIngressDescriptor struct {
-	StartTable string         `json:"startTable"`
-	Relations  []JSONRelation `json:"relations"`
+	StartTable      string         `json:"startTable"`
+	Roots           []JSONRoot     `json:"roots,omitempty"`
+	ReferenceTables []string       `json:"referenceTables,omitempty"`
+	Relations       []JSONRelation `json:"relations"`
+}
+
+// JSONRoot defines how to store a start table in JSON format.
+type JSONRoot struct {
+	Name   string            `json:"name,omitempty"`
+	Table  string            `json:"table"`
+	Filter map[string]string `json:"filter,omitempty"`
+	Limit  uint              `json:"limit,omitempty"`
+	Where  string            `json:"where,omitempty"`
 }
 
 // JSONRelation defines how to store a relation in JSON format.
 type JSONRelation struct {
-	Name   string    `json:"name"`
-	Parent JSONTable `json:"parent"`
-	Child  JSONTable `json:"child"`
+	Name     string    `json:"name"`
+	Parent   JSONTable `json:"parent"`
+	Child    JSONTable `json:"child"`
+	MaxDepth uint      `json:"maxDepth,omitempty"`
 }
 
 // JSONTable defines how to store a table in JSON format.
@@ -70,15 +82,30 @@ func (s *JSONStorage) Store(id id.IngressDescriptor) *id.Error {
 	for i := uint(0); i < list.Len(); i++ {
 		relation := list.Relation(i)
 		relations = append(relations, JSONRelation{
-			Name:   relation.Name(),
-			Parent: JSONTable{Name: relation.Parent().Name(), Lookup: relation.LookUpParent()},
-			Child:  JSONTable{Name: relation.Child().Name(), Lookup: relation.LookUpChild()},
+			Name:     relation.Name(),
+			Parent:   JSONTable{Name: relation.Parent().Name(), Lookup: relation.LookUpParent()},
+			Child:    JSONTable{Name: relation.Child().Name(), Lookup: relation.LookUpChild()},
+			MaxDepth: relation.MaxDepth(),
 		})
 	}
 
+	roots := []JSONRoot{}
+	if !isSingleStartTable(id.Roots()) {
+		for _, root := range id.Roots() {
+			roots = append(roots, JSONRoot{Name: root.Name, Table: root.Table, Filter: root.Filter, Limit: root.Limit, Where: root.Where})
+		}
+	}
+
+	references := []string{}
+	for i := uint(0); i < id.ReferenceTables().Len(); i++ {
+		references = append(references, id.ReferenceTables().Table(i).Name())
+	}
+
 	structure.IngressDescriptor = JSONIngressDescriptor{
-		StartTable: id.StartTable().Name(),
-		Relations:  relations,
+		StartTable:      id.StartTable().Name(),
+		Roots:           roots,
+		ReferenceTables: references,
+		Relations:       relations,
 	}
 
 	err := writeJSONFile(&structure, s.file)
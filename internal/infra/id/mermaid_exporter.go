@@ -0,0 +1,76 @@
+// Copyright (C) 2021 CGI France
+//
+// This file is part of LINO.
+//
+// LINO is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// LINO is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with LINO.  If not, see <http://www.gnu.org/licenses/>.
+
+package id
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/cgi-fr/lino/pkg/id"
+)
+
+// MermaidExporter export to a mermaid flowchart.
+type MermaidExporter struct {
+	out io.Writer
+}
+
+// NewMermaidExporter create a new MermaidExporter
+func NewMermaidExporter(out io.Writer) id.Exporter {
+	return &MermaidExporter{out: out}
+}
+
+// Export the puller plan as a mermaid flowchart.
+func (e *MermaidExporter) Export(ep id.PullerPlan) *id.Error {
+	model := newGraphModel(ep)
+	nodes := model.nodeIDs()
+
+	sb := &strings.Builder{}
+	fmt.Fprintln(sb, "flowchart LR")
+
+	clustered := map[string]bool{}
+	for i, cluster := range model.clusters {
+		fmt.Fprintf(sb, "  subgraph cluster%d [\"step %d (cycles)\"]\n", i, cluster.step)
+		for _, table := range cluster.tables {
+			fmt.Fprintf(sb, "    %s[\"%s<br/>step %d\"]\n", nodes[table], mermaidEscape(table), model.steps[table])
+			clustered[table] = true
+		}
+		fmt.Fprintln(sb, "  end")
+	}
+
+	for _, table := range model.tables {
+		if !clustered[table] {
+			fmt.Fprintf(sb, "  %s[\"%s<br/>step %d\"]\n", nodes[table], mermaidEscape(table), model.steps[table])
+		}
+	}
+
+	for _, edge := range model.edges {
+		fmt.Fprintf(sb, "  %s -->|\"%s\"| %s\n", nodes[edge.parent], mermaidEscape(edge.label()), nodes[edge.child])
+	}
+
+	_, err := fmt.Fprint(e.out, sb.String())
+	if err != nil {
+		return &id.Error{Description: err.Error()}
+	}
+
+	return nil
+}
+
+func mermaidEscape(s string) string {
+	return strings.ReplaceAll(s, `"`, "#quot;")
+}
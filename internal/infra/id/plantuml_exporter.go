@@ -0,0 +1,79 @@
+// Copyright (C) 2021 CGI France
+//
+// This file is part of LINO.
+//
+// LINO is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// LINO is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with LINO.  If not, see <http://www.gnu.org/licenses/>.
+
+package id
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/cgi-fr/lino/pkg/id"
+)
+
+// PlantUMLExporter export to a PlantUML diagram.
+type PlantUMLExporter struct {
+	out io.Writer
+}
+
+// NewPlantUMLExporter create a new PlantUMLExporter
+func NewPlantUMLExporter(out io.Writer) id.Exporter {
+	return &PlantUMLExporter{out: out}
+}
+
+// Export the puller plan as a PlantUML diagram.
+func (e *PlantUMLExporter) Export(ep id.PullerPlan) *id.Error {
+	model := newGraphModel(ep)
+	nodes := model.nodeIDs()
+
+	sb := &strings.Builder{}
+	fmt.Fprintln(sb, "@startuml")
+	fmt.Fprintln(sb, "left to right direction")
+
+	clustered := map[string]bool{}
+	for _, cluster := range model.clusters {
+		fmt.Fprintf(sb, "rectangle \"step %d (cycles)\" {\n", cluster.step)
+		for _, table := range cluster.tables {
+			fmt.Fprintf(sb, "  rectangle \"%s\\nstep %d\" as %s\n", plantUMLEscape(table), model.steps[table], nodes[table])
+			clustered[table] = true
+		}
+		fmt.Fprintln(sb, "}")
+	}
+
+	for _, table := range model.tables {
+		if !clustered[table] {
+			fmt.Fprintf(sb, "rectangle \"%s\\nstep %d\" as %s\n", plantUMLEscape(table), model.steps[table], nodes[table])
+		}
+	}
+
+	for _, edge := range model.edges {
+		fmt.Fprintf(sb, "%s --> %s : %s\n", nodes[edge.parent], nodes[edge.child], edge.label())
+	}
+
+	fmt.Fprintln(sb, "@enduml")
+
+	_, err := fmt.Fprint(e.out, sb.String())
+	if err != nil {
+		return &id.Error{Description: err.Error()}
+	}
+
+	return nil
+}
+
+func plantUMLEscape(s string) string {
+	return strings.ReplaceAll(s, `"`, `'`)
+}
@@ -0,0 +1,55 @@
+// Copyright (C) 2021 CGI France
+//
+// This file is part of LINO.
+//
+// LINO is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// LINO is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with LINO.  If not, see <http://www.gnu.org/licenses/>.
+
+package id
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/cgi-fr/lino/pkg/id"
+)
+
+func TestPlantUMLExporter_Export(t *testing.T) {
+	a := id.NewTable("A")
+	b := id.NewTable("B")
+	c := id.NewTable("C")
+	storage := memoryStorage{id.NewIngressDescriptor(a, id.NewIngressRelationList([]id.IngressRelation{
+		id.NewIngressRelation(id.NewRelation("a_b", a, b), true, true),
+		id.NewIngressRelation(id.NewRelation("c_a", c, a), true, false),
+	}))}
+
+	want := `@startuml
+left to right direction
+rectangle "step 1 (cycles)" {
+  rectangle "A\nstep 1" as t0
+  rectangle "B\nstep 1" as t1
+}
+rectangle "C\nstep 2" as t2
+t0 --> t1 : ↔ a_b
+t2 --> t0 : ← c_a
+@enduml
+`
+
+	out := &bytes.Buffer{}
+	if err := id.Export(storage, NewPlantUMLExporter(out)); err != nil {
+		t.Fatalf("PlantUMLExporter.Export() error = %v", err.Description)
+	}
+	if got := out.String(); got != want {
+		t.Errorf("PlantUMLExporter.Export() = %v, want %v", got, want)
+	}
+}
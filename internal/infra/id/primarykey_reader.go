@@ -0,0 +1,41 @@
+// Copyright (C) 2021 CGI France
+//
+// This file is part of LINO.
+//
+// LINO is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// LINO is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with LINO.  If not, see <http://www.gnu.org/licenses/>.
+
+package id
+
+import (
+	"github.com/cgi-fr/lino/pkg/id"
+	"github.com/cgi-fr/lino/pkg/table"
+)
+
+// PrimaryKeyReader is an adapter to read primary keys from the table domain.
+type PrimaryKeyReader struct {
+	tables []table.Table
+}
+
+// NewPrimaryKeyReader create a new primary keys reader
+func NewPrimaryKeyReader(tables []table.Table) *PrimaryKeyReader {
+	return &PrimaryKeyReader{tables: tables}
+}
+
+func (r *PrimaryKeyReader) Read() (map[string][]string, *id.Error) {
+	result := map[string][]string{}
+	for _, table := range r.tables {
+		result[table.Name] = table.Keys
+	}
+	return result, nil
+}
@@ -0,0 +1,41 @@
+// Copyright (C) 2021 CGI France
+//
+// This file is part of LINO.
+//
+// LINO is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// LINO is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with LINO.  If not, see <http://www.gnu.org/licenses/>.
+
+package id
+
+import (
+	"github.com/cgi-fr/lino/pkg/id"
+	"github.com/cgi-fr/lino/pkg/relation"
+)
+
+// RelationKeysReader is an adapter to read relation keys from the relation domain.
+type RelationKeysReader struct {
+	relations []relation.Relation
+}
+
+// NewRelationKeysReader create a new relation keys reader
+func NewRelationKeysReader(relations []relation.Relation) *RelationKeysReader {
+	return &RelationKeysReader{relations: relations}
+}
+
+func (r *RelationKeysReader) Read() (map[string]id.RelationKeys, *id.Error) {
+	result := map[string]id.RelationKeys{}
+	for _, relation := range r.relations {
+		result[relation.Name] = id.RelationKeys{Parent: relation.Parent.Keys, Child: relation.Child.Keys}
+	}
+	return result, nil
+}
@@ -0,0 +1,67 @@
+// Copyright (C) 2021 CGI France
+//
+// This file is part of LINO.
+//
+// LINO is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// LINO is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with LINO.  If not, see <http://www.gnu.org/licenses/>.
+
+package id
+
+import (
+	// import Oracle connector
+	_ "github.com/godror/godror"
+
+	"github.com/cgi-fr/lino/pkg/dataconnector"
+	"github.com/cgi-fr/lino/pkg/id"
+)
+
+// NewOracleStatisticsReaderFactory creates a new oracle statistics reader factory.
+func NewOracleStatisticsReaderFactory() *OracleStatisticsReaderFactory {
+	return &OracleStatisticsReaderFactory{}
+}
+
+// OracleStatisticsReaderFactory exposes methods to create new Oracle statistics readers.
+type OracleStatisticsReaderFactory struct{}
+
+// New return a Oracle statistics reader
+func (e *OracleStatisticsReaderFactory) New(url string, schema string, options dataconnector.Options) id.StatisticsReader {
+	return NewSQLStatisticsReader(url, schema, options, OracleStatisticsDialect{})
+}
+
+// OracleStatisticsDialect read statistics from ALL_TAB_STATISTICS and ALL_TAB_COL_STATISTICS.
+// An empty schema is NULL in Oracle, the owner is then the current user.
+type OracleStatisticsDialect struct{}
+
+func (d OracleStatisticsDialect) RowsSQL() string {
+	return `SELECT num_rows
+ FROM all_tab_statistics
+ WHERE owner = COALESCE(:1, user)
+ AND table_name = :2
+ AND object_type = 'TABLE'`
+}
+
+func (d OracleStatisticsDialect) RowSizeSQL() string {
+	return `SELECT avg_row_len
+ FROM all_tab_statistics
+ WHERE owner = COALESCE(:1, user)
+ AND table_name = :2
+ AND object_type = 'TABLE'`
+}
+
+func (d OracleStatisticsDialect) DistinctSQL() string {
+	return `SELECT num_distinct
+ FROM all_tab_col_statistics
+ WHERE owner = COALESCE(:1, user)
+ AND table_name = :2
+ AND column_name = :3`
+}
@@ -0,0 +1,65 @@
+// Copyright (C) 2021 CGI France
+//
+// This file is part of LINO.
+//
+// LINO is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// LINO is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with LINO.  If not, see <http://www.gnu.org/licenses/>.
+
+package id
+
+import (
+	// import postgresql connector
+	_ "github.com/lib/pq"
+
+	"github.com/cgi-fr/lino/pkg/dataconnector"
+	"github.com/cgi-fr/lino/pkg/id"
+)
+
+// NewPostgresStatisticsReaderFactory creates a new postgres statistics reader factory.
+func NewPostgresStatisticsReaderFactory() *PostgresStatisticsReaderFactory {
+	return &PostgresStatisticsReaderFactory{}
+}
+
+// PostgresStatisticsReaderFactory exposes methods to create new Postgres statistics readers.
+type PostgresStatisticsReaderFactory struct{}
+
+// New return a Postgres statistics reader
+func (e *PostgresStatisticsReaderFactory) New(url string, schema string, options dataconnector.Options) id.StatisticsReader {
+	return NewSQLStatisticsReader(url, schema, options, PostgresStatisticsDialect{})
+}
+
+// PostgresStatisticsDialect read statistics from pg_class and pg_stats.
+type PostgresStatisticsDialect struct{}
+
+func (d PostgresStatisticsDialect) RowsSQL() string {
+	return `SELECT GREATEST(c.reltuples, 0)
+FROM pg_class c
+JOIN pg_namespace n ON n.oid = c.relnamespace
+WHERE n.nspname = COALESCE(NULLIF($1, ''), current_schema())
+AND c.relname = $2`
+}
+
+func (d PostgresStatisticsDialect) RowSizeSQL() string {
+	return `SELECT SUM(avg_width)
+FROM pg_stats
+WHERE schemaname = COALESCE(NULLIF($1, ''), current_schema())
+AND tablename = $2`
+}
+
+func (d PostgresStatisticsDialect) DistinctSQL() string {
+	return `SELECT n_distinct
+FROM pg_stats
+WHERE schemaname = COALESCE(NULLIF($1, ''), current_schema())
+AND tablename = $2
+AND attname = $3`
+}
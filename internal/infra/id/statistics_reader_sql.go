@@ -0,0 +1,162 @@
+// Copyright (C) 2021 CGI France
+//
+// This file is part of LINO.
+//
+// LINO is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// LINO is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with LINO.  If not, see <http://www.gnu.org/licenses/>.
+
+package id
+
+import (
+	"database/sql"
+	"math"
+	"strings"
+
+	"github.com/cgi-fr/lino/internal/infra/connection"
+	"github.com/cgi-fr/lino/pkg/dataconnector"
+	"github.com/cgi-fr/lino/pkg/id"
+)
+
+// StatisticsDialect provides the queries reading the optimizer statistics of a database.
+// The schema parameter is empty for the current schema of the connection.
+type StatisticsDialect interface {
+	// RowsSQL returns the number of rows of a table (2 parameters: schema, table name)
+	RowsSQL() string
+	// RowSizeSQL returns the average size in bytes of a row (2 parameters: schema, table name)
+	RowSizeSQL() string
+	// DistinctSQL returns the number of distinct values of a column, negative values are a fraction of the rows (3 parameters: schema, table name, column name)
+	DistinctSQL() string
+}
+
+// SQLStatisticsReader read statistics from SQL database.
+type SQLStatisticsReader struct {
+	url     string
+	options dataconnector.Options
+	schema  string
+	dialect StatisticsDialect
+	db      *sql.DB
+}
+
+// NewSQLStatisticsReader creates a new SQL statistics reader.
+func NewSQLStatisticsReader(url string, schema string, options dataconnector.Options, dialect StatisticsDialect) *SQLStatisticsReader {
+	return &SQLStatisticsReader{
+		url:     url,
+		options: options,
+		schema:  schema,
+		dialect: dialect,
+	}
+}
+
+// Rows returns the estimated number of rows of the table.
+func (r *SQLStatisticsReader) Rows(table string) (float64, *id.Error) {
+	schema, name := r.split(table)
+	return r.query(r.dialect.RowsSQL(), schema, name)
+}
+
+// RowSize returns the estimated average size in bytes of a row of the table.
+func (r *SQLStatisticsReader) RowSize(table string) (float64, *id.Error) {
+	schema, name := r.split(table)
+	return r.query(r.dialect.RowSizeSQL(), schema, name)
+}
+
+// Distinct returns the estimated number of distinct values of the columns, assuming columns are independent.
+func (r *SQLStatisticsReader) Distinct(table string, columns []string) (float64, *id.Error) {
+	rows, err := r.Rows(table)
+	if err != nil {
+		return 0, err
+	}
+
+	schema, name := r.split(table)
+	result := float64(1)
+	for _, column := range columns {
+		distinct, err := r.query(r.dialect.DistinctSQL(), schema, name, column)
+		if err != nil {
+			return 0, err
+		}
+		if distinct < 0 {
+			distinct = -distinct * rows
+		}
+		if distinct == 0 {
+			return 0, nil
+		}
+		result *= distinct
+	}
+
+	if rows > 0 {
+		result = math.Min(result, rows)
+	}
+
+	return result, nil
+}
+
+// split the table name in schema and name, the schema of the dataconnector is used if the name is not qualified.
+func (r *SQLStatisticsReader) split(table string) (string, string) {
+	if i := strings.LastIndex(table, "."); i >= 0 {
+		return table[:i], table[i+1:]
+	}
+	return r.schema, table
+}
+
+// Close the database connection.
+func (r *SQLStatisticsReader) Close() *id.Error {
+	if r.db == nil {
+		return nil
+	}
+	err := r.db.Close()
+	r.db = nil
+	if err != nil {
+		return &id.Error{Description: err.Error()}
+	}
+	return nil
+}
+
+func (r *SQLStatisticsReader) open() *id.Error {
+	if r.db != nil {
+		return nil
+	}
+
+	db, err := connection.Open(r.url, r.options)
+	if err != nil {
+		return &id.Error{Description: err.Error()}
+	}
+
+	err = db.Ping()
+	if err != nil {
+		db.Close()
+		return &id.Error{Description: err.Error()}
+	}
+
+	r.db = db
+	return nil
+}
+
+// query returns the single value of the query, 0 if there is no statistics.
+func (r *SQLStatisticsReader) query(query string, args ...interface{}) (float64, *id.Error) {
+	if err := r.open(); err != nil {
+		return 0, err
+	}
+
+	var value sql.NullFloat64
+	err := r.db.QueryRow(query, args...).Scan(&value)
+	if err == sql.ErrNoRows {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, &id.Error{Description: err.Error()}
+	}
+
+	if !value.Valid {
+		return 0, nil
+	}
+	return value.Float64, nil
+}
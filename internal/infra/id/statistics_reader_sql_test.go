@@ -0,0 +1,100 @@
+// Copyright (C) 2021 CGI France
+//
+// This file is part of LINO.
+//
+// LINO is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// LINO is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with LINO.  If not, see <http://www.gnu.org/licenses/>.
+
+package id
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/cgi-fr/lino/pkg/dataconnector"
+	"github.com/stretchr/testify/assert"
+)
+
+// statsDriver records the arguments of the queries and returns 100 for each of them
+type statsDriver struct {
+	args [][]driver.Value
+}
+
+func (d *statsDriver) Open(name string) (driver.Conn, error) { return &statsConn{d}, nil }
+
+type statsConn struct{ d *statsDriver }
+
+func (c *statsConn) Prepare(query string) (driver.Stmt, error) { return &statsStmt{c.d}, nil }
+func (c *statsConn) Close() error                              { return nil }
+func (c *statsConn) Begin() (driver.Tx, error)                 { return nil, errors.New("not implemented") }
+
+type statsStmt struct{ d *statsDriver }
+
+func (s *statsStmt) Close() error  { return nil }
+func (s *statsStmt) NumInput() int { return -1 }
+func (s *statsStmt) Exec(args []driver.Value) (driver.Result, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (s *statsStmt) Query(args []driver.Value) (driver.Rows, error) {
+	s.d.args = append(s.d.args, args)
+	return &statsRows{}, nil
+}
+
+type statsRows struct{ done bool }
+
+func (r *statsRows) Columns() []string { return []string{"value"} }
+func (r *statsRows) Close() error      { return nil }
+func (r *statsRows) Next(dest []driver.Value) error {
+	if r.done {
+		return io.EOF
+	}
+	r.done = true
+	dest[0] = float64(100)
+	return nil
+}
+
+var stats = &statsDriver{}
+
+func init() {
+	sql.Register("lino-stats", stats)
+}
+
+func TestStatisticsOfQualifiedTable(t *testing.T) {
+	db, err := sql.Open("lino-stats", "")
+	assert.Nil(t, err)
+
+	reader := NewSQLStatisticsReader("", "public", dataconnector.Options{}, PostgresStatisticsDialect{})
+	reader.db = db
+	defer reader.Close()
+
+	rows, e := reader.Rows("sales.customer")
+	assert.Nil(t, e)
+	assert.Equal(t, float64(100), rows)
+
+	_, e = reader.Distinct("sales.customer", []string{"store_id"})
+	assert.Nil(t, e)
+
+	_, e = reader.RowSize("customer")
+	assert.Nil(t, e)
+
+	assert.Equal(t, [][]driver.Value{
+		{"sales", "customer"},
+		{"sales", "customer"},
+		{"sales", "customer", "store_id"},
+		{"public", "customer"},
+	}, stats.args)
+}
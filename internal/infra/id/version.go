@@ -19,3 +19,6 @@ package id
 
 // Version of the structure.
 const Version string = "v1"
+
+// IngressDirectory contains the named ingress descriptors.
+const IngressDirectory string = "ingress"
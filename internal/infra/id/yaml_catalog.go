@@ -0,0 +1,56 @@
+// Copyright (C) 2021 CGI France
+//
+// This file is part of LINO.
+//
+// LINO is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// LINO is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with LINO.  If not, see <http://www.gnu.org/licenses/>.
+
+package id
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/cgi-fr/lino/pkg/id"
+)
+
+// YAMLCatalog lists the named ingress descriptors stored in the ingress directory
+type YAMLCatalog struct{}
+
+// NewYAMLCatalog create a new YAML catalog
+func NewYAMLCatalog() *YAMLCatalog {
+	return &YAMLCatalog{}
+}
+
+// List names of the ingress descriptors
+func (c *YAMLCatalog) List() ([]string, *id.Error) {
+	result := []string{}
+
+	files, err := ioutil.ReadDir(IngressDirectory)
+	if os.IsNotExist(err) {
+		return result, nil
+	}
+	if err != nil {
+		return nil, &id.Error{Description: err.Error()}
+	}
+
+	for _, file := range files {
+		if !file.IsDir() && filepath.Ext(file.Name()) == ".yaml" {
+			result = append(result, strings.TrimSuffix(file.Name(), ".yaml"))
+		}
+	}
+
+	return result, nil
+}
@@ -19,6 +19,8 @@ package id
 
 import (
 	"io/ioutil"
+	"os"
+	"path/filepath"
 
 	"github.com/cgi-fr/lino/pkg/id"
 	"gopkg.in/yaml.v3"
@@ -32,15 +34,27 @@ type YAMLStructure struct {
 
 // YAMLIngressDescriptor defines how to store an ingress descriptor in YAML format.
 type YAMLIngressDescriptor struct {
-	StartTable string         `yaml:"startTable"`
-	Relations  []YAMLRelation `yaml:"relations"`
+	StartTable      string         `yaml:"startTable"`
+	Roots           []YAMLRoot     `yaml:"roots,omitempty"`
+	ReferenceTables []string       `yaml:"referenceTables,omitempty"`
+	Relations       []YAMLRelation `yaml:"relations"`
+}
+
+// YAMLRoot defines how to store a start table in YAML format.
+type YAMLRoot struct {
+	Name   string            `yaml:"name,omitempty"`
+	Table  string            `yaml:"table"`
+	Filter map[string]string `yaml:"filter,omitempty"`
+	Limit  uint              `yaml:"limit,omitempty"`
+	Where  string            `yaml:"where,omitempty"`
 }
 
 // YAMLRelation defines how to store a relation in YAML format.
 type YAMLRelation struct {
-	Name   string    `yaml:"name"`
-	Parent YAMLTable `yaml:"parent"`
-	Child  YAMLTable `yaml:"child"`
+	Name     string    `yaml:"name"`
+	Parent   YAMLTable `yaml:"parent"`
+	Child    YAMLTable `yaml:"child"`
+	MaxDepth uint      `yaml:"maxDepth,omitempty"`
 }
 
 // YAMLTable defines how to store a table in YAML format.
@@ -51,6 +65,7 @@ type YAMLTable struct {
 
 // YAMLStorage provides storage in a local YAML file
 type YAMLStorage struct {
+	name string
 }
 
 // NewYAMLStorage create a new YAML storage
@@ -58,6 +73,11 @@ func NewYAMLStorage() *YAMLStorage {
 	return &YAMLStorage{}
 }
 
+// NewNamedYAMLStorage create a new YAML storage for the named ingress descriptor in the ingress directory
+func NewNamedYAMLStorage(name string) *YAMLStorage {
+	return &YAMLStorage{name: name}
+}
+
 // Store ingress descriptor in the YAML file
 func (s *YAMLStorage) Store(id id.IngressDescriptor) *id.Error {
 	structure := YAMLStructure{
@@ -69,18 +89,33 @@ func (s *YAMLStorage) Store(id id.IngressDescriptor) *id.Error {
 	for i := uint(0); i < list.Len(); i++ {
 		relation := list.Relation(i)
 		relations = append(relations, YAMLRelation{
-			Name:   relation.Name(),
-			Parent: YAMLTable{Name: relation.Parent().Name(), Lookup: relation.LookUpParent()},
-			Child:  YAMLTable{Name: relation.Child().Name(), Lookup: relation.LookUpChild()},
+			Name:     relation.Name(),
+			Parent:   YAMLTable{Name: relation.Parent().Name(), Lookup: relation.LookUpParent()},
+			Child:    YAMLTable{Name: relation.Child().Name(), Lookup: relation.LookUpChild()},
+			MaxDepth: relation.MaxDepth(),
 		})
 	}
 
+	roots := []YAMLRoot{}
+	if !isSingleStartTable(id.Roots()) {
+		for _, root := range id.Roots() {
+			roots = append(roots, YAMLRoot{Name: root.Name, Table: root.Table, Filter: root.Filter, Limit: root.Limit, Where: root.Where})
+		}
+	}
+
+	references := []string{}
+	for i := uint(0); i < id.ReferenceTables().Len(); i++ {
+		references = append(references, id.ReferenceTables().Table(i).Name())
+	}
+
 	structure.IngressDescriptor = YAMLIngressDescriptor{
-		StartTable: id.StartTable().Name(),
-		Relations:  relations,
+		StartTable:      id.StartTable().Name(),
+		Roots:           roots,
+		ReferenceTables: references,
+		Relations:       relations,
 	}
 
-	err := writeFile(&structure)
+	err := writeFile(&structure, s.name)
 	if err != nil {
 		return err
 	}
@@ -89,7 +124,7 @@ func (s *YAMLStorage) Store(id id.IngressDescriptor) *id.Error {
 }
 
 func (s *YAMLStorage) Read() (id.IngressDescriptor, *id.Error) {
-	structure, err := readFile()
+	structure, err := readFile(s.name)
 	if err != nil {
 		return nil, err
 	}
@@ -97,26 +132,71 @@ func (s *YAMLStorage) Read() (id.IngressDescriptor, *id.Error) {
 	relations := []id.IngressRelation{}
 	for _, relation := range structure.IngressDescriptor.Relations {
 		relations = append(relations,
-			id.NewIngressRelation(
+			id.NewBoundedIngressRelation(
 				id.NewRelation(
 					relation.Name,
 					id.NewTable(relation.Parent.Name),
 					id.NewTable(relation.Child.Name),
 				),
-				relation.Parent.Lookup, relation.Child.Lookup),
+				relation.Parent.Lookup, relation.Child.Lookup, relation.MaxDepth),
 		)
 	}
 
-	return id.NewIngressDescriptor(id.NewTable(structure.IngressDescriptor.StartTable), id.NewIngressRelationList(relations)), nil
+	roots := []id.Root{}
+	for _, root := range structure.IngressDescriptor.Roots {
+		roots = append(roots, id.Root{Name: root.Name, Table: root.Table, Filter: root.Filter, Limit: root.Limit, Where: root.Where})
+	}
+	if len(roots) == 0 {
+		roots = append(roots, id.Root{Table: structure.IngressDescriptor.StartTable})
+	}
+
+	references := []id.Table{}
+	for _, reference := range structure.IngressDescriptor.ReferenceTables {
+		references = append(references, id.NewTable(reference))
+	}
+
+	return id.NewMultiRootIngressDescriptor(roots, id.NewTableList(references), id.NewIngressRelationList(relations)), nil
 }
 
-func writeFile(structure *YAMLStructure) *id.Error {
+// isSingleStartTable returns true if the roots can be stored as a start table only
+func isSingleStartTable(roots []id.Root) bool {
+	if len(roots) != 1 {
+		return false
+	}
+	root := roots[0]
+	return root.Name == "" && len(root.Filter) == 0 && root.Limit == 0 && root.Where == ""
+}
+
+// filename of the ingress descriptor, default ingress descriptor if name is empty
+func filename(name string, ext string) (string, *id.Error) {
+	if name == "" {
+		return "ingress-descriptor" + ext, nil
+	}
+	if name != filepath.Base(name) || name == "." || name == ".." {
+		return "", &id.Error{Description: "invalid ingress descriptor name " + name}
+	}
+	return filepath.Join(IngressDirectory, name+ext), nil
+}
+
+func writeFile(structure *YAMLStructure, name string) *id.Error {
 	out, err := yaml.Marshal(structure)
 	if err != nil {
 		return &id.Error{Description: err.Error()}
 	}
 
-	err = ioutil.WriteFile("ingress-descriptor.yaml", out, 0600)
+	file, e := filename(name, ".yaml")
+	if e != nil {
+		return e
+	}
+
+	if name != "" {
+		err = os.MkdirAll(IngressDirectory, 0700)
+		if err != nil {
+			return &id.Error{Description: err.Error()}
+		}
+	}
+
+	err = ioutil.WriteFile(file, out, 0600)
 	if err != nil {
 		return &id.Error{Description: err.Error()}
 	}
@@ -124,12 +204,17 @@ func writeFile(structure *YAMLStructure) *id.Error {
 	return nil
 }
 
-func readFile() (*YAMLStructure, *id.Error) {
+func readFile(name string) (*YAMLStructure, *id.Error) {
 	structure := &YAMLStructure{
 		Version: Version,
 	}
 
-	dat, err := ioutil.ReadFile("ingress-descriptor.yaml")
+	file, e := filename(name, ".yaml")
+	if e != nil {
+		return nil, e
+	}
+
+	dat, err := ioutil.ReadFile(file)
 	if err != nil {
 		return nil, &id.Error{Description: err.Error()}
 	}
@@ -140,7 +225,7 @@ func readFile() (*YAMLStructure, *id.Error) {
 	}
 
 	if structure.Version != Version {
-		return nil, &id.Error{Description: "invalid version in ./ingress-descriptor.yaml (" + structure.Version + ")"}
+		return nil, &id.Error{Description: "invalid version in ./" + file + " (" + structure.Version + ")"}
 	}
 
 	return structure, nil
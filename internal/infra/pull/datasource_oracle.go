@@ -2,7 +2,9 @@ package pull
 
 import (
 	"fmt"
+	"regexp"
 
+	"github.com/cgi-fr/lino/pkg/dataconnector"
 	"github.com/cgi-fr/lino/pkg/pull"
 )
 
@@ -15,14 +17,17 @@ func NewOracleDataSourceFactory() *OracleDataSourceFactory {
 }
 
 // New return a Oracle puller
-func (e *OracleDataSourceFactory) New(url string, schema string) pull.DataSource {
+func (e *OracleDataSourceFactory) New(url string, schema string, options dataconnector.Options) pull.DataSource {
 	return &SQLDataSource{
 		url:     url,
+		options: options,
 		schema:  schema,
 		dialect: OracleDialect{},
 	}
 }
 
+var oracleErrorCode = regexp.MustCompile(`ORA-\d{5}`)
+
 // PostgresDialect implement postgres SQL variations
 type OracleDialect struct{}
 
@@ -33,3 +38,7 @@ func (od OracleDialect) Placeholder(position int) string {
 func (od OracleDialect) Limit(limit uint) string {
 	return fmt.Sprintf(" AND rownum <= %d", limit)
 }
+
+func (od OracleDialect) ErrorCode(err error) string {
+	return oracleErrorCode.FindString(err.Error())
+}
@@ -20,10 +20,10 @@ package pull
 import (
 	"fmt"
 
+	"github.com/cgi-fr/lino/pkg/dataconnector"
 	"github.com/cgi-fr/lino/pkg/pull"
 
-	// import postgresql connector
-	_ "github.com/lib/pq"
+	"github.com/lib/pq"
 )
 
 // PostgresDataSourceFactory exposes methods to create new Postgres pullers.
@@ -35,9 +35,10 @@ func NewPostgresDataSourceFactory() *PostgresDataSourceFactory {
 }
 
 // New return a Postgres puller
-func (e *PostgresDataSourceFactory) New(url string, schema string) pull.DataSource {
+func (e *PostgresDataSourceFactory) New(url string, schema string, options dataconnector.Options) pull.DataSource {
 	return &SQLDataSource{
 		url:     url,
+		options: options,
 		schema:  schema,
 		dialect: PostgresDialect{},
 	}
@@ -53,3 +54,10 @@ func (pd PostgresDialect) Placeholder(position int) string {
 func (pd PostgresDialect) Limit(limit uint) string {
 	return fmt.Sprintf(" LIMIT %d", limit)
 }
+
+func (pd PostgresDialect) ErrorCode(err error) string {
+	if pqErr, ok := err.(*pq.Error); ok {
+		return string(pqErr.Code)
+	}
+	return ""
+}
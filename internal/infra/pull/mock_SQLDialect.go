@@ -9,6 +9,20 @@ type MockSQLDialect struct {
 	mock.Mock
 }
 
+// ErrorCode provides a mock function with given fields: _a0
+func (_m *MockSQLDialect) ErrorCode(_a0 error) string {
+	ret := _m.Called(_a0)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(error) string); ok {
+		r0 = rf(_a0)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
+
 // Limit provides a mock function with given fields: _a0
 func (_m *MockSQLDialect) Limit(_a0 uint) string {
 	ret := _m.Called(_a0)
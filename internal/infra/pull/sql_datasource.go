@@ -22,6 +22,8 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/cgi-fr/lino/internal/infra/connection"
+	"github.com/cgi-fr/lino/pkg/dataconnector"
 	"github.com/cgi-fr/lino/pkg/pull"
 
 	"github.com/jmoiron/sqlx"
@@ -33,6 +35,7 @@ import (
 // SQLDataSource to read in the pull process.
 type SQLDataSource struct {
 	url     string
+	options dataconnector.Options
 	schema  string
 	dbx     *sqlx.DB
 	db      *sql.DB
@@ -41,7 +44,7 @@ type SQLDataSource struct {
 
 // Open a connection to the SQL DB
 func (ds *SQLDataSource) Open() *pull.Error {
-	db, err := dburl.Open(ds.url)
+	db, err := connection.Open(ds.url, ds.options)
 	if err != nil {
 		return &pull.Error{Description: err.Error()}
 	}
@@ -74,11 +77,44 @@ func (ds *SQLDataSource) tableName(source pull.Table) string {
 	return ds.schema + "." + source.Name()
 }
 
+// from returns the source of the rows, the view or the subselect of a virtual table is aliased by the table name (without schema)
+func (ds *SQLDataSource) from(source pull.Table) string {
+	alias := source.Name()[strings.LastIndex(source.Name(), ".")+1:]
+	switch {
+	case source.Query() != "":
+		return "(" + source.Query() + ") " + alias
+	case source.View() != "":
+		return ds.tableName(pull.NewTable(source.View(), source.PrimaryKey())) + " " + alias
+	default:
+		return ds.tableName(source)
+	}
+}
+
 // RowReader iterate over rows in table with filter
 func (ds *SQLDataSource) RowReader(source pull.Table, filter pull.Filter) (pull.RowReader, *pull.Error) {
+	query, values := ds.selectSQL(source, filter)
+
+	if log.Logger.GetLevel() <= zerolog.DebugLevel {
+		printSQL := query
+		for i, v := range values {
+			printSQL = strings.ReplaceAll(printSQL, ds.dialect.Placeholder(i+1), fmt.Sprintf("%v", v))
+		}
+		log.Debug().Msg(fmt.Sprint(printSQL))
+	}
+
+	rows, err := ds.dbx.Queryx(query, values...)
+	if err != nil {
+		return nil, &pull.Error{Description: err.Error(), Code: ds.dialect.ErrorCode(err)}
+	}
+
+	return &SQLDataIterator{rows, nil, nil}, nil
+}
+
+// selectSQL builds the query of the rows in table with filter and its values
+func (ds *SQLDataSource) selectSQL(source pull.Table, filter pull.Filter) (string, []interface{}) {
 	sql := &strings.Builder{}
 	sql.Write([]byte("SELECT * FROM "))
-	sql.Write([]byte(ds.tableName(source)))
+	sql.Write([]byte(ds.from(source)))
 	sql.Write([]byte(" WHERE "))
 
 	whereContentFlag := false
@@ -111,20 +147,7 @@ func (ds *SQLDataSource) RowReader(source pull.Table, filter pull.Filter) (pull.
 		fmt.Fprint(sql, ds.dialect.Limit(filter.Limit()))
 	}
 
-	if log.Logger.GetLevel() <= zerolog.DebugLevel {
-		printSQL := sql.String()
-		for i, v := range values {
-			printSQL = strings.ReplaceAll(printSQL, ds.dialect.Placeholder(i+1), fmt.Sprintf("%v", v))
-		}
-		log.Debug().Msg(fmt.Sprint(printSQL))
-	}
-
-	rows, err := ds.dbx.Queryx(sql.String(), values...)
-	if err != nil {
-		return nil, &pull.Error{Description: err.Error()}
-	}
-
-	return &SQLDataIterator{rows, nil, nil}, nil
+	return sql.String(), values
 }
 
 // Close a connection to the SQL DB
@@ -191,4 +214,6 @@ type SQLDialect interface {
 	Placeholder(int) string
 	// Limit format limitation clause
 	Limit(uint) string
+	// ErrorCode extract the vendor error code (SQLSTATE, ORA-nnnnn)
+	ErrorCode(error) string
 }
@@ -0,0 +1,52 @@
+// Copyright (C) 2021 CGI France
+//
+// This file is part of LINO.
+//
+// LINO is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// LINO is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with LINO.  If not, see <http://www.gnu.org/licenses/>.
+
+package pull
+
+import (
+	"testing"
+
+	"github.com/cgi-fr/lino/pkg/pull"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFromVirtualTable(t *testing.T) {
+	ds := &SQLDataSource{schema: "sales"}
+
+	assert.Equal(t, "sales.customer", ds.from(pull.NewTable("customer", []string{"id"})))
+	assert.Equal(t, "sales.v_active_customer active_customer", ds.from(pull.NewVirtualTable("active_customer", []string{"id"}, "v_active_customer", "")))
+	assert.Equal(t, "(SELECT * FROM customer WHERE claim = 'open') open_claim", ds.from(pull.NewVirtualTable("sales.open_claim", []string{"id"}, "", "SELECT * FROM customer WHERE claim = 'open'")))
+}
+
+func TestFilterVirtualTable(t *testing.T) {
+	source := pull.NewVirtualTable("sales.open_claim", []string{"id"}, "", "SELECT * FROM claim WHERE status = 'open'")
+	filter := pull.NewFilter(10, pull.Row{"customer_id": 42}, "amount > 100")
+
+	postgres := &SQLDataSource{schema: "sales", dialect: PostgresDialect{}}
+	query, values := postgres.selectSQL(source, filter)
+	assert.Equal(t, "SELECT * FROM (SELECT * FROM claim WHERE status = 'open') open_claim WHERE customer_id=$1 AND amount > 100 LIMIT 10", query)
+	assert.Equal(t, []interface{}{42}, values)
+
+	oracle := &SQLDataSource{schema: "sales", dialect: OracleDialect{}}
+	query, values = oracle.selectSQL(source, filter)
+	assert.Equal(t, "SELECT * FROM (SELECT * FROM claim WHERE status = 'open') open_claim WHERE customer_id=:v1 AND amount > 100 AND rownum <= 10", query)
+	assert.Equal(t, []interface{}{42}, values)
+
+	query, values = oracle.selectSQL(source, pull.NewFilter(5, pull.Row{}, ""))
+	assert.Equal(t, "SELECT * FROM (SELECT * FROM claim WHERE status = 'open') open_claim WHERE  1=1  AND rownum <= 5", query)
+	assert.Empty(t, values)
+}
@@ -0,0 +1,74 @@
+// Copyright (C) 2021 CGI France
+//
+// This file is part of LINO.
+//
+// LINO is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// LINO is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with LINO.  If not, see <http://www.gnu.org/licenses/>.
+
+package pull
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/cgi-fr/lino/pkg/pull"
+)
+
+// JSONStatsWriter write pull statistics as a JSON object
+type JSONStatsWriter struct {
+	file io.Writer
+}
+
+// NewJSONStatsWriter creates a new JSONStatsWriter.
+func NewJSONStatsWriter(file io.Writer) pull.StatsWriter {
+	return &JSONStatsWriter{file}
+}
+
+// JSONStats is the JSON representation of pull statistics, durations are in milliseconds
+type JSONStats struct {
+	Duration int64                     `json:"duration"`
+	Tables   map[string]JSONTableStats `json:"tables"`
+}
+
+// JSONTableStats is the JSON representation of the counters of a table
+type JSONTableStats struct {
+	Read     uint  `json:"read"`
+	Exported uint  `json:"exported"`
+	Duration int64 `json:"duration"`
+}
+
+// Write stats to the file
+func (w *JSONStatsWriter) Write(stats pull.Stats) *pull.Error {
+	jsonStats := JSONStats{
+		Duration: stats.Duration.Milliseconds(),
+		Tables:   map[string]JSONTableStats{},
+	}
+	for name, ts := range stats.Tables {
+		jsonStats.Tables[name] = JSONTableStats{
+			Read:     ts.Read,
+			Exported: ts.Exported,
+			Duration: ts.Duration.Milliseconds(),
+		}
+	}
+
+	jsonString, err := json.Marshal(jsonStats)
+	if err != nil {
+		return &pull.Error{Description: err.Error()}
+	}
+	_, err = fmt.Fprintln(w.file, string(jsonString))
+	if err != nil {
+		return &pull.Error{Description: err.Error()}
+	}
+	return nil
+}
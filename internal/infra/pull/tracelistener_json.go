@@ -39,11 +39,14 @@ func NewJSONTraceListener(file *os.File) JSONTraceListener {
 
 // Event is catch by json tracer
 type Event struct {
-	Duration int64  `json:"duration"`
-	Index    uint   `json:"index"`
-	Entry    string `json:"entry"`
-	Follow   string `json:"follow"`
-	Filter   string `json:"filter"`
+	Duration int64    `json:"duration"`
+	Index    uint     `json:"index"`
+	Entry    string   `json:"entry"`
+	Follow   string   `json:"follow"`
+	Filter   string   `json:"filter"`
+	Event    string   `json:"event,omitempty"`
+	Cycle    []string `json:"cycle,omitempty"`
+	MaxDepth uint     `json:"maxDepth,omitempty"`
 }
 
 // TraceStep catch Step event.
@@ -69,3 +72,26 @@ func (t JSONTraceListener) TraceStep(s pull.Step, filter pull.Filter) pull.Trace
 	fmt.Fprintln(t.file, string(jsonString))
 	return t
 }
+
+// TraceMaxDepth catch the end of a cycle traversal on maximum depth.
+func (t JSONTraceListener) TraceMaxDepth(s pull.Step, cycle pull.Cycle) pull.TraceListener {
+	now := time.Now()
+	event := Event{
+		Duration: now.Sub(t.last).Milliseconds(),
+		Index:    s.Index(),
+		Entry:    s.Entry().Name(),
+		Event:    "max-cycle-depth",
+		Cycle:    []string{},
+		MaxDepth: cycle.MaxDepth(),
+	}
+	for i := uint(0); i < cycle.Len(); i++ {
+		event.Cycle = append(event.Cycle, cycle.Relation(i).Name())
+	}
+	t.last = now
+	jsonString, err := json.Marshal(event)
+	if err != nil {
+		panic(err)
+	}
+	fmt.Fprintln(t.file, string(jsonString))
+	return t
+}
@@ -2,9 +2,11 @@ package push
 
 import (
 	"fmt"
+	"regexp"
 	"strings"
 	"time"
 
+	"github.com/cgi-fr/lino/pkg/dataconnector"
 	"github.com/cgi-fr/lino/pkg/push"
 
 	// import Oracle connector
@@ -20,10 +22,12 @@ func NewOracleDataDestinationFactory() *OracleDataDestinationFactory {
 }
 
 // New return a Oracle pusher
-func (e *OracleDataDestinationFactory) New(url string, schema string) push.DataDestination {
-	return NewSQLDataDestination(url, schema, OracleDialect{})
+func (e *OracleDataDestinationFactory) New(url string, schema string, options dataconnector.Options) push.DataDestination {
+	return NewSQLDataDestination(url, schema, options, OracleDialect{})
 }
 
+var oracleErrorCode = regexp.MustCompile(`ORA-\d{5}`)
+
 // OracleDialect inject oracle variations
 type OracleDialect struct{}
 
@@ -163,6 +167,11 @@ func (d OracleDialect) IsDuplicateError(err error) bool {
 	return strings.Contains(err.Error(), "ORA-00001")
 }
 
+// ErrorCode returns the ORA-nnnnn code of the error
+func (d OracleDialect) ErrorCode(err error) string {
+	return oracleErrorCode.FindString(err.Error())
+}
+
 // ConvertValue before load
 func (d OracleDialect) ConvertValue(from push.Value) push.Value {
 	// FIXME: Workaround to parse time from json
@@ -21,6 +21,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/cgi-fr/lino/pkg/dataconnector"
 	"github.com/cgi-fr/lino/pkg/push"
 	"github.com/lib/pq"
 )
@@ -34,8 +35,8 @@ func NewPostgresDataDestinationFactory() *PostgresDataDestinationFactory {
 }
 
 // New return a Postgres pusher
-func (e *PostgresDataDestinationFactory) New(url string, schema string) push.DataDestination {
-	return NewSQLDataDestination(url, schema, PostgresDialect{})
+func (e *PostgresDataDestinationFactory) New(url string, schema string, options dataconnector.Options) push.DataDestination {
+	return NewSQLDataDestination(url, schema, options, PostgresDialect{})
 }
 
 // PostgresDialect inject postgres variations
@@ -108,6 +109,14 @@ func (d PostgresDialect) IsDuplicateError(err error) bool {
 	return ok && pqErr.Code == "23505"
 }
 
+// ErrorCode returns the SQLSTATE code of the error
+func (d PostgresDialect) ErrorCode(err error) string {
+	if pqErr, ok := err.(*pq.Error); ok {
+		return string(pqErr.Code)
+	}
+	return ""
+}
+
 // ConvertValue before load
 func (d PostgresDialect) ConvertValue(from push.Value) push.Value {
 	return from
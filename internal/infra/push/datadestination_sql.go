@@ -22,6 +22,8 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/cgi-fr/lino/internal/infra/connection"
+	"github.com/cgi-fr/lino/pkg/dataconnector"
 	"github.com/cgi-fr/lino/pkg/push"
 	"github.com/jmoiron/sqlx"
 	"github.com/rs/zerolog/log"
@@ -31,6 +33,7 @@ import (
 // SQLDataDestination read data from a SQL database.
 type SQLDataDestination struct {
 	url                string
+	options            dataconnector.Options
 	schema             string
 	db                 *sqlx.DB
 	tx                 *sql.Tx
@@ -41,9 +44,10 @@ type SQLDataDestination struct {
 }
 
 // NewSQLDataDestination creates a new SQL datadestination.
-func NewSQLDataDestination(url string, schema string, dialect SQLDialect) *SQLDataDestination {
+func NewSQLDataDestination(url string, schema string, options dataconnector.Options, dialect SQLDialect) *SQLDataDestination {
 	return &SQLDataDestination{
 		url:       url,
+		options:   options,
 		schema:    schema,
 		rowWriter: map[string]*SQLRowWriter{},
 		dialect:   dialect,
@@ -91,7 +95,7 @@ func (dd *SQLDataDestination) Commit() *push.Error {
 
 	err := dd.tx.Commit()
 	if err != nil {
-		return &push.Error{Description: err.Error()}
+		return &push.Error{Description: err.Error(), Code: dd.dialect.ErrorCode(err)}
 	}
 	log.Debug().Msg("transaction committed")
 
@@ -112,12 +116,37 @@ func (dd *SQLDataDestination) Commit() *push.Error {
 	return nil
 }
 
+// Rollback SQL transaction and start a new one
+func (dd *SQLDataDestination) Rollback() *push.Error {
+	for _, rw := range dd.rowWriter {
+		err := rw.commit()
+		if err != nil {
+			return err
+		}
+	}
+
+	err := dd.tx.Rollback()
+	if err != nil {
+		return &push.Error{Description: err.Error()}
+	}
+	log.Debug().Msg("transaction rolled back")
+
+	tx, err := dd.db.Begin()
+	if err != nil {
+		return &push.Error{Description: err.Error()}
+	}
+
+	dd.tx = tx
+
+	return nil
+}
+
 // Open SQL Connection
 func (dd *SQLDataDestination) Open(plan push.Plan, mode push.Mode, disableConstraints bool) *push.Error {
 	dd.mode = mode
 	dd.disableConstraints = disableConstraints
 
-	db, err := dburl.Open(dd.url)
+	db, err := connection.Open(dd.url, dd.options)
 	if err != nil {
 		return &push.Error{Description: err.Error()}
 	}
@@ -323,8 +352,9 @@ func (rw *SQLRowWriter) Write(row push.Row) *push.Error {
 	if err2 != nil {
 		if rw.dd.dialect.IsDuplicateError(err2) {
 			log.Trace().Msg(fmt.Sprintf("duplicate key %v (%s) for %s", row, rw.table.PrimaryKey(), rw.table.Name()))
+			return push.ErrDuplicate
 		} else {
-			return &push.Error{Description: err2.Error()}
+			return &push.Error{Description: err2.Error(), Code: rw.dd.dialect.ErrorCode(err2)}
 		}
 	}
 
@@ -370,5 +400,6 @@ type SQLDialect interface {
 	InsertStatement(tableName string, columns []string, values []string, primaryKeys []string) string
 	UpdateStatement(tableName string, columns []string, uValues []string, primaryKeys []string, pValues []string) (string, *push.Error)
 	IsDuplicateError(error) bool
+	ErrorCode(error) string
 	ConvertValue(push.Value) push.Value
 }
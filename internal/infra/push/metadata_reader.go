@@ -0,0 +1,74 @@
+// Copyright (C) 2021 CGI France
+//
+// This file is part of LINO.
+//
+// LINO is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// LINO is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with LINO.  If not, see <http://www.gnu.org/licenses/>.
+
+package push
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cgi-fr/lino/pkg/push"
+	"github.com/cgi-fr/lino/pkg/table"
+)
+
+// TableMetadataReader is an adapter to read destination columns from the table domain.
+type TableMetadataReader struct {
+	tables map[string]table.Table
+	// unqualified lists the schema prefixed names of each table name
+	unqualified map[string][]string
+}
+
+// NewTableMetadataReader create a new metadata reader from tables whose names are prefixed by their schema
+func NewTableMetadataReader(tables []table.Table) *TableMetadataReader {
+	tmap := map[string]table.Table{}
+	unqualified := map[string][]string{}
+	for _, t := range tables {
+		tmap[t.Name] = t
+		if idx := strings.LastIndex(t.Name, "."); idx >= 0 {
+			unqualified[t.Name[idx+1:]] = append(unqualified[t.Name[idx+1:]], t.Name)
+		}
+	}
+	return &TableMetadataReader{tables: tmap, unqualified: unqualified}
+}
+
+// Columns of the table, a table name without schema must match a table of a single schema
+func (r *TableMetadataReader) Columns(t push.Table) ([]push.Column, *push.Error) {
+	tab, ok := r.tables[t.Name()]
+	if !ok && !strings.Contains(t.Name(), ".") {
+		names := r.unqualified[t.Name()]
+		if len(names) > 1 {
+			return nil, &push.Error{Description: fmt.Sprintf("table %s exists in several schemas (%s), prefix it by its schema", t.Name(), strings.Join(names, ", "))}
+		}
+		if len(names) == 1 {
+			tab, ok = r.tables[names[0]]
+		}
+	}
+	if !ok {
+		return []push.Column{}, nil
+	}
+
+	result := []push.Column{}
+	for _, c := range tab.Columns {
+		result = append(result, push.Column{
+			Name:       c.Name,
+			Type:       c.Type,
+			Nullable:   c.Nullable,
+			HasDefault: c.HasDefault,
+		})
+	}
+	return result, nil
+}
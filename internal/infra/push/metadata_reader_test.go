@@ -0,0 +1,54 @@
+// Copyright (C) 2021 CGI France
+//
+// This file is part of LINO.
+//
+// LINO is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// LINO is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with LINO.  If not, see <http://www.gnu.org/licenses/>.
+
+package push
+
+import (
+	"testing"
+
+	"github.com/cgi-fr/lino/pkg/push"
+	"github.com/cgi-fr/lino/pkg/table"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestColumnsOfSameTableInSeveralSchemas(t *testing.T) {
+	reader := NewTableMetadataReader([]table.Table{
+		{Name: "public.customer", Columns: []table.Column{{Name: "id", Type: "integer"}}},
+		{Name: "sales.customer", Columns: []table.Column{{Name: "id", Type: "integer"}, {Name: "store_id", Type: "integer", Nullable: true}}},
+		{Name: "sales.store", Columns: []table.Column{{Name: "id", Type: "integer", HasDefault: true}}},
+	})
+
+	columns, err := reader.Columns(push.NewTable("public.customer", []string{"id"}))
+	assert.Nil(t, err)
+	assert.Equal(t, []push.Column{{Name: "id", Type: "integer"}}, columns)
+
+	columns, err = reader.Columns(push.NewTable("sales.customer", []string{"id"}))
+	assert.Nil(t, err)
+	assert.Equal(t, []push.Column{{Name: "id", Type: "integer"}, {Name: "store_id", Type: "integer", Nullable: true}}, columns)
+
+	columns, err = reader.Columns(push.NewTable("store", []string{"id"}))
+	assert.Nil(t, err)
+	assert.Equal(t, []push.Column{{Name: "id", Type: "integer", HasDefault: true}}, columns)
+
+	_, err = reader.Columns(push.NewTable("customer", []string{"id"}))
+	assert.NotNil(t, err)
+	assert.Equal(t, "table customer exists in several schemas (public.customer, sales.customer), prefix it by its schema", err.Description)
+
+	columns, err = reader.Columns(push.NewTable("public.store", []string{"id"}))
+	assert.Nil(t, err)
+	assert.Empty(t, columns)
+}
@@ -56,6 +56,20 @@ func (_m *MockSQLDialect) EnableConstraintsStatement(tableName string) string {
 	return r0
 }
 
+// ErrorCode provides a mock function with given fields: _a0
+func (_m *MockSQLDialect) ErrorCode(_a0 error) string {
+	ret := _m.Called(_a0)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(error) string); ok {
+		r0 = rf(_a0)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
+
 // InsertStatement provides a mock function with given fields: tableName, columns, values, primaryKeys
 func (_m *MockSQLDialect) InsertStatement(tableName string, columns []string, values []string, primaryKeys []string) string {
 	ret := _m.Called(tableName, columns, values, primaryKeys)
@@ -1,8 +1,8 @@
 package relation
 
 import (
-	"fmt"
-
+	"github.com/cgi-fr/lino/internal/infra/sqlfilter"
+	"github.com/cgi-fr/lino/pkg/dataconnector"
 	"github.com/cgi-fr/lino/pkg/relation"
 
 	// import Oracle connector
@@ -18,18 +18,20 @@ func NewOracleExtractorFactory() *OracleExtractorFactory {
 type OracleExtractorFactory struct{}
 
 // New return a Oracle extractor
-func (e *OracleExtractorFactory) New(url string, schema string) relation.Extractor {
-	return NewSQLExtractor(url, schema, OracleDialect{})
+func (e *OracleExtractorFactory) New(url string, schema string, options dataconnector.Options) relation.Extractor {
+	return NewSQLExtractor(url, schema, options, OracleDialect{})
 }
 
 type OracleDialect struct{}
 
-func (d OracleDialect) SQL(schema string) string {
+func (d OracleDialect) SQL(filter relation.Filter) string {
 	SQL := `
 	SELECT
 	a.constraint_name name,
+	a.owner child_schema,
 	a.table_name child_table,
 	a.COLUMN_NAME child_key,
+	c_pk.owner parent_schema,
 	c_pk.table_name parent_table,
 	a_pk.COLUMN_NAME parent_key
 FROM all_cons_columns a
@@ -42,14 +44,15 @@ JOIN all_cons_columns a_pk ON c_pk.CONSTRAINT_NAME = a_pk.CONSTRAINT_NAME
 WHERE
 `
 
-	if schema == "" {
+	if len(filter.Schemas) == 0 {
 		SQL += "a.owner = user"
 	} else {
-		SQL += fmt.Sprintf("a.owner = '%s'", schema)
+		SQL += sqlfilter.In("a.owner", filter.Schemas)
 	}
+	SQL += sqlfilter.Patterns("a.table_name", filter.Include, filter.Exclude, sqlfilter.OracleRegexp)
 
 	SQL += `
-ORDER by 1, 2 asc
+ORDER by 1, 3 asc
 `
 	return SQL
 }
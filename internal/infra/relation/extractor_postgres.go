@@ -18,12 +18,11 @@
 package relation
 
 import (
-
 	// import postgresql connector
-	"fmt"
-
 	_ "github.com/lib/pq"
 
+	"github.com/cgi-fr/lino/internal/infra/sqlfilter"
+	"github.com/cgi-fr/lino/pkg/dataconnector"
 	"github.com/cgi-fr/lino/pkg/relation"
 )
 
@@ -36,18 +35,20 @@ func NewPostgresExtractorFactory() *PostgresExtractorFactory {
 type PostgresExtractorFactory struct{}
 
 // New return a Postgres extractor
-func (e *PostgresExtractorFactory) New(url string, schema string) relation.Extractor {
-	return NewSQLExtractor(url, schema, PostgresDialect{})
+func (e *PostgresExtractorFactory) New(url string, schema string, options dataconnector.Options) relation.Extractor {
+	return NewSQLExtractor(url, schema, options, PostgresDialect{})
 }
 
 type PostgresDialect struct{}
 
-func (d PostgresDialect) SQL(schema string) string {
+func (d PostgresDialect) SQL(filter relation.Filter) string {
 	SQL := `
 SELECT
     tc.constraint_name,
+    tc.table_schema,
     tc.table_name,
     kcu.column_name,
+    ccu.table_schema AS foreign_table_schema,
     ccu.table_name AS foreign_table_name,
     ccu.column_name AS foreign_column_name
 FROM
@@ -57,12 +58,13 @@ FROM
       AND tc.table_schema = kcu.table_schema
     JOIN information_schema.constraint_column_usage AS ccu
       ON ccu.constraint_name = tc.constraint_name
-      AND ccu.table_schema = tc.table_schema
+      AND ccu.constraint_schema = tc.constraint_schema
 WHERE tc.constraint_type = 'FOREIGN KEY'
             `
 
-	if schema != "" {
-		SQL += fmt.Sprintf("AND tc.table_schema = '%s'", schema)
+	if len(filter.Schemas) > 0 {
+		SQL += "AND " + sqlfilter.In("tc.table_schema", filter.Schemas)
 	}
+	SQL += sqlfilter.Patterns("tc.table_name", filter.Include, filter.Exclude, sqlfilter.PostgresRegexp)
 	return SQL
 }
@@ -18,33 +18,43 @@
 package relation
 
 import (
+	"github.com/cgi-fr/lino/internal/infra/connection"
+	"github.com/cgi-fr/lino/pkg/dataconnector"
 	"github.com/cgi-fr/lino/pkg/relation"
-	"github.com/xo/dburl"
 )
 
 // SQLExtractor provides relation extraction logic from SQL database.
 type SQLExtractor struct {
 	url     string
+	options dataconnector.Options
 	schema  string
 	dialect Dialect
 }
 
 type Dialect interface {
-	SQL(schema string) string
+	// SQL returns the name, child schema, child table, child column, parent schema, parent table and parent column of the foreign keys of the child tables matching filter
+	SQL(filter relation.Filter) string
 }
 
 // NewSQLExtractor creates a new SQL extractor.
-func NewSQLExtractor(url string, schema string, dialect Dialect) *SQLExtractor {
+func NewSQLExtractor(url string, schema string, options dataconnector.Options, dialect Dialect) *SQLExtractor {
 	return &SQLExtractor{
 		url:     url,
+		options: options,
 		schema:  schema,
 		dialect: dialect,
 	}
 }
 
-// Extract relations from the database.
-func (e *SQLExtractor) Extract() ([]relation.Relation, *relation.Error) {
-	db, err := dburl.Open(e.url)
+// Extract relations of the child tables matching filter from the database, table names are prefixed by their schema if filter has schemas,
+// a parent table in another schema than its child is always prefixed by its schema.
+func (e *SQLExtractor) Extract(filter relation.Filter) ([]relation.Relation, *relation.Error) {
+	qualify := len(filter.Schemas) > 0
+	if !qualify && e.schema != "" {
+		filter.Schemas = []string{e.schema}
+	}
+
+	db, err := connection.Open(e.url, e.options)
 
 	if err != nil {
 		return nil, &relation.Error{Description: err.Error()}
@@ -56,7 +66,7 @@ func (e *SQLExtractor) Extract() ([]relation.Relation, *relation.Error) {
 		return nil, &relation.Error{Description: err.Error()}
 	}
 
-	rows, err := db.Query(e.dialect.SQL(e.schema))
+	rows, err := db.Query(e.dialect.SQL(filter))
 	if err != nil {
 		return nil, &relation.Error{Description: err.Error()}
 	}
@@ -65,18 +75,27 @@ func (e *SQLExtractor) Extract() ([]relation.Relation, *relation.Error) {
 
 	var (
 		relationName string
+		sourceSchema string
 		sourceTable  string
 		sourceColumn string
+		targetSchema string
 		targetTable  string
 		targetColumn string
 	)
 
 	for rows.Next() {
-		err := rows.Scan(&relationName, &sourceTable, &sourceColumn, &targetTable, &targetColumn)
+		err := rows.Scan(&relationName, &sourceSchema, &sourceTable, &sourceColumn, &targetSchema, &targetTable, &targetColumn)
 		if err != nil {
 			return nil, &relation.Error{Description: err.Error()}
 		}
 
+		if qualify {
+			sourceTable = sourceSchema + "." + sourceTable
+		}
+		if qualify || targetSchema != sourceSchema {
+			targetTable = targetSchema + "." + targetTable
+		}
+
 		relation := relation.Relation{
 			Name: relationName,
 			Parent: relation.Table{
@@ -0,0 +1,71 @@
+// Copyright (C) 2021 CGI France
+//
+// This file is part of LINO.
+//
+// LINO is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// LINO is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with LINO.  If not, see <http://www.gnu.org/licenses/>.
+
+package relation
+
+import (
+	"fmt"
+
+	"github.com/cgi-fr/lino/pkg/dataconnector"
+	"github.com/cgi-fr/lino/pkg/relation"
+)
+
+// NewOracleInferenceReaderFactory creates a new oracle inference reader factory.
+func NewOracleInferenceReaderFactory() *OracleInferenceReaderFactory {
+	return &OracleInferenceReaderFactory{}
+}
+
+// OracleInferenceReaderFactory exposes methods to create new Oracle inference readers.
+type OracleInferenceReaderFactory struct{}
+
+// New return a Oracle inference reader
+func (e *OracleInferenceReaderFactory) New(url string, schema string, options dataconnector.Options) relation.InferenceReader {
+	return NewSQLInferenceReader(url, schema, options, OracleInferenceDialect{})
+}
+
+// OracleInferenceDialect reads columns from all_tab_columns and compares values as text.
+type OracleInferenceDialect struct{}
+
+func (d OracleInferenceDialect) ColumnsSQL(schema string) string {
+	SQL := `
+SELECT
+	table_name,
+	column_name
+ FROM all_tab_columns
+ where
+	`
+
+	if schema == "" {
+		SQL += "owner = user"
+	} else {
+		SQL += fmt.Sprintf("owner = '%s'", schema)
+	}
+
+	SQL += `
+	and table_name in (select table_name from all_tables where all_tables.owner = all_tab_columns.owner)
+ order by table_name, column_id
+	`
+
+	return SQL
+}
+
+func (d OracleInferenceDialect) CoverageSQL(schema string, child string, childColumn string, parent string, parentColumn string, sampleSize uint) string {
+	return fmt.Sprintf(`SELECT COUNT(*),
+	NVL(SUM(CASE WHEN CAST(s.v AS VARCHAR2(4000)) IN (SELECT CAST(p.%[4]s AS VARCHAR2(4000)) FROM %[3]s p) THEN 1 ELSE 0 END), 0)
+FROM (SELECT %[2]s AS v FROM %[1]s WHERE %[2]s IS NOT NULL AND ROWNUM <= %[5]d) s`,
+		tableName(schema, child), childColumn, tableName(schema, parent), parentColumn, sampleSize)
+}
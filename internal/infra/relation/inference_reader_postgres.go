@@ -0,0 +1,74 @@
+// Copyright (C) 2021 CGI France
+//
+// This file is part of LINO.
+//
+// LINO is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// LINO is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with LINO.  If not, see <http://www.gnu.org/licenses/>.
+
+package relation
+
+import (
+	"fmt"
+
+	// import postgresql connector
+	_ "github.com/lib/pq"
+
+	"github.com/cgi-fr/lino/pkg/dataconnector"
+	"github.com/cgi-fr/lino/pkg/relation"
+)
+
+// NewPostgresInferenceReaderFactory creates a new postgres inference reader factory.
+func NewPostgresInferenceReaderFactory() *PostgresInferenceReaderFactory {
+	return &PostgresInferenceReaderFactory{}
+}
+
+// PostgresInferenceReaderFactory exposes methods to create new Postgres inference readers.
+type PostgresInferenceReaderFactory struct{}
+
+// New return a Postgres inference reader
+func (e *PostgresInferenceReaderFactory) New(url string, schema string, options dataconnector.Options) relation.InferenceReader {
+	return NewSQLInferenceReader(url, schema, options, PostgresInferenceDialect{})
+}
+
+// PostgresInferenceDialect reads columns from information_schema and compares values as text.
+type PostgresInferenceDialect struct{}
+
+func (d PostgresInferenceDialect) ColumnsSQL(schema string) string {
+	SQL := `SELECT c.table_name,
+	c.column_name
+FROM information_schema.columns c
+JOIN information_schema.tables t
+ON t.table_schema = c.table_schema
+AND t.table_name = c.table_name
+WHERE t.table_type = 'BASE TABLE'
+`
+
+	if schema != "" {
+		SQL += fmt.Sprintf("AND c.table_schema = '%s'", schema)
+	} else {
+		SQL += "AND c.table_schema = current_schema()"
+	}
+
+	SQL += `
+ORDER BY c.table_name,
+	c.ordinal_position`
+
+	return SQL
+}
+
+func (d PostgresInferenceDialect) CoverageSQL(schema string, child string, childColumn string, parent string, parentColumn string, sampleSize uint) string {
+	return fmt.Sprintf(`SELECT COUNT(*),
+	COALESCE(SUM(CASE WHEN CAST(s.v AS TEXT) IN (SELECT CAST(p.%[4]s AS TEXT) FROM %[3]s p) THEN 1 ELSE 0 END), 0)
+FROM (SELECT %[2]s AS v FROM %[1]s WHERE %[2]s IS NOT NULL LIMIT %[5]d) s`,
+		tableName(schema, child), childColumn, tableName(schema, parent), parentColumn, sampleSize)
+}
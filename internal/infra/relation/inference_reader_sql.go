@@ -0,0 +1,109 @@
+// Copyright (C) 2021 CGI France
+//
+// This file is part of LINO.
+//
+// LINO is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// LINO is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with LINO.  If not, see <http://www.gnu.org/licenses/>.
+
+package relation
+
+import (
+	"strings"
+
+	"github.com/cgi-fr/lino/pkg/dataconnector"
+	"github.com/cgi-fr/lino/pkg/relation"
+)
+
+// InferenceDialect provides the queries used to infer relations.
+type InferenceDialect interface {
+	// ColumnsSQL returns the table name and column name of each column
+	ColumnsSQL(schema string) string
+	// CoverageSQL returns the number of sampled non null values of the child column and the number of them found in the parent column
+	CoverageSQL(schema string, child string, childColumn string, parent string, parentColumn string, sampleSize uint) string
+}
+
+// SQLInferenceReader reads columns and samples data from SQL database.
+type SQLInferenceReader struct {
+	sqlDatabase
+	schema  string
+	dialect InferenceDialect
+}
+
+// NewSQLInferenceReader creates a new SQL inference reader.
+func NewSQLInferenceReader(url string, schema string, options dataconnector.Options, dialect InferenceDialect) *SQLInferenceReader {
+	return &SQLInferenceReader{
+		sqlDatabase: sqlDatabase{url: url, options: options},
+		schema:      schema,
+		dialect:     dialect,
+	}
+}
+
+// Columns returns the column names of each table.
+func (r *SQLInferenceReader) Columns() (map[string][]string, *relation.Error) {
+	if err := r.open(); err != nil {
+		return nil, err
+	}
+
+	rows, err := r.db.Query(r.dialect.ColumnsSQL(r.schema))
+	if err != nil {
+		return nil, &relation.Error{Description: err.Error()}
+	}
+	defer rows.Close()
+
+	result := map[string][]string{}
+
+	var (
+		tableName  string
+		columnName string
+	)
+
+	for rows.Next() {
+		err := rows.Scan(&tableName, &columnName)
+		if err != nil {
+			return nil, &relation.Error{Description: err.Error()}
+		}
+		result[tableName] = append(result[tableName], columnName)
+	}
+	err = rows.Err()
+	if err != nil {
+		return nil, &relation.Error{Description: err.Error()}
+	}
+
+	return result, nil
+}
+
+// Coverage returns the ratio of sampleSize non null values of childColumn found in parentColumn, 0 if the child column has no value.
+func (r *SQLInferenceReader) Coverage(child string, childColumn string, parent string, parentColumn string, sampleSize uint) (float64, *relation.Error) {
+	if err := r.open(); err != nil {
+		return 0, err
+	}
+
+	var sampled, found float64
+	err := r.db.QueryRow(r.dialect.CoverageSQL(r.schema, child, childColumn, parent, parentColumn, sampleSize)).Scan(&sampled, &found)
+	if err != nil {
+		return 0, &relation.Error{Description: err.Error()}
+	}
+
+	if sampled == 0 {
+		return 0, nil
+	}
+	return found / sampled, nil
+}
+
+// tableName returns the table name with the dataconnector schema, unless it is already prefixed by a schema
+func tableName(schema string, table string) string {
+	if schema == "" || strings.Contains(table, ".") {
+		return table
+	}
+	return schema + "." + table
+}
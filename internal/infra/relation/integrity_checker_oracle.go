@@ -0,0 +1,58 @@
+// Copyright (C) 2021 CGI France
+//
+// This file is part of LINO.
+//
+// LINO is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// LINO is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with LINO.  If not, see <http://www.gnu.org/licenses/>.
+
+package relation
+
+import (
+	"fmt"
+
+	"github.com/cgi-fr/lino/pkg/dataconnector"
+	"github.com/cgi-fr/lino/pkg/relation"
+)
+
+// NewOracleIntegrityCheckerFactory creates a new oracle integrity checker factory.
+func NewOracleIntegrityCheckerFactory() *OracleIntegrityCheckerFactory {
+	return &OracleIntegrityCheckerFactory{}
+}
+
+// OracleIntegrityCheckerFactory exposes methods to create new Oracle integrity checkers.
+type OracleIntegrityCheckerFactory struct{}
+
+// New return a Oracle integrity checker
+func (e *OracleIntegrityCheckerFactory) New(url string, schema string, options dataconnector.Options) relation.IntegrityChecker {
+	return NewSQLIntegrityChecker(url, schema, options, OracleIntegrityDialect{})
+}
+
+// OracleIntegrityDialect compares keys of different types as text.
+type OracleIntegrityDialect struct{}
+
+func (d OracleIntegrityDialect) Text(column string) string {
+	return fmt.Sprintf("CAST(%s AS VARCHAR2(4000))", column)
+}
+
+func (d OracleIntegrityDialect) ColumnTypeSQL() string {
+	// an empty string is bound as NULL
+	return `SELECT data_type
+FROM all_tab_columns
+WHERE owner = COALESCE(:1, SYS_CONTEXT('USERENV', 'CURRENT_SCHEMA'))
+AND table_name = :2
+AND column_name = :3`
+}
+
+func (d OracleIntegrityDialect) Limit(query string, rows uint) string {
+	return fmt.Sprintf("SELECT * FROM (%s) WHERE ROWNUM <= %d", query, rows)
+}
@@ -0,0 +1,60 @@
+// Copyright (C) 2021 CGI France
+//
+// This file is part of LINO.
+//
+// LINO is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// LINO is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with LINO.  If not, see <http://www.gnu.org/licenses/>.
+
+package relation
+
+import (
+	"fmt"
+
+	// import postgresql connector
+	_ "github.com/lib/pq"
+
+	"github.com/cgi-fr/lino/pkg/dataconnector"
+	"github.com/cgi-fr/lino/pkg/relation"
+)
+
+// NewPostgresIntegrityCheckerFactory creates a new postgres integrity checker factory.
+func NewPostgresIntegrityCheckerFactory() *PostgresIntegrityCheckerFactory {
+	return &PostgresIntegrityCheckerFactory{}
+}
+
+// PostgresIntegrityCheckerFactory exposes methods to create new Postgres integrity checkers.
+type PostgresIntegrityCheckerFactory struct{}
+
+// New return a Postgres integrity checker
+func (e *PostgresIntegrityCheckerFactory) New(url string, schema string, options dataconnector.Options) relation.IntegrityChecker {
+	return NewSQLIntegrityChecker(url, schema, options, PostgresIntegrityDialect{})
+}
+
+// PostgresIntegrityDialect compares keys of different types as text.
+type PostgresIntegrityDialect struct{}
+
+func (d PostgresIntegrityDialect) Text(column string) string {
+	return fmt.Sprintf("CAST(%s AS TEXT)", column)
+}
+
+func (d PostgresIntegrityDialect) ColumnTypeSQL() string {
+	return `SELECT udt_name
+FROM information_schema.columns
+WHERE table_schema = COALESCE(NULLIF($1, ''), current_schema())
+AND table_name = $2
+AND column_name = $3`
+}
+
+func (d PostgresIntegrityDialect) Limit(query string, rows uint) string {
+	return fmt.Sprintf("%s LIMIT %d", query, rows)
+}
@@ -0,0 +1,176 @@
+// Copyright (C) 2021 CGI France
+//
+// This file is part of LINO.
+//
+// LINO is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// LINO is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with LINO.  If not, see <http://www.gnu.org/licenses/>.
+
+package relation
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/cgi-fr/lino/pkg/dataconnector"
+	"github.com/cgi-fr/lino/pkg/relation"
+)
+
+// IntegrityDialect provides the SQL syntax specific to a database to check relations.
+type IntegrityDialect interface {
+	// Text converts a column to text so that keys of different types can be compared
+	Text(column string) string
+	// ColumnTypeSQL returns the data type of a column, its arguments are the schema (empty for the current schema), the table and the column
+	ColumnTypeSQL() string
+	// Limit restricts the query to its first rows
+	Limit(query string, rows uint) string
+}
+
+// SQLIntegrityChecker checks relations against the data of a SQL database with anti-join queries.
+type SQLIntegrityChecker struct {
+	sqlDatabase
+	schema  string
+	dialect IntegrityDialect
+}
+
+// NewSQLIntegrityChecker creates a new SQL integrity checker.
+func NewSQLIntegrityChecker(url string, schema string, options dataconnector.Options, dialect IntegrityDialect) *SQLIntegrityChecker {
+	return &SQLIntegrityChecker{
+		sqlDatabase: sqlDatabase{url: url, options: options},
+		schema:      schema,
+		dialect:     dialect,
+	}
+}
+
+// NullKeys returns the number of child rows and the number of them with a null key column.
+func (c *SQLIntegrityChecker) NullKeys(rel relation.Relation) (uint, uint, *relation.Error) {
+	if err := c.open(); err != nil {
+		return 0, 0, err
+	}
+
+	nulls := []string{}
+	for _, key := range rel.Child.Keys {
+		nulls = append(nulls, "c."+key+" IS NULL")
+	}
+
+	SQL := fmt.Sprintf("SELECT COUNT(*), COALESCE(SUM(CASE WHEN %s THEN 1 ELSE 0 END), 0) FROM %s c",
+		strings.Join(nulls, " OR "), tableName(c.schema, rel.Child.Name))
+
+	var rows, nullKeys uint
+	err := c.db.QueryRow(SQL).Scan(&rows, &nullKeys)
+	if err != nil {
+		return 0, 0, &relation.Error{Description: err.Error()}
+	}
+
+	return rows, nullKeys, nil
+}
+
+// Orphans returns the number of child rows with non null keys not found in the parent table and the keys of at most sampleSize of them.
+func (c *SQLIntegrityChecker) Orphans(rel relation.Relation, sampleSize uint) (uint, [][]string, *relation.Error) {
+	if err := c.open(); err != nil {
+		return 0, nil, err
+	}
+
+	from, e := c.orphansFrom(rel)
+	if e != nil {
+		return 0, nil, e
+	}
+
+	var orphans uint
+	err := c.db.QueryRow("SELECT COUNT(*) " + from).Scan(&orphans)
+	if err != nil {
+		return 0, nil, &relation.Error{Description: err.Error()}
+	}
+
+	samples := [][]string{}
+	if orphans == 0 || sampleSize == 0 {
+		return orphans, samples, nil
+	}
+
+	keys := []string{}
+	for _, key := range rel.Child.Keys {
+		keys = append(keys, c.dialect.Text("c."+key))
+	}
+
+	rows, err := c.db.Query(c.dialect.Limit("SELECT "+strings.Join(keys, ", ")+" "+from, sampleSize))
+	if err != nil {
+		return 0, nil, &relation.Error{Description: err.Error()}
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		values := make([]string, len(keys))
+		pointers := make([]interface{}, len(keys))
+		for i := range values {
+			pointers[i] = &values[i]
+		}
+		err := rows.Scan(pointers...)
+		if err != nil {
+			return 0, nil, &relation.Error{Description: err.Error()}
+		}
+		samples = append(samples, values)
+	}
+	err = rows.Err()
+	if err != nil {
+		return 0, nil, &relation.Error{Description: err.Error()}
+	}
+
+	return orphans, samples, nil
+}
+
+// orphansFrom returns the FROM and WHERE clauses selecting child rows with non null keys without parent,
+// keys are compared as text only if the child and parent columns have different types.
+func (c *SQLIntegrityChecker) orphansFrom(rel relation.Relation) (string, *relation.Error) {
+	notNulls := []string{}
+	joins := []string{}
+	for i, key := range rel.Child.Keys {
+		notNulls = append(notNulls, "c."+key+" IS NOT NULL")
+
+		childType, err := c.columnType(rel.Child.Name, key)
+		if err != nil {
+			return "", err
+		}
+		parentType, err := c.columnType(rel.Parent.Name, rel.Parent.Keys[i])
+		if err != nil {
+			return "", err
+		}
+
+		if childType != "" && childType == parentType {
+			joins = append(joins, "p."+rel.Parent.Keys[i]+" = c."+key)
+		} else {
+			joins = append(joins, c.dialect.Text("p."+rel.Parent.Keys[i])+" = "+c.dialect.Text("c."+key))
+		}
+	}
+
+	return fmt.Sprintf("FROM %s c WHERE %s AND NOT EXISTS (SELECT 1 FROM %s p WHERE %s)",
+		tableName(c.schema, rel.Child.Name), strings.Join(notNulls, " AND "),
+		tableName(c.schema, rel.Parent.Name), strings.Join(joins, " AND ")), nil
+}
+
+// columnType returns the data type of the column of the table, empty if the column is not found.
+func (c *SQLIntegrityChecker) columnType(table string, column string) (string, *relation.Error) {
+	schema := c.schema
+	if i := strings.LastIndex(table, "."); i >= 0 {
+		schema, table = table[:i], table[i+1:]
+	}
+
+	var dataType string
+	err := c.db.QueryRow(c.dialect.ColumnTypeSQL(), schema, table, column).Scan(&dataType)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	if err != nil {
+		return "", &relation.Error{Description: err.Error()}
+	}
+	return dataType, nil
+}
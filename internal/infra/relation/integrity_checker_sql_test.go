@@ -0,0 +1,130 @@
+// Copyright (C) 2021 CGI France
+//
+// This file is part of LINO.
+//
+// LINO is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// LINO is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with LINO.  If not, see <http://www.gnu.org/licenses/>.
+
+package relation
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/cgi-fr/lino/pkg/dataconnector"
+	"github.com/cgi-fr/lino/pkg/relation"
+	"github.com/stretchr/testify/assert"
+)
+
+// integrityDriver returns the type of the columns found in types and 0 for the other queries, the queries are recorded
+type integrityDriver struct {
+	types   map[string]string
+	queries []string
+}
+
+func (d *integrityDriver) Open(name string) (driver.Conn, error) { return &integrityConn{d}, nil }
+
+type integrityConn struct{ d *integrityDriver }
+
+func (c *integrityConn) Prepare(query string) (driver.Stmt, error) {
+	return &integrityStmt{c.d, query}, nil
+}
+func (c *integrityConn) Close() error              { return nil }
+func (c *integrityConn) Begin() (driver.Tx, error) { return nil, errors.New("not implemented") }
+
+type integrityStmt struct {
+	d     *integrityDriver
+	query string
+}
+
+func (s *integrityStmt) Close() error  { return nil }
+func (s *integrityStmt) NumInput() int { return -1 }
+func (s *integrityStmt) Exec(args []driver.Value) (driver.Result, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (s *integrityStmt) Query(args []driver.Value) (driver.Rows, error) {
+	if len(args) == 3 {
+		dataType, ok := s.d.types[fmt.Sprintf("%v.%v.%v", args[0], args[1], args[2])]
+		if !ok {
+			return &integrityRows{}, nil
+		}
+		return &integrityRows{values: []driver.Value{dataType}}, nil
+	}
+	s.d.queries = append(s.d.queries, s.query)
+	return &integrityRows{values: []driver.Value{int64(0)}}, nil
+}
+
+type integrityRows struct{ values []driver.Value }
+
+func (r *integrityRows) Columns() []string { return []string{"value"} }
+func (r *integrityRows) Close() error      { return nil }
+func (r *integrityRows) Next(dest []driver.Value) error {
+	if len(r.values) == 0 {
+		return io.EOF
+	}
+	dest[0], r.values = r.values[0], r.values[1:]
+	return nil
+}
+
+var integrity = &integrityDriver{types: map[string]string{
+	"public.customer.store_id": "int4",
+	"public.store.store_id":    "int4",
+	"sales.payment.customer":   "varchar",
+	"public.customer.id":       "int4",
+}}
+
+func init() {
+	sql.Register("lino-integrity", integrity)
+}
+
+func TestOrphansComparesNativeColumns(t *testing.T) {
+	db, err := sql.Open("lino-integrity", "")
+	assert.Nil(t, err)
+
+	checker := NewSQLIntegrityChecker("", "public", dataconnector.Options{}, PostgresIntegrityDialect{})
+	checker.db = db
+	defer checker.Close()
+
+	orphans, _, e := checker.Orphans(relation.Relation{
+		Name:   "customer_store",
+		Parent: relation.Table{Name: "store", Keys: []string{"store_id"}},
+		Child:  relation.Table{Name: "customer", Keys: []string{"store_id"}},
+	}, 0)
+	assert.Nil(t, e)
+	assert.Equal(t, uint(0), orphans)
+
+	_, _, e = checker.Orphans(relation.Relation{
+		Name:   "payment_customer",
+		Parent: relation.Table{Name: "customer", Keys: []string{"id"}},
+		Child:  relation.Table{Name: "sales.payment", Keys: []string{"customer"}},
+	}, 0)
+	assert.Nil(t, e)
+
+	_, _, e = checker.Orphans(relation.Relation{
+		Name:   "rental_customer",
+		Parent: relation.Table{Name: "customer", Keys: []string{"id"}},
+		Child:  relation.Table{Name: "rental", Keys: []string{"customer_id"}},
+	}, 0)
+	assert.Nil(t, e)
+
+	assert.Len(t, integrity.queries, 3)
+	assert.True(t, strings.HasSuffix(integrity.queries[0], "WHERE p.store_id = c.store_id)"), integrity.queries[0])
+	assert.True(t, strings.HasSuffix(integrity.queries[1], "WHERE CAST(p.id AS TEXT) = CAST(c.customer AS TEXT))"), integrity.queries[1])
+	assert.True(t, strings.HasSuffix(integrity.queries[2], "WHERE CAST(p.id AS TEXT) = CAST(c.customer_id AS TEXT))"), integrity.queries[2])
+}
@@ -2,20 +2,23 @@
 
 package relation
 
-import mock "github.com/stretchr/testify/mock"
+import (
+	relation "github.com/cgi-fr/lino/pkg/relation"
+	mock "github.com/stretchr/testify/mock"
+)
 
 // MockDialect is an autogenerated mock type for the Dialect type
 type MockDialect struct {
 	mock.Mock
 }
 
-// SQL provides a mock function with given fields: schema
-func (_m *MockDialect) SQL(schema string) string {
-	ret := _m.Called(schema)
+// SQL provides a mock function with given fields: filter
+func (_m *MockDialect) SQL(filter relation.Filter) string {
+	ret := _m.Called(filter)
 
 	var r0 string
-	if rf, ok := ret.Get(0).(func(string) string); ok {
-		r0 = rf(schema)
+	if rf, ok := ret.Get(0).(func(relation.Filter) string); ok {
+		r0 = rf(filter)
 	} else {
 		r0 = ret.Get(0).(string)
 	}
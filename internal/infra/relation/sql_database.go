@@ -0,0 +1,66 @@
+// Copyright (C) 2021 CGI France
+//
+// This file is part of LINO.
+//
+// LINO is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// LINO is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with LINO.  If not, see <http://www.gnu.org/licenses/>.
+
+package relation
+
+import (
+	"database/sql"
+
+	"github.com/cgi-fr/lino/internal/infra/connection"
+	"github.com/cgi-fr/lino/pkg/dataconnector"
+	"github.com/cgi-fr/lino/pkg/relation"
+)
+
+// sqlDatabase opens the connection to the database on first use.
+type sqlDatabase struct {
+	url     string
+	options dataconnector.Options
+	db      *sql.DB
+}
+
+// Close the database connection.
+func (d *sqlDatabase) Close() *relation.Error {
+	if d.db == nil {
+		return nil
+	}
+	err := d.db.Close()
+	d.db = nil
+	if err != nil {
+		return &relation.Error{Description: err.Error()}
+	}
+	return nil
+}
+
+func (d *sqlDatabase) open() *relation.Error {
+	if d.db != nil {
+		return nil
+	}
+
+	db, err := connection.Open(d.url, d.options)
+	if err != nil {
+		return &relation.Error{Description: err.Error()}
+	}
+
+	err = db.Ping()
+	if err != nil {
+		db.Close()
+		return &relation.Error{Description: err.Error()}
+	}
+
+	d.db = db
+	return nil
+}
@@ -19,6 +19,7 @@ package relation
 
 import (
 	"io/ioutil"
+	"os"
 
 	"github.com/cgi-fr/lino/pkg/relation"
 	"gopkg.in/yaml.v3"
@@ -35,9 +36,11 @@ type YAMLStructure struct {
 
 // YAMLRelation defines how to store a relation in YAML format.
 type YAMLRelation struct {
-	Name   string    `yaml:"name"`
-	Parent YAMLTable `yaml:"parent"`
-	Child  YAMLTable `yaml:"child"`
+	Name       string    `yaml:"name"`
+	Source     string    `yaml:"source,omitempty"`
+	Confidence float64   `yaml:"confidence,omitempty"`
+	Parent     YAMLTable `yaml:"parent"`
+	Child      YAMLTable `yaml:"child"`
 }
 
 // YAMLTable defines how to store a relation in YAML format.
@@ -64,7 +67,9 @@ func (s YAMLStorage) List() ([]relation.Relation, *relation.Error) {
 
 	for _, ym := range list.Relations {
 		m := relation.Relation{
-			Name: ym.Name,
+			Name:       ym.Name,
+			Source:     ym.Source,
+			Confidence: ym.Confidence,
 			Parent: relation.Table{
 				Name: ym.Parent.Name,
 				Keys: ym.Parent.Keys,
@@ -88,7 +93,9 @@ func (s YAMLStorage) Store(relations []relation.Relation) *relation.Error {
 
 	for _, r := range relations {
 		yml := YAMLRelation{
-			Name: r.Name,
+			Name:       r.Name,
+			Source:     r.Source,
+			Confidence: r.Confidence,
 			Parent: YAMLTable{
 				Name: r.Parent.Name,
 				Keys: r.Parent.Keys,
@@ -114,6 +121,10 @@ func readFile() (*YAMLStructure, *relation.Error) {
 		Version: Version,
 	}
 
+	if _, err := os.Stat("relations.yaml"); os.IsNotExist(err) {
+		return list, nil
+	}
+
 	dat, err := ioutil.ReadFile("relations.yaml")
 	if err != nil {
 		return nil, &relation.Error{Description: err.Error()}
@@ -0,0 +1,83 @@
+// Copyright (C) 2021 CGI France
+//
+// This file is part of LINO.
+//
+// LINO is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// LINO is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with LINO.  If not, see <http://www.gnu.org/licenses/>.
+
+package schema
+
+import (
+	"strings"
+
+	"github.com/cgi-fr/lino/pkg/dataconnector"
+	"github.com/cgi-fr/lino/pkg/schema"
+)
+
+// NewOracleApplierFactory creates a new oracle applier factory.
+func NewOracleApplierFactory() *OracleApplierFactory {
+	return &OracleApplierFactory{}
+}
+
+// OracleApplierFactory exposes methods to create new Oracle appliers.
+type OracleApplierFactory struct{}
+
+// New return a Oracle applier
+func (e *OracleApplierFactory) New(url string, schema string, options dataconnector.Options, dryRun bool) schema.Applier {
+	return NewSQLApplier(url, schema, options, OracleDDLDialect{}, dryRun)
+}
+
+// OracleDDLDialect maps portable types to Oracle types, booleans are stored as NUMBER(1)
+// and arrays as a CLOB holding their text representation.
+type OracleDDLDialect struct{}
+
+var oracleTypes = map[string]string{
+	"smallint":    "NUMBER(5)",
+	"integer":     "NUMBER(10)",
+	"bigint":      "NUMBER(19)",
+	"numeric":     "NUMBER",
+	"float":       "BINARY_FLOAT",
+	"double":      "BINARY_DOUBLE",
+	"varchar":     "VARCHAR2",
+	"char":        "CHAR",
+	"text":        "CLOB",
+	"boolean":     "NUMBER(1)",
+	"date":        "DATE",
+	"timestamp":   "TIMESTAMP",
+	"timestamptz": "TIMESTAMP WITH TIME ZONE",
+	"blob":        "BLOB",
+}
+
+func (d OracleDDLDialect) Type(portable string) string {
+	if strings.HasSuffix(portable, "[]") {
+		return "CLOB"
+	}
+	if portable == "varchar" {
+		return "VARCHAR2(4000)"
+	}
+	return mapType(oracleTypes, portable)
+}
+
+func (d OracleDDLDialect) Default(column schema.Column, schema string) string {
+	if column.Sequence != "" {
+		return qualify(schema, column.Sequence) + ".NEXTVAL"
+	}
+	switch column.Default {
+	case "true":
+		return "1"
+	case "false":
+		return "0"
+	default:
+		return column.Default
+	}
+}
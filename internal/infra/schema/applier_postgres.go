@@ -0,0 +1,63 @@
+// Copyright (C) 2021 CGI France
+//
+// This file is part of LINO.
+//
+// LINO is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// LINO is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with LINO.  If not, see <http://www.gnu.org/licenses/>.
+
+package schema
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cgi-fr/lino/pkg/dataconnector"
+	"github.com/cgi-fr/lino/pkg/schema"
+)
+
+// NewPostgresApplierFactory creates a new postgres applier factory.
+func NewPostgresApplierFactory() *PostgresApplierFactory {
+	return &PostgresApplierFactory{}
+}
+
+// PostgresApplierFactory exposes methods to create new Postgres appliers.
+type PostgresApplierFactory struct{}
+
+// New return a Postgres applier
+func (e *PostgresApplierFactory) New(url string, schema string, options dataconnector.Options, dryRun bool) schema.Applier {
+	return NewSQLApplier(url, schema, options, PostgresDDLDialect{}, dryRun)
+}
+
+// PostgresDDLDialect maps portable types to Postgres types.
+type PostgresDDLDialect struct{}
+
+var postgresTypes = map[string]string{
+	"float":       "real",
+	"double":      "double precision",
+	"timestamptz": "timestamp with time zone",
+	"blob":        "bytea",
+}
+
+func (d PostgresDDLDialect) Type(portable string) string {
+	if strings.HasSuffix(portable, "[]") {
+		return d.Type(strings.TrimSuffix(portable, "[]")) + "[]"
+	}
+	return mapType(postgresTypes, portable)
+}
+
+func (d PostgresDDLDialect) Default(column schema.Column, schema string) string {
+	if column.Sequence != "" {
+		return fmt.Sprintf("nextval('%s')", qualify(schema, column.Sequence))
+	}
+	return column.Default
+}
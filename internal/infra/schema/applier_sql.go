@@ -0,0 +1,168 @@
+// Copyright (C) 2021 CGI France
+//
+// This file is part of LINO.
+//
+// LINO is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// LINO is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with LINO.  If not, see <http://www.gnu.org/licenses/>.
+
+package schema
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/cgi-fr/lino/internal/infra/connection"
+	"github.com/cgi-fr/lino/pkg/dataconnector"
+	"github.com/cgi-fr/lino/pkg/schema"
+)
+
+// DDLDialect provides the SQL syntax specific to a database to create a schema.
+type DDLDialect interface {
+	// Type returns the type of the database for a portable type, unknown types are returned unchanged
+	Type(portable string) string
+	// Default returns the default value of a column, empty if it has none
+	Default(column schema.Column, schema string) string
+}
+
+// SQLApplier creates tables and sequences in a SQL database.
+type SQLApplier struct {
+	url     string
+	options dataconnector.Options
+	schema  string
+	dialect DDLDialect
+	dryRun  bool
+	db      *sql.DB
+}
+
+// NewSQLApplier creates a new SQL applier, a dry run applier doesn't connect to the database.
+func NewSQLApplier(url string, schema string, options dataconnector.Options, dialect DDLDialect, dryRun bool) *SQLApplier {
+	return &SQLApplier{
+		url:     url,
+		options: options,
+		schema:  schema,
+		dialect: dialect,
+		dryRun:  dryRun,
+	}
+}
+
+// CreateSequence creates a sequence.
+func (a *SQLApplier) CreateSequence(sequence schema.Sequence) (string, *schema.Error) {
+	increment := sequence.Increment
+	if increment == 0 {
+		increment = 1
+	}
+	statement := fmt.Sprintf("CREATE SEQUENCE %s START WITH %d INCREMENT BY %d", qualify(a.schema, sequence.Name), sequence.Start, increment)
+	return statement, a.exec(statement)
+}
+
+// CreateTable creates a table with its columns, primary key, unique keys and foreign keys.
+func (a *SQLApplier) CreateTable(table schema.Table) (string, *schema.Error) {
+	definitions := []string{}
+
+	for _, column := range table.Columns {
+		definition := column.Name + " " + a.dialect.Type(column.Type)
+		if value := a.dialect.Default(column, a.schema); value != "" {
+			definition += " DEFAULT " + value
+		}
+		if !column.Nullable {
+			definition += " NOT NULL"
+		}
+		definitions = append(definitions, definition)
+	}
+
+	if len(table.PrimaryKey) > 0 {
+		definitions = append(definitions, fmt.Sprintf("PRIMARY KEY (%s)", strings.Join(table.PrimaryKey, ", ")))
+	}
+
+	for _, keys := range table.UniqueKeys {
+		definitions = append(definitions, fmt.Sprintf("UNIQUE (%s)", strings.Join(keys, ", ")))
+	}
+
+	for _, foreignKey := range table.ForeignKeys {
+		definitions = append(definitions, a.foreignKey(foreignKey))
+	}
+
+	statement := fmt.Sprintf("CREATE TABLE %s (\n\t%s\n)", qualify(a.schema, table.Name), strings.Join(definitions, ",\n\t"))
+	return statement, a.exec(statement)
+}
+
+// AddForeignKey adds a foreign key to an existing table.
+func (a *SQLApplier) AddForeignKey(table string, foreignKey schema.ForeignKey) (string, *schema.Error) {
+	statement := fmt.Sprintf("ALTER TABLE %s ADD %s", qualify(a.schema, table), a.foreignKey(foreignKey))
+	return statement, a.exec(statement)
+}
+
+// Close the database connection.
+func (a *SQLApplier) Close() *schema.Error {
+	if a.db == nil {
+		return nil
+	}
+	err := a.db.Close()
+	a.db = nil
+	if err != nil {
+		return &schema.Error{Description: err.Error()}
+	}
+	return nil
+}
+
+func (a *SQLApplier) foreignKey(foreignKey schema.ForeignKey) string {
+	return fmt.Sprintf("CONSTRAINT %s FOREIGN KEY (%s) REFERENCES %s (%s)",
+		foreignKey.Name, strings.Join(foreignKey.Columns, ", "),
+		qualify(a.schema, foreignKey.References), strings.Join(foreignKey.ReferencedColumns, ", "))
+}
+
+func (a *SQLApplier) exec(statement string) *schema.Error {
+	if a.dryRun {
+		return nil
+	}
+
+	if a.db == nil {
+		db, err := connection.Open(a.url, a.options)
+		if err != nil {
+			return &schema.Error{Description: err.Error()}
+		}
+		a.db = db
+	}
+
+	_, err := a.db.Exec(statement)
+	if err != nil {
+		return &schema.Error{Description: err.Error()}
+	}
+	return nil
+}
+
+// qualify returns the name prefixed by the dataconnector schema
+func qualify(schema string, name string) string {
+	if schema == "" {
+		return name
+	}
+	return schema + "." + name
+}
+
+// mapType returns the type of the types map for a portable type with optional arguments (varchar(20), numeric(10,2)), unknown types are returned unchanged
+func mapType(types map[string]string, portable string) string {
+	name, args := portable, ""
+	if i := strings.Index(portable, "("); i >= 0 {
+		name, args = portable[:i], portable[i:]
+	}
+
+	native, ok := types[name]
+	if !ok {
+		return portable
+	}
+	if args == "" || strings.Contains(native, "(") {
+		return native
+	}
+	return native + args
+}
@@ -0,0 +1,174 @@
+// Copyright (C) 2021 CGI France
+//
+// This file is part of LINO.
+//
+// LINO is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// LINO is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with LINO.  If not, see <http://www.gnu.org/licenses/>.
+
+package schema
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/cgi-fr/lino/pkg/dataconnector"
+	"github.com/cgi-fr/lino/pkg/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+// portable returns the portable type of a column and fails the test if it can't be mapped
+func portable(t *testing.T, dialect Dialect, dataType string, length sql.NullInt64, precision sql.NullInt64, scale sql.NullInt64) string {
+	result, ok := dialect.Type(dataType, length, precision, scale)
+	assert.True(t, ok, dataType)
+	return result
+}
+
+func TestExtractPostgresTypeToOracle(t *testing.T) {
+	pg := PostgresDialect{}
+	columns := []schema.Column{
+		{Name: "film_id", Type: portable(t, pg, "integer", sql.NullInt64{}, sql.NullInt64{Int64: 32, Valid: true}, sql.NullInt64{Valid: true})},
+		{Name: "title", Type: portable(t, pg, "character varying", sql.NullInt64{Int64: 255, Valid: true}, sql.NullInt64{}, sql.NullInt64{})},
+		{Name: "rental_rate", Type: portable(t, pg, "numeric", sql.NullInt64{}, sql.NullInt64{Int64: 4, Valid: true}, sql.NullInt64{Int64: 2, Valid: true})},
+		{Name: "last_update", Type: portable(t, pg, "timestamp without time zone", sql.NullInt64{}, sql.NullInt64{}, sql.NullInt64{})},
+		{Name: "rating", Type: portable(t, pg, "enum", sql.NullInt64{Int64: 5, Valid: true}, sql.NullInt64{}, sql.NullInt64{})},
+		{Name: "special_features", Type: portable(t, pg, "text[]", sql.NullInt64{}, sql.NullInt64{}, sql.NullInt64{})},
+	}
+	columns[0].Default, columns[0].Sequence = pg.Default("nextval('film_film_id_seq'::regclass)")
+	columns[1].Default, columns[1].Sequence = pg.Default("'untitled'::character varying")
+	columns[2].Default, columns[2].Sequence = pg.Default("4.99")
+	columns[3].Default, columns[3].Sequence = pg.Default("now()")
+	columns[4].Default, columns[4].Sequence = pg.Default("'G'::mpaa_rating")
+	columns[4].Nullable = true
+	columns[5].Nullable = true
+
+	assert.Equal(t, []schema.Column{
+		{Name: "film_id", Type: "integer", Sequence: "film_film_id_seq"},
+		{Name: "title", Type: "varchar(255)", Default: "'untitled'"},
+		{Name: "rental_rate", Type: "numeric(4,2)", Default: "4.99"},
+		{Name: "last_update", Type: "timestamp", Default: "CURRENT_TIMESTAMP"},
+		{Name: "rating", Type: "varchar(5)", Default: "'G'", Nullable: true},
+		{Name: "special_features", Type: "text[]", Nullable: true},
+	}, columns)
+
+	applier := NewSQLApplier("", "SAKILA", dataconnector.Options{}, OracleDDLDialect{}, true)
+	statement, err := applier.CreateTable(schema.Table{
+		Name:        "film",
+		Columns:     columns,
+		PrimaryKey:  []string{"film_id"},
+		UniqueKeys:  [][]string{{"title"}},
+		ForeignKeys: []schema.ForeignKey{{Name: "film_language", Columns: []string{"language_id"}, References: "language", ReferencedColumns: []string{"language_id"}}},
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, `CREATE TABLE SAKILA.film (
+	film_id NUMBER(10) DEFAULT SAKILA.film_film_id_seq.NEXTVAL NOT NULL,
+	title VARCHAR2(255) DEFAULT 'untitled' NOT NULL,
+	rental_rate NUMBER(4,2) DEFAULT 4.99 NOT NULL,
+	last_update TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+	rating VARCHAR2(5) DEFAULT 'G',
+	special_features CLOB,
+	PRIMARY KEY (film_id),
+	UNIQUE (title),
+	CONSTRAINT film_language FOREIGN KEY (language_id) REFERENCES SAKILA.language (language_id)
+)`, statement)
+}
+
+func TestExtractOracleTypeToPostgres(t *testing.T) {
+	ora := OracleDialect{}
+	columns := []schema.Column{
+		{Name: "ID", Type: portable(t, ora, "NUMBER", sql.NullInt64{}, sql.NullInt64{}, sql.NullInt64{Valid: true})},
+		{Name: "CREATED", Type: portable(t, ora, "DATE", sql.NullInt64{}, sql.NullInt64{}, sql.NullInt64{})},
+		{Name: "ACTIVE", Type: portable(t, ora, "NUMBER", sql.NullInt64{}, sql.NullInt64{Int64: 1, Valid: true}, sql.NullInt64{Valid: true})},
+		{Name: "NOTES", Type: portable(t, ora, "CLOB", sql.NullInt64{}, sql.NullInt64{}, sql.NullInt64{})},
+	}
+	columns[0].Default, columns[0].Sequence = ora.Default(`"HR"."EMP_SEQ"."NEXTVAL"`)
+	columns[1].Default, columns[1].Sequence = ora.Default("sysdate ")
+	columns[2].Default, columns[2].Sequence = ora.Default("1")
+
+	applier := NewSQLApplier("", "", dataconnector.Options{}, PostgresDDLDialect{}, true)
+	statement, err := applier.CreateTable(schema.Table{Name: "EMP", Columns: columns, PrimaryKey: []string{"ID"}})
+
+	assert.Nil(t, err)
+	assert.Equal(t, `CREATE TABLE EMP (
+	ID integer DEFAULT nextval('EMP_SEQ') NOT NULL,
+	CREATED timestamp DEFAULT CURRENT_TIMESTAMP NOT NULL,
+	ACTIVE numeric(1) DEFAULT 1 NOT NULL,
+	NOTES text NOT NULL,
+	PRIMARY KEY (ID)
+)`, statement)
+
+	statement, err = applier.AddForeignKey("EMP", schema.ForeignKey{Name: "EMP_MANAGER", Columns: []string{"MANAGER_ID"}, References: "EMP", ReferencedColumns: []string{"ID"}})
+
+	assert.Nil(t, err)
+	assert.Equal(t, "ALTER TABLE EMP ADD CONSTRAINT EMP_MANAGER FOREIGN KEY (MANAGER_ID) REFERENCES EMP (ID)", statement)
+}
+
+func TestExtractPostgresArrayToPostgres(t *testing.T) {
+	pg := PostgresDialect{}
+	columns := []schema.Column{
+		{Name: "tags", Type: portable(t, pg, "character varying[]", sql.NullInt64{}, sql.NullInt64{}, sql.NullInt64{}), Nullable: true},
+		{Name: "scores", Type: portable(t, pg, "double precision[]", sql.NullInt64{}, sql.NullInt64{}, sql.NullInt64{}), Nullable: true},
+	}
+
+	applier := NewSQLApplier("", "", dataconnector.Options{}, PostgresDDLDialect{}, true)
+	statement, err := applier.CreateTable(schema.Table{Name: "film", Columns: columns})
+
+	assert.Nil(t, err)
+	assert.Equal(t, `CREATE TABLE film (
+	tags varchar[],
+	scores double precision[]
+)`, statement)
+}
+
+func TestExtractPostgresBuiltinTypeToOracle(t *testing.T) {
+	pg := PostgresDialect{}
+	columns := []schema.Column{
+		{Name: "id", Type: portable(t, pg, "uuid", sql.NullInt64{}, sql.NullInt64{}, sql.NullInt64{})},
+		{Name: "document", Type: portable(t, pg, "jsonb", sql.NullInt64{}, sql.NullInt64{}, sql.NullInt64{}), Nullable: true},
+		{Name: "settings", Type: portable(t, pg, "json", sql.NullInt64{}, sql.NullInt64{}, sql.NullInt64{}), Nullable: true},
+		{Name: "address", Type: portable(t, pg, "inet", sql.NullInt64{}, sql.NullInt64{}, sql.NullInt64{}), Nullable: true},
+		{Name: "device", Type: portable(t, pg, "macaddr", sql.NullInt64{}, sql.NullInt64{}, sql.NullInt64{}), Nullable: true},
+	}
+
+	applier := NewSQLApplier("", "", dataconnector.Options{}, OracleDDLDialect{}, true)
+	statement, err := applier.CreateTable(schema.Table{Name: "event", Columns: columns, PrimaryKey: []string{"id"}})
+
+	assert.Nil(t, err)
+	assert.Equal(t, `CREATE TABLE event (
+	id VARCHAR2(36) NOT NULL,
+	document CLOB,
+	settings CLOB,
+	address VARCHAR2(43),
+	device VARCHAR2(17),
+	PRIMARY KEY (id)
+)`, statement)
+
+	for _, dataType := range []string{"interval", "tsvector", "money", "time without time zone", "point", "tsvector[]"} {
+		_, ok := pg.Type(dataType, sql.NullInt64{}, sql.NullInt64{}, sql.NullInt64{})
+		assert.False(t, ok, dataType)
+	}
+}
+
+func TestExtractUnmappableType(t *testing.T) {
+	_, ok := PostgresDialect{}.Type("USER-DEFINED public.hstore", sql.NullInt64{}, sql.NullInt64{}, sql.NullInt64{})
+	assert.False(t, ok)
+
+	_, ok = PostgresDialect{}.Type("mpaa_rating[]", sql.NullInt64{}, sql.NullInt64{}, sql.NullInt64{})
+	assert.False(t, ok)
+
+	_, ok = OracleDialect{}.Type("USER-DEFINED MDSYS.SDO_GEOMETRY", sql.NullInt64{}, sql.NullInt64{}, sql.NullInt64{})
+	assert.False(t, ok)
+
+	_, ok = OracleDialect{}.Type("INTERVAL DAY(2) TO SECOND(6)", sql.NullInt64{}, sql.NullInt64{}, sql.NullInt64{})
+	assert.False(t, ok)
+}
@@ -0,0 +1,167 @@
+// Copyright (C) 2021 CGI France
+//
+// This file is part of LINO.
+//
+// LINO is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// LINO is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with LINO.  If not, see <http://www.gnu.org/licenses/>.
+
+package schema
+
+import (
+	"database/sql"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/cgi-fr/lino/pkg/dataconnector"
+	"github.com/cgi-fr/lino/pkg/schema"
+)
+
+// NewOracleExtractorFactory creates a new oracle extractor factory.
+func NewOracleExtractorFactory() *OracleExtractorFactory {
+	return &OracleExtractorFactory{}
+}
+
+// OracleExtractorFactory exposes methods to create new Oracle extractors.
+type OracleExtractorFactory struct{}
+
+// New return a Oracle extractor
+func (e *OracleExtractorFactory) New(url string, schema string, options dataconnector.Options) schema.Extractor {
+	return NewSQLExtractor(url, schema, options, OracleDialect{})
+}
+
+// OracleDialect reads the structure of the database from the all_* views.
+type OracleDialect struct{}
+
+// oracleNextval matches the default value given by a sequence ("HR"."EMP_SEQ"."NEXTVAL")
+var oracleNextval = regexp.MustCompile(`(?i)^(?:"?[^".]+"?\.)?"?([^".]+)"?\."?nextval"?$`)
+
+func ownerCondition(column string, schema string) string {
+	if schema == "" {
+		return column + " = user"
+	}
+	return fmt.Sprintf("%s = '%s'", column, schema)
+}
+
+func (d OracleDialect) ColumnsSQL(schema string) string {
+	return `SELECT table_name,
+	column_name,
+	CASE WHEN data_type_owner IS NOT NULL THEN 'USER-DEFINED ' || data_type_owner || '.' || data_type ELSE data_type END AS data_type,
+	char_length,
+	data_precision,
+	data_scale,
+	CASE WHEN nullable = 'Y' THEN 1 ELSE 0 END AS nullable,
+	data_default
+FROM all_tab_columns
+WHERE ` + ownerCondition("owner", schema) + `
+AND table_name IN (SELECT table_name FROM all_tables WHERE all_tables.owner = all_tab_columns.owner)
+ORDER BY table_name,
+	column_id`
+}
+
+func (d OracleDialect) KeysSQL(schema string) string {
+	return `SELECT c.table_name,
+	c.constraint_name,
+	c.constraint_type,
+	cc.column_name
+FROM all_constraints c
+JOIN all_cons_columns cc
+ON cc.owner = c.owner
+AND cc.constraint_name = c.constraint_name
+WHERE c.constraint_type IN ('P', 'U')
+AND ` + ownerCondition("c.owner", schema) + `
+ORDER BY c.table_name,
+	c.constraint_name,
+	cc.position`
+}
+
+func (d OracleDialect) ForeignKeysSQL(schema string) string {
+	return `SELECT c.constraint_name,
+	c.table_name,
+	cc.column_name,
+	pc.table_name,
+	pc.column_name
+FROM all_constraints c
+JOIN all_cons_columns cc
+ON cc.owner = c.owner
+AND cc.constraint_name = c.constraint_name
+JOIN all_cons_columns pc
+ON pc.owner = c.r_owner
+AND pc.constraint_name = c.r_constraint_name
+AND pc.position = cc.position
+WHERE c.constraint_type = 'R'
+AND ` + ownerCondition("c.owner", schema) + `
+ORDER BY c.table_name,
+	c.constraint_name,
+	cc.position`
+}
+
+func (d OracleDialect) SequencesSQL(schema string) string {
+	return `SELECT sequence_name,
+	min_value,
+	increment_by
+FROM all_sequences
+WHERE ` + ownerCondition("sequence_owner", schema) + `
+ORDER BY sequence_name`
+}
+
+func (d OracleDialect) Type(dataType string, length sql.NullInt64, precision sql.NullInt64, scale sql.NullInt64) (string, bool) {
+	switch {
+	case dataType == "NUMBER" && !precision.Valid && scale.Valid && scale.Int64 == 0:
+		return "integer", true
+	case dataType == "NUMBER":
+		return numeric(precision, scale), true
+	case dataType == "VARCHAR2" || dataType == "NVARCHAR2":
+		return sized("varchar", length), true
+	case dataType == "CHAR" || dataType == "NCHAR":
+		return sized("char", length), true
+	case dataType == "CLOB" || dataType == "NCLOB" || dataType == "LONG":
+		return "text", true
+	case dataType == "BLOB" || dataType == "RAW" || dataType == "LONG RAW":
+		return "blob", true
+	case dataType == "FLOAT" || dataType == "BINARY_FLOAT":
+		return "float", true
+	case dataType == "BINARY_DOUBLE":
+		return "double", true
+	case dataType == "DATE":
+		// an Oracle date holds the time of the day
+		return "timestamp", true
+	case strings.HasPrefix(dataType, "TIMESTAMP") && strings.HasSuffix(dataType, "TIME ZONE"):
+		return "timestamptz", true
+	case strings.HasPrefix(dataType, "TIMESTAMP"):
+		return "timestamp", true
+	default:
+		// user-defined types, intervals, rowids and the other types have no portable type
+		return dataType, false
+	}
+}
+
+func (d OracleDialect) Default(value string) (string, string) {
+	value = strings.TrimSpace(value)
+
+	if match := oracleNextval.FindStringSubmatch(value); match != nil {
+		return "", match[1]
+	}
+
+	switch strings.ToUpper(value) {
+	case "SYSDATE", "SYSTIMESTAMP", "CURRENT_TIMESTAMP", "LOCALTIMESTAMP":
+		return "CURRENT_TIMESTAMP", ""
+	case "CURRENT_DATE":
+		return "CURRENT_DATE", ""
+	}
+
+	if literal.MatchString(value) {
+		return value, ""
+	}
+	return "", ""
+}
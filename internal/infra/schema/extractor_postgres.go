@@ -0,0 +1,230 @@
+// Copyright (C) 2021 CGI France
+//
+// This file is part of LINO.
+//
+// LINO is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// LINO is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with LINO.  If not, see <http://www.gnu.org/licenses/>.
+
+package schema
+
+import (
+	"database/sql"
+	"fmt"
+	"regexp"
+	"strings"
+
+	// import postgresql connector
+	_ "github.com/lib/pq"
+
+	"github.com/cgi-fr/lino/pkg/dataconnector"
+	"github.com/cgi-fr/lino/pkg/schema"
+)
+
+// NewPostgresExtractorFactory creates a new postgres extractor factory.
+func NewPostgresExtractorFactory() *PostgresExtractorFactory {
+	return &PostgresExtractorFactory{}
+}
+
+// PostgresExtractorFactory exposes methods to create new Postgres extractors.
+type PostgresExtractorFactory struct{}
+
+// New return a Postgres extractor
+func (e *PostgresExtractorFactory) New(url string, schema string, options dataconnector.Options) schema.Extractor {
+	return NewSQLExtractor(url, schema, options, PostgresDialect{})
+}
+
+// PostgresDialect reads the structure of the database from information_schema.
+type PostgresDialect struct{}
+
+// postgresCast matches the cast of a default value ('G'::mpaa_rating)
+var postgresCast = regexp.MustCompile(`^\(?(.*?)\)?::[a-z0-9_ ."]+(\[\])?$`)
+
+// postgresNextval matches the default value given by a sequence
+var postgresNextval = regexp.MustCompile(`^nextval\('(?:[^'.]+\.)?([^']+)'(?:::regclass)?\)$`)
+
+func schemaCondition(column string, schema string) string {
+	if schema == "" {
+		return column + " = current_schema()"
+	}
+	return fmt.Sprintf("%s = '%s'", column, schema)
+}
+
+func (d PostgresDialect) ColumnsSQL(schema string) string {
+	return `SELECT c.table_name,
+	c.column_name,
+	CASE
+		WHEN c.data_type = 'ARRAY' THEN format_type(ty.typelem, NULL) || '[]'
+		WHEN ty.typtype = 'e' THEN 'enum'
+		WHEN c.data_type = 'USER-DEFINED' THEN c.data_type || ' ' || format_type(ty.oid, NULL)
+		ELSE c.data_type
+	END AS data_type,
+	COALESCE(c.character_maximum_length, (SELECT MAX(LENGTH(e.enumlabel)) FROM pg_catalog.pg_enum e WHERE e.enumtypid = ty.oid)),
+	c.numeric_precision,
+	c.numeric_scale,
+	CASE WHEN c.is_nullable = 'YES' THEN 1 ELSE 0 END AS nullable,
+	c.column_default
+FROM information_schema.columns c
+JOIN information_schema.tables t
+ON t.table_schema = c.table_schema
+AND t.table_name = c.table_name
+LEFT JOIN pg_catalog.pg_namespace tn
+ON tn.nspname = c.udt_schema
+LEFT JOIN pg_catalog.pg_type ty
+ON ty.typnamespace = tn.oid
+AND ty.typname = c.udt_name
+WHERE t.table_type = 'BASE TABLE'
+AND ` + schemaCondition("c.table_schema", schema) + `
+ORDER BY c.table_name,
+	c.ordinal_position`
+}
+
+func (d PostgresDialect) KeysSQL(schema string) string {
+	return `SELECT tc.table_name,
+	tc.constraint_name,
+	CASE WHEN tc.constraint_type = 'PRIMARY KEY' THEN 'P' ELSE 'U' END AS constraint_type,
+	kcu.column_name
+FROM information_schema.table_constraints tc
+JOIN information_schema.key_column_usage kcu
+ON kcu.constraint_schema = tc.constraint_schema
+AND kcu.constraint_name = tc.constraint_name
+AND kcu.table_name = tc.table_name
+WHERE tc.constraint_type IN ('PRIMARY KEY', 'UNIQUE')
+AND ` + schemaCondition("tc.table_schema", schema) + `
+ORDER BY tc.table_name,
+	tc.constraint_name,
+	kcu.ordinal_position`
+}
+
+func (d PostgresDialect) ForeignKeysSQL(schema string) string {
+	return `SELECT rc.constraint_name,
+	kcu.table_name,
+	kcu.column_name,
+	ccu.table_name,
+	ccu.column_name
+FROM information_schema.referential_constraints rc
+JOIN information_schema.key_column_usage kcu
+ON kcu.constraint_schema = rc.constraint_schema
+AND kcu.constraint_name = rc.constraint_name
+JOIN information_schema.key_column_usage ccu
+ON ccu.constraint_schema = rc.unique_constraint_schema
+AND ccu.constraint_name = rc.unique_constraint_name
+AND ccu.ordinal_position = kcu.position_in_unique_constraint
+WHERE ` + schemaCondition("kcu.table_schema", schema) + `
+ORDER BY kcu.table_name,
+	rc.constraint_name,
+	kcu.ordinal_position`
+}
+
+func (d PostgresDialect) SequencesSQL(schema string) string {
+	return `SELECT sequence_name,
+	CAST(start_value AS BIGINT),
+	CAST(increment AS BIGINT)
+FROM information_schema.sequences
+WHERE ` + schemaCondition("sequence_schema", schema) + `
+ORDER BY sequence_name`
+}
+
+func (d PostgresDialect) Type(dataType string, length sql.NullInt64, precision sql.NullInt64, scale sql.NullInt64) (string, bool) {
+	switch {
+	case dataType == "enum":
+		// the labels of an enum are stored as strings as long as the longest label
+		return sized("varchar", length), true
+	case strings.HasSuffix(dataType, "[]"):
+		element, ok := postgresType(strings.TrimSuffix(dataType, "[]"), sql.NullInt64{}, precision, scale)
+		return element + "[]", ok
+	case strings.HasPrefix(dataType, "USER-DEFINED"):
+		return dataType, false
+	default:
+		return postgresType(dataType, length, precision, scale)
+	}
+}
+
+// postgresType returns the portable type of a built-in type, false if it has none
+func postgresType(dataType string, length sql.NullInt64, precision sql.NullInt64, scale sql.NullInt64) (string, bool) {
+	switch dataType {
+	case "smallint", "integer", "bigint", "text", "boolean", "date":
+		return dataType, true
+	case "numeric":
+		return numeric(precision, scale), true
+	case "real":
+		return "float", true
+	case "double precision":
+		return "double", true
+	case "character varying":
+		return sized("varchar", length), true
+	case "character", "bpchar":
+		return sized("char", length), true
+	case "timestamp without time zone":
+		return "timestamp", true
+	case "timestamp with time zone":
+		return "timestamptz", true
+	case "bytea":
+		return "blob", true
+	case "uuid":
+		return sized("varchar", sql.NullInt64{Int64: 36, Valid: true}), true
+	case "json", "jsonb", "xml":
+		return "text", true
+	case "inet", "cidr":
+		// the longest text representation of an IPv6 network
+		return sized("varchar", sql.NullInt64{Int64: 43, Valid: true}), true
+	case "macaddr":
+		return sized("varchar", sql.NullInt64{Int64: 17, Valid: true}), true
+	default:
+		return dataType, false
+	}
+}
+
+func (d PostgresDialect) Default(value string) (string, string) {
+	value = strings.TrimSpace(value)
+
+	if match := postgresNextval.FindStringSubmatch(value); match != nil {
+		return "", match[1]
+	}
+	if match := postgresCast.FindStringSubmatch(value); match != nil {
+		value = match[1]
+	}
+
+	switch strings.ToLower(value) {
+	case "now()", "current_timestamp", "localtimestamp":
+		return "CURRENT_TIMESTAMP", ""
+	case "current_date":
+		return "CURRENT_DATE", ""
+	case "true", "false":
+		return strings.ToLower(value), ""
+	}
+
+	if literal.MatchString(value) {
+		return value, ""
+	}
+	return "", ""
+}
+
+// numeric returns the portable type of a decimal number
+func numeric(precision sql.NullInt64, scale sql.NullInt64) string {
+	switch {
+	case !precision.Valid:
+		return "numeric"
+	case !scale.Valid || scale.Int64 == 0:
+		return fmt.Sprintf("numeric(%d)", precision.Int64)
+	default:
+		return fmt.Sprintf("numeric(%d,%d)", precision.Int64, scale.Int64)
+	}
+}
+
+// sized returns the portable type of a string with its length
+func sized(name string, length sql.NullInt64) string {
+	if !length.Valid || length.Int64 == 0 {
+		return name
+	}
+	return fmt.Sprintf("%s(%d)", name, length.Int64)
+}
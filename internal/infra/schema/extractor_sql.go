@@ -0,0 +1,270 @@
+// Copyright (C) 2021 CGI France
+//
+// This file is part of LINO.
+//
+// LINO is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// LINO is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with LINO.  If not, see <http://www.gnu.org/licenses/>.
+
+package schema
+
+import (
+	"database/sql"
+	"fmt"
+	"regexp"
+
+	"github.com/cgi-fr/lino/internal/infra/connection"
+	"github.com/cgi-fr/lino/pkg/dataconnector"
+	"github.com/cgi-fr/lino/pkg/schema"
+)
+
+// Dialect provides the queries reading the structure of a database and the mapping of its types.
+type Dialect interface {
+	// ColumnsSQL returns the table name, column name, data type, length, precision, scale, nullable (1 or 0) and default value of each column
+	ColumnsSQL(schema string) string
+	// KeysSQL returns the table name, constraint name, constraint type (P or U) and column name of each column of the primary and unique keys
+	KeysSQL(schema string) string
+	// ForeignKeysSQL returns the constraint name, child table, child column, parent table and parent column of each column of the foreign keys
+	ForeignKeysSQL(schema string) string
+	// SequencesSQL returns the name, start value and increment of each sequence
+	SequencesSQL(schema string) string
+	// Type returns the portable type of a column, false if the type can't be mapped to a portable type
+	Type(dataType string, length sql.NullInt64, precision sql.NullInt64, scale sql.NullInt64) (string, bool)
+	// Default returns the portable default value of a column or the sequence giving its value
+	Default(value string) (string, string)
+}
+
+// literal matches a number or a string literal
+var literal = regexp.MustCompile(`^(-?[0-9]+(\.[0-9]+)?|'([^']|'')*')$`)
+
+// SQLExtractor provides schema extraction logic from SQL database.
+type SQLExtractor struct {
+	url     string
+	options dataconnector.Options
+	schema  string
+	dialect Dialect
+}
+
+// NewSQLExtractor creates a new SQL extractor.
+func NewSQLExtractor(url string, schema string, options dataconnector.Options, dialect Dialect) *SQLExtractor {
+	return &SQLExtractor{
+		url:     url,
+		options: options,
+		schema:  schema,
+		dialect: dialect,
+	}
+}
+
+// Extract tables, columns, keys and sequences of the dataconnector schema.
+func (e *SQLExtractor) Extract() (schema.Schema, *schema.Error) {
+	result := schema.Schema{Tables: []schema.Table{}, Sequences: []schema.Sequence{}}
+
+	db, err := connection.Open(e.url, e.options)
+	if err != nil {
+		return result, &schema.Error{Description: err.Error()}
+	}
+	defer db.Close()
+
+	err = db.Ping()
+	if err != nil {
+		return result, &schema.Error{Description: err.Error()}
+	}
+
+	index := map[string]int{}
+
+	if err := e.extractColumns(db, &result, index); err != nil {
+		return result, err
+	}
+	if err := e.extractKeys(db, &result, index); err != nil {
+		return result, err
+	}
+	if err := e.extractForeignKeys(db, &result, index); err != nil {
+		return result, err
+	}
+	if err := e.extractSequences(db, &result); err != nil {
+		return result, err
+	}
+
+	return result, nil
+}
+
+func (e *SQLExtractor) extractColumns(db *sql.DB, result *schema.Schema, index map[string]int) *schema.Error {
+	rows, err := db.Query(e.dialect.ColumnsSQL(e.schema))
+	if err != nil {
+		return &schema.Error{Description: err.Error()}
+	}
+	defer rows.Close()
+
+	var (
+		tableName    string
+		columnName   string
+		dataType     string
+		length       sql.NullInt64
+		precision    sql.NullInt64
+		scale        sql.NullInt64
+		nullable     int
+		defaultValue sql.NullString
+	)
+
+	for rows.Next() {
+		err := rows.Scan(&tableName, &columnName, &dataType, &length, &precision, &scale, &nullable, &defaultValue)
+		if err != nil {
+			return &schema.Error{Description: err.Error()}
+		}
+
+		idx, ok := index[tableName]
+		if !ok {
+			idx = len(result.Tables)
+			index[tableName] = idx
+			result.Tables = append(result.Tables, schema.Table{Name: tableName, Columns: []schema.Column{}, ForeignKeys: []schema.ForeignKey{}})
+		}
+
+		portable, ok := e.dialect.Type(dataType, length, precision, scale)
+		if !ok {
+			return &schema.Error{Description: fmt.Sprintf("column %s.%s has the type %s that can't be mapped to a portable type", tableName, columnName, dataType)}
+		}
+
+		column := schema.Column{
+			Name:     columnName,
+			Type:     portable,
+			Nullable: nullable == 1,
+		}
+		if defaultValue.Valid {
+			column.Default, column.Sequence = e.dialect.Default(defaultValue.String)
+		}
+		result.Tables[idx].Columns = append(result.Tables[idx].Columns, column)
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return &schema.Error{Description: err.Error()}
+	}
+	return nil
+}
+
+func (e *SQLExtractor) extractKeys(db *sql.DB, result *schema.Schema, index map[string]int) *schema.Error {
+	rows, err := db.Query(e.dialect.KeysSQL(e.schema))
+	if err != nil {
+		return &schema.Error{Description: err.Error()}
+	}
+	defer rows.Close()
+
+	var (
+		tableName      string
+		constraintName string
+		constraintType string
+		columnName     string
+		previous       string
+	)
+
+	for rows.Next() {
+		err := rows.Scan(&tableName, &constraintName, &constraintType, &columnName)
+		if err != nil {
+			return &schema.Error{Description: err.Error()}
+		}
+
+		idx, ok := index[tableName]
+		if !ok {
+			continue
+		}
+		t := &result.Tables[idx]
+
+		switch {
+		case constraintType == "P":
+			t.PrimaryKey = append(t.PrimaryKey, columnName)
+		case tableName+"."+constraintName == previous:
+			t.UniqueKeys[len(t.UniqueKeys)-1] = append(t.UniqueKeys[len(t.UniqueKeys)-1], columnName)
+		default:
+			t.UniqueKeys = append(t.UniqueKeys, []string{columnName})
+		}
+		previous = tableName + "." + constraintName
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return &schema.Error{Description: err.Error()}
+	}
+	return nil
+}
+
+func (e *SQLExtractor) extractForeignKeys(db *sql.DB, result *schema.Schema, index map[string]int) *schema.Error {
+	rows, err := db.Query(e.dialect.ForeignKeysSQL(e.schema))
+	if err != nil {
+		return &schema.Error{Description: err.Error()}
+	}
+	defer rows.Close()
+
+	var (
+		constraintName string
+		childTable     string
+		childColumn    string
+		parentTable    string
+		parentColumn   string
+		previous       string
+	)
+
+	for rows.Next() {
+		err := rows.Scan(&constraintName, &childTable, &childColumn, &parentTable, &parentColumn)
+		if err != nil {
+			return &schema.Error{Description: err.Error()}
+		}
+
+		idx, ok := index[childTable]
+		if !ok {
+			continue
+		}
+		t := &result.Tables[idx]
+
+		if childTable+"."+constraintName == previous {
+			fk := &t.ForeignKeys[len(t.ForeignKeys)-1]
+			fk.Columns = append(fk.Columns, childColumn)
+			fk.ReferencedColumns = append(fk.ReferencedColumns, parentColumn)
+		} else {
+			t.ForeignKeys = append(t.ForeignKeys, schema.ForeignKey{
+				Name:              constraintName,
+				Columns:           []string{childColumn},
+				References:        parentTable,
+				ReferencedColumns: []string{parentColumn},
+			})
+		}
+		previous = childTable + "." + constraintName
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return &schema.Error{Description: err.Error()}
+	}
+	return nil
+}
+
+func (e *SQLExtractor) extractSequences(db *sql.DB, result *schema.Schema) *schema.Error {
+	rows, err := db.Query(e.dialect.SequencesSQL(e.schema))
+	if err != nil {
+		return &schema.Error{Description: err.Error()}
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		sequence := schema.Sequence{}
+		err := rows.Scan(&sequence.Name, &sequence.Start, &sequence.Increment)
+		if err != nil {
+			return &schema.Error{Description: err.Error()}
+		}
+		result.Sequences = append(result.Sequences, sequence)
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return &schema.Error{Description: err.Error()}
+	}
+	return nil
+}
@@ -0,0 +1,203 @@
+// Copyright (C) 2021 CGI France
+//
+// This file is part of LINO.
+//
+// LINO is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// LINO is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with LINO.  If not, see <http://www.gnu.org/licenses/>.
+
+package schema
+
+import (
+	"io/ioutil"
+
+	"github.com/cgi-fr/lino/pkg/schema"
+	"gopkg.in/yaml.v3"
+)
+
+// Version of the YAML strcuture.
+const Version string = "v1"
+
+// YAMLStructure of the file.
+type YAMLStructure struct {
+	Version   string         `yaml:"version"`
+	Tables    []YAMLTable    `yaml:"tables,omitempty"`
+	Sequences []YAMLSequence `yaml:"sequences,omitempty"`
+}
+
+// YAMLTable defines how to store a table in YAML format.
+type YAMLTable struct {
+	Name        string           `yaml:"name"`
+	Columns     []YAMLColumn     `yaml:"columns"`
+	PrimaryKey  []string         `yaml:"primaryKey,omitempty"`
+	UniqueKeys  [][]string       `yaml:"uniqueKeys,omitempty"`
+	ForeignKeys []YAMLForeignKey `yaml:"foreignKeys,omitempty"`
+}
+
+// YAMLColumn defines how to store a column in YAML format.
+type YAMLColumn struct {
+	Name     string `yaml:"name"`
+	Type     string `yaml:"type"`
+	Nullable bool   `yaml:"nullable,omitempty"`
+	Default  string `yaml:"default,omitempty"`
+	Sequence string `yaml:"sequence,omitempty"`
+}
+
+// YAMLForeignKey defines how to store a foreign key in YAML format.
+type YAMLForeignKey struct {
+	Name              string   `yaml:"name"`
+	Columns           []string `yaml:"columns"`
+	References        string   `yaml:"references"`
+	ReferencedColumns []string `yaml:"referencedColumns"`
+}
+
+// YAMLSequence defines how to store a sequence in YAML format.
+type YAMLSequence struct {
+	Name      string `yaml:"name"`
+	Start     int64  `yaml:"start"`
+	Increment int64  `yaml:"increment"`
+}
+
+// YAMLStorage provides storage in a local YAML file
+type YAMLStorage struct{}
+
+// NewYAMLStorage create a new YAML storage
+func NewYAMLStorage() *YAMLStorage {
+	return &YAMLStorage{}
+}
+
+// Read the schema stored in the YAML file
+func (s YAMLStorage) Read() (schema.Schema, *schema.Error) {
+	list, err := readFile()
+	if err != nil {
+		return schema.Schema{}, err
+	}
+
+	result := schema.Schema{Tables: []schema.Table{}, Sequences: []schema.Sequence{}}
+
+	for _, yt := range list.Tables {
+		t := schema.Table{
+			Name:        yt.Name,
+			PrimaryKey:  yt.PrimaryKey,
+			UniqueKeys:  yt.UniqueKeys,
+			Columns:     []schema.Column{},
+			ForeignKeys: []schema.ForeignKey{},
+		}
+		for _, yc := range yt.Columns {
+			t.Columns = append(t.Columns, schema.Column{
+				Name:     yc.Name,
+				Type:     yc.Type,
+				Nullable: yc.Nullable,
+				Default:  yc.Default,
+				Sequence: yc.Sequence,
+			})
+		}
+		for _, yf := range yt.ForeignKeys {
+			t.ForeignKeys = append(t.ForeignKeys, schema.ForeignKey{
+				Name:              yf.Name,
+				Columns:           yf.Columns,
+				References:        yf.References,
+				ReferencedColumns: yf.ReferencedColumns,
+			})
+		}
+		result.Tables = append(result.Tables, t)
+	}
+
+	for _, ys := range list.Sequences {
+		result.Sequences = append(result.Sequences, schema.Sequence{
+			Name:      ys.Name,
+			Start:     ys.Start,
+			Increment: ys.Increment,
+		})
+	}
+
+	return result, nil
+}
+
+// Write the schema in the YAML file
+func (s YAMLStorage) Write(sch schema.Schema) *schema.Error {
+	list := YAMLStructure{
+		Version: Version,
+	}
+
+	for _, t := range sch.Tables {
+		yt := YAMLTable{
+			Name:       t.Name,
+			PrimaryKey: t.PrimaryKey,
+			UniqueKeys: t.UniqueKeys,
+		}
+		for _, c := range t.Columns {
+			yt.Columns = append(yt.Columns, YAMLColumn{
+				Name:     c.Name,
+				Type:     c.Type,
+				Nullable: c.Nullable,
+				Default:  c.Default,
+				Sequence: c.Sequence,
+			})
+		}
+		for _, f := range t.ForeignKeys {
+			yt.ForeignKeys = append(yt.ForeignKeys, YAMLForeignKey{
+				Name:              f.Name,
+				Columns:           f.Columns,
+				References:        f.References,
+				ReferencedColumns: f.ReferencedColumns,
+			})
+		}
+		list.Tables = append(list.Tables, yt)
+	}
+
+	for _, seq := range sch.Sequences {
+		list.Sequences = append(list.Sequences, YAMLSequence{
+			Name:      seq.Name,
+			Start:     seq.Start,
+			Increment: seq.Increment,
+		})
+	}
+
+	return writeFile(&list)
+}
+
+func readFile() (*YAMLStructure, *schema.Error) {
+	list := &YAMLStructure{
+		Version: Version,
+	}
+
+	dat, err := ioutil.ReadFile("schema.yaml")
+	if err != nil {
+		return nil, &schema.Error{Description: err.Error()}
+	}
+
+	err = yaml.Unmarshal(dat, list)
+	if err != nil {
+		return nil, &schema.Error{Description: err.Error()}
+	}
+
+	if list.Version != Version {
+		return nil, &schema.Error{Description: "invalid version in ./schema.yaml (" + list.Version + ")"}
+	}
+
+	return list, nil
+}
+
+func writeFile(list *YAMLStructure) *schema.Error {
+	out, err := yaml.Marshal(list)
+	if err != nil {
+		return &schema.Error{Description: err.Error()}
+	}
+
+	err = ioutil.WriteFile("schema.yaml", out, 0600)
+	if err != nil {
+		return &schema.Error{Description: err.Error()}
+	}
+
+	return nil
+}
@@ -0,0 +1,77 @@
+// Copyright (C) 2021 CGI France
+//
+// This file is part of LINO.
+//
+// LINO is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// LINO is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with LINO.  If not, see <http://www.gnu.org/licenses/>.
+
+// Package sqlfilter builds the SQL conditions restricting the tables read by the extractors to a schema list and include/exclude patterns.
+package sqlfilter
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Regexp builds the condition matching column with a quoted regular expression in a SQL dialect.
+type Regexp func(column string, pattern string) string
+
+// PostgresRegexp matches a regular expression with the ~ operator.
+func PostgresRegexp(column string, pattern string) string {
+	return fmt.Sprintf("%s ~ %s", column, pattern)
+}
+
+// OracleRegexp matches a regular expression with REGEXP_LIKE.
+func OracleRegexp(column string, pattern string) string {
+	return fmt.Sprintf("REGEXP_LIKE(%s, %s)", column, pattern)
+}
+
+// In returns the condition restricting column to the values.
+func In(column string, values []string) string {
+	quoted := []string{}
+	for _, value := range values {
+		quoted = append(quoted, Quote(value))
+	}
+	return fmt.Sprintf("%s IN (%s)", column, strings.Join(quoted, ", "))
+}
+
+// Patterns returns the conditions matching column with the include and exclude patterns.
+func Patterns(column string, include []string, exclude []string, regexp Regexp) string {
+	SQL := ""
+	if len(include) > 0 {
+		SQL += fmt.Sprintf("\nAND (%s)", Match(column, include, regexp))
+	}
+	if len(exclude) > 0 {
+		SQL += fmt.Sprintf("\nAND NOT (%s)", Match(column, exclude, regexp))
+	}
+	return SQL
+}
+
+// Match returns the condition matching column with one of the patterns, a pattern between slashes is a regular expression, else a glob.
+func Match(column string, patterns []string, regexp Regexp) string {
+	conditions := []string{}
+	for _, pattern := range patterns {
+		if len(pattern) > 1 && strings.HasPrefix(pattern, "/") && strings.HasSuffix(pattern, "/") {
+			conditions = append(conditions, regexp(column, Quote(pattern[1:len(pattern)-1])))
+			continue
+		}
+		like := strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`, "*", "%", "?", "_").Replace(pattern)
+		conditions = append(conditions, fmt.Sprintf(`%s LIKE %s ESCAPE '\'`, column, Quote(like)))
+	}
+	return strings.Join(conditions, " OR ")
+}
+
+// Quote returns value as a SQL string literal.
+func Quote(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+}
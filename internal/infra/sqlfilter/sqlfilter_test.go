@@ -0,0 +1,43 @@
+// Copyright (C) 2021 CGI France
+//
+// This file is part of LINO.
+//
+// LINO is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// LINO is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with LINO.  If not, see <http://www.gnu.org/licenses/>.
+
+package sqlfilter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPatterns(t *testing.T) {
+	include := []string{"order*", "/^stock_[0-9]+$/"}
+	exclude := []string{"*_tmp", "o'hara"}
+
+	assert.Equal(t,
+		"\nAND (table_name LIKE 'order%' ESCAPE '\\' OR table_name ~ '^stock_[0-9]+$')"+
+			"\nAND NOT (table_name LIKE '%\\_tmp' ESCAPE '\\' OR table_name LIKE 'o''hara' ESCAPE '\\')",
+		Patterns("table_name", include, exclude, PostgresRegexp))
+	assert.Equal(t, "", Patterns("table_name", nil, nil, PostgresRegexp))
+}
+
+func TestIn(t *testing.T) {
+	assert.Equal(t, "owner IN ('SALES', 'STOCK')", In("owner", []string{"SALES", "STOCK"}))
+}
+
+func TestOracleRegexp(t *testing.T) {
+	assert.Equal(t, "\nAND (REGEXP_LIKE(table_name, '^stock_[0-9]+$'))", Patterns("table_name", []string{"/^stock_[0-9]+$/"}, nil, OracleRegexp))
+}
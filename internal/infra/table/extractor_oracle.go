@@ -6,6 +6,8 @@ import (
 	// import Oracle connector
 	_ "github.com/godror/godror"
 
+	"github.com/cgi-fr/lino/internal/infra/sqlfilter"
+	"github.com/cgi-fr/lino/pkg/dataconnector"
 	"github.com/cgi-fr/lino/pkg/table"
 )
 
@@ -18,13 +20,13 @@ func NewOracleExtractorFactory() *OracleExtractorFactory {
 type OracleExtractorFactory struct{}
 
 // New return a Oracle extractor
-func (e *OracleExtractorFactory) New(url string, schema string) table.Extractor {
-	return NewSQLExtractor(url, schema, OracleDialect{})
+func (e *OracleExtractorFactory) New(url string, schema string, options dataconnector.Options) table.Extractor {
+	return NewSQLExtractor(url, schema, options, OracleDialect{})
 }
 
 type OracleDialect struct{}
 
-func (d OracleDialect) SQL(schema string) string {
+func (d OracleDialect) SQL(filter table.Filter) string {
 	SQL := `
 SELECT
 	all_cons_columns.owner as schema_name,
@@ -37,11 +39,12 @@ SELECT
 	and all_constraints.owner = all_cons_columns.owner
 	`
 
-	if schema == "" {
+	if len(filter.Schemas) == 0 {
 		SQL += "AND all_constraints.owner =  user"
 	} else {
-		SQL += fmt.Sprintf("AND all_constraints.owner = '%s'", schema)
+		SQL += "AND " + sqlfilter.In("all_constraints.owner", filter.Schemas)
 	}
+	SQL += sqlfilter.Patterns("all_cons_columns.table_name", filter.Include, filter.Exclude, sqlfilter.OracleRegexp)
 
 	SQL += `
  group by all_cons_columns.table_name, all_cons_columns.owner
@@ -49,3 +52,85 @@ SELECT
 
 	return SQL
 }
+
+func (d OracleDialect) UniqueSQL(filter table.Filter) string {
+	SQL := `
+SELECT
+	all_ind_columns.table_owner as schema_name,
+	all_ind_columns.table_name as table_name,
+	LISTAGG(all_ind_columns.column_name, ',') WITHIN GROUP (order by all_ind_columns.column_position) as columns
+ FROM all_indexes, all_ind_columns
+ where
+	all_indexes.uniqueness = 'UNIQUE'
+	and all_indexes.index_name = all_ind_columns.index_name
+	and all_indexes.owner = all_ind_columns.index_owner
+	and not exists (
+		select 1 from all_constraints
+		where all_constraints.constraint_type = 'P'
+		and all_constraints.owner = all_indexes.table_owner
+		and all_constraints.index_name = all_indexes.index_name
+	)
+	`
+
+	if len(filter.Schemas) == 0 {
+		SQL += "AND all_indexes.table_owner = user"
+	} else {
+		SQL += "AND " + sqlfilter.In("all_indexes.table_owner", filter.Schemas)
+	}
+	SQL += sqlfilter.Patterns("all_indexes.table_name", filter.Include, filter.Exclude, sqlfilter.OracleRegexp)
+
+	SQL += `
+ group by all_ind_columns.table_owner, all_ind_columns.table_name, all_ind_columns.index_name
+	`
+
+	return SQL
+}
+
+func (d OracleDialect) TablesSQL(filter table.Filter) string {
+	SQL := `
+SELECT
+	owner as schema_name,
+	table_name
+ FROM all_tables
+ where
+	`
+
+	if len(filter.Schemas) == 0 {
+		SQL += "owner = user"
+	} else {
+		SQL += sqlfilter.In("owner", filter.Schemas)
+	}
+	SQL += sqlfilter.Patterns("table_name", filter.Include, filter.Exclude, sqlfilter.OracleRegexp)
+
+	SQL += `
+ order by owner, table_name
+	`
+
+	return SQL
+}
+
+func (d OracleDialect) ColumnsSQL(schema string) string {
+	SQL := `
+SELECT
+	owner as schema_name,
+	table_name,
+	column_name,
+	data_type,
+	CASE WHEN nullable = 'Y' THEN 1 ELSE 0 END as nullable,
+	CASE WHEN default_length > 0 THEN 1 ELSE 0 END as has_default
+ FROM all_tab_columns
+ where
+	`
+
+	if schema == "" {
+		SQL += "owner = user"
+	} else {
+		SQL += fmt.Sprintf("owner = '%s'", schema)
+	}
+
+	SQL += `
+ order by owner, table_name, column_id
+	`
+
+	return SQL
+}
@@ -23,6 +23,8 @@ import (
 	// import postgresql connector
 	_ "github.com/lib/pq"
 
+	"github.com/cgi-fr/lino/internal/infra/sqlfilter"
+	"github.com/cgi-fr/lino/pkg/dataconnector"
 	"github.com/cgi-fr/lino/pkg/table"
 )
 
@@ -35,14 +37,14 @@ func NewPostgresExtractorFactory() *PostgresExtractorFactory {
 type PostgresExtractorFactory struct{}
 
 // New return a Postgres extractor
-func (e *PostgresExtractorFactory) New(url string, schema string) table.Extractor {
-	return NewSQLExtractor(url, schema, PostgresDialect{})
+func (e *PostgresExtractorFactory) New(url string, schema string, options dataconnector.Options) table.Extractor {
+	return NewSQLExtractor(url, schema, options, PostgresDialect{})
 }
 
 type PostgresDialect struct {
 }
 
-func (d PostgresDialect) SQL(schema string) string {
+func (d PostgresDialect) SQL(filter table.Filter) string {
 	SQL := `SELECT kcu.table_schema,
 	kcu.table_name,
 	string_agg(kcu.column_name,',') AS key_columns
@@ -54,9 +56,10 @@ AND kcu.constraint_name = tco.constraint_name
 WHERE tco.constraint_type = 'PRIMARY KEY'
 `
 
-	if schema != "" {
-		SQL += fmt.Sprintf("AND kcu.table_schema = '%s'", schema)
+	if len(filter.Schemas) > 0 {
+		SQL += "AND " + sqlfilter.In("kcu.table_schema", filter.Schemas)
 	}
+	SQL += sqlfilter.Patterns("kcu.table_name", filter.Include, filter.Exclude, sqlfilter.PostgresRegexp)
 
 	SQL += `
 GROUP BY tco.constraint_name,
@@ -67,3 +70,81 @@ ORDER BY kcu.table_schema,
 
 	return SQL
 }
+
+func (d PostgresDialect) UniqueSQL(filter table.Filter) string {
+	SQL := `SELECT n.nspname AS table_schema,
+	c.relname AS table_name,
+	string_agg(a.attname, ',' ORDER BY k.ord) AS key_columns
+FROM pg_index i
+JOIN pg_class c ON c.oid = i.indrelid
+JOIN pg_namespace n ON n.oid = c.relnamespace
+JOIN LATERAL unnest(i.indkey) WITH ORDINALITY AS k(attnum, ord) ON true
+JOIN pg_attribute a ON a.attrelid = c.oid AND a.attnum = k.attnum
+WHERE i.indisunique
+AND NOT i.indisprimary
+AND i.indpred IS NULL
+AND i.indexprs IS NULL
+`
+
+	if len(filter.Schemas) > 0 {
+		SQL += "AND " + sqlfilter.In("n.nspname", filter.Schemas)
+	} else {
+		SQL += "AND n.nspname NOT IN ('pg_catalog', 'information_schema')"
+	}
+	SQL += sqlfilter.Patterns("c.relname", filter.Include, filter.Exclude, sqlfilter.PostgresRegexp)
+
+	SQL += `
+GROUP BY n.nspname,
+	c.relname,
+	i.indexrelid
+ORDER BY n.nspname,
+	c.relname,
+	i.indexrelid`
+
+	return SQL
+}
+
+func (d PostgresDialect) TablesSQL(filter table.Filter) string {
+	SQL := `SELECT table_schema,
+	table_name
+FROM information_schema.tables
+WHERE table_type = 'BASE TABLE'
+`
+
+	if len(filter.Schemas) > 0 {
+		SQL += "AND " + sqlfilter.In("table_schema", filter.Schemas)
+	} else {
+		SQL += "AND table_schema NOT IN ('pg_catalog', 'information_schema')"
+	}
+	SQL += sqlfilter.Patterns("table_name", filter.Include, filter.Exclude, sqlfilter.PostgresRegexp)
+
+	SQL += `
+ORDER BY table_schema,
+	table_name`
+
+	return SQL
+}
+
+func (d PostgresDialect) ColumnsSQL(schema string) string {
+	SQL := `SELECT table_schema,
+	table_name,
+	column_name,
+	data_type,
+	CASE WHEN is_nullable = 'YES' THEN 1 ELSE 0 END AS nullable,
+	CASE WHEN column_default IS NULL THEN 0 ELSE 1 END AS has_default
+FROM information_schema.columns
+`
+
+	if schema != "" {
+		SQL += fmt.Sprintf("WHERE table_schema = '%s'", schema)
+	} else {
+		SQL += "WHERE table_schema NOT IN ('pg_catalog', 'information_schema')"
+	}
+
+	SQL += `
+ORDER BY table_schema,
+	table_name,
+	ordinal_position`
+
+	return SQL
+}
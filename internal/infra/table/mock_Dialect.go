@@ -2,15 +2,18 @@
 
 package table
 
-import mock "github.com/stretchr/testify/mock"
+import (
+	table "github.com/cgi-fr/lino/pkg/table"
+	mock "github.com/stretchr/testify/mock"
+)
 
 // MockDialect is an autogenerated mock type for the Dialect type
 type MockDialect struct {
 	mock.Mock
 }
 
-// SQL provides a mock function with given fields: schema
-func (_m *MockDialect) SQL(schema string) string {
+// ColumnsSQL provides a mock function with given fields: schema
+func (_m *MockDialect) ColumnsSQL(schema string) string {
 	ret := _m.Called(schema)
 
 	var r0 string
@@ -22,3 +25,45 @@ func (_m *MockDialect) SQL(schema string) string {
 
 	return r0
 }
+
+// SQL provides a mock function with given fields: filter
+func (_m *MockDialect) SQL(filter table.Filter) string {
+	ret := _m.Called(filter)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(table.Filter) string); ok {
+		r0 = rf(filter)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
+
+// TablesSQL provides a mock function with given fields: filter
+func (_m *MockDialect) TablesSQL(filter table.Filter) string {
+	ret := _m.Called(filter)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(table.Filter) string); ok {
+		r0 = rf(filter)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
+
+// UniqueSQL provides a mock function with given fields: filter
+func (_m *MockDialect) UniqueSQL(filter table.Filter) string {
+	ret := _m.Called(filter)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(table.Filter) string); ok {
+		r0 = rf(filter)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
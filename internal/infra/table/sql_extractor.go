@@ -18,35 +18,45 @@
 package table
 
 import (
+	"database/sql"
 	"strings"
 
+	"github.com/cgi-fr/lino/internal/infra/connection"
+	"github.com/cgi-fr/lino/pkg/dataconnector"
 	"github.com/cgi-fr/lino/pkg/table"
-	"github.com/xo/dburl"
 )
 
 // SQLExtractor provides table extraction logic from SQL database.
 type SQLExtractor struct {
 	url     string
+	options dataconnector.Options
 	schema  string
 	dialect Dialect
 }
 
 type Dialect interface {
-	SQL(schema string) string
+	// SQL returns the schema, table name and comma separated columns of each primary key of the tables matching filter
+	SQL(filter table.Filter) string
+	// UniqueSQL returns the schema, table name and comma separated columns of each unique constraint or index that is not a primary key
+	UniqueSQL(filter table.Filter) string
+	// TablesSQL returns the schema and name of each table matching filter
+	TablesSQL(filter table.Filter) string
+	ColumnsSQL(schema string) string
 }
 
 // NewSQLExtractor creates a new SQL extractor.
-func NewSQLExtractor(url string, schema string, dialect Dialect) *SQLExtractor {
+func NewSQLExtractor(url string, schema string, options dataconnector.Options, dialect Dialect) *SQLExtractor {
 	return &SQLExtractor{
 		url:     url,
+		options: options,
 		schema:  schema,
 		dialect: dialect,
 	}
 }
 
-// Extract tables from the database.
-func (e *SQLExtractor) Extract() ([]table.Table, *table.Error) {
-	db, err := dburl.Open(e.url)
+// Extract tables matching filter from the database, table names are prefixed by their schema if filter has schemas.
+func (e *SQLExtractor) Extract(filter table.Filter) ([]table.Table, *table.Error) {
+	db, err := connection.Open(e.url, e.options)
 	if err != nil {
 		return nil, &table.Error{Description: err.Error()}
 	}
@@ -57,14 +67,79 @@ func (e *SQLExtractor) Extract() ([]table.Table, *table.Error) {
 		return nil, &table.Error{Description: err.Error()}
 	}
 
-	SQL := e.dialect.SQL(e.schema)
+	return e.extract(db, filter)
+}
+
+// extract tables matching filter from the opened database
+func (e *SQLExtractor) extract(db *sql.DB, filter table.Filter) ([]table.Table, *table.Error) {
+	qualify := len(filter.Schemas) > 0
+	if !qualify && e.schema != "" {
+		filter.Schemas = []string{e.schema}
+	}
 
-	rows, err := db.Query(SQL)
+	tables := []table.Table{}
+	index := map[string]int{}
+	tableIndex := func(tableSchema string, tableName string) int {
+		if qualify {
+			tableName = tableSchema + "." + tableName
+		}
+		idx, ok := index[tableName]
+		if !ok {
+			idx = len(tables)
+			index[tableName] = idx
+			tables = append(tables, table.Table{Name: tableName, Keys: []string{}})
+		}
+		return idx
+	}
+
+	err2 := queryKeys(db, e.dialect.SQL(filter), func(tableSchema string, tableName string, keys []string) {
+		tables[tableIndex(tableSchema, tableName)].Keys = keys
+	})
+	if err2 != nil {
+		return nil, err2
+	}
+
+	err2 = queryKeys(db, e.dialect.UniqueSQL(filter), func(tableSchema string, tableName string, keys []string) {
+		idx := tableIndex(tableSchema, tableName)
+		tables[idx].AlternateKeys = append(tables[idx].AlternateKeys, keys)
+	})
+	if err2 != nil {
+		return nil, err2
+	}
+
+	rows, err := db.Query(e.dialect.TablesSQL(filter))
 	if err != nil {
 		return nil, &table.Error{Description: err.Error()}
 	}
+	defer rows.Close()
 
-	tables := []table.Table{}
+	var (
+		tableSchema string
+		tableName   string
+	)
+
+	for rows.Next() {
+		err := rows.Scan(&tableSchema, &tableName)
+		if err != nil {
+			return nil, &table.Error{Description: err.Error()}
+		}
+		tableIndex(tableSchema, tableName)
+	}
+	err = rows.Err()
+	if err != nil {
+		return nil, &table.Error{Description: err.Error()}
+	}
+
+	return tables, nil
+}
+
+// queryKeys runs a query returning the schema, table name and comma separated columns of keys and calls add for each key.
+func queryKeys(db *sql.DB, query string, add func(tableSchema string, tableName string, keys []string)) *table.Error {
+	rows, err := db.Query(query)
+	if err != nil {
+		return &table.Error{Description: err.Error()}
+	}
+	defer rows.Close()
 
 	var (
 		tableSchema string
@@ -75,15 +150,74 @@ func (e *SQLExtractor) Extract() ([]table.Table, *table.Error) {
 	for rows.Next() {
 		err := rows.Scan(&tableSchema, &tableName, &keyColumns)
 		if err != nil {
-			return nil, &table.Error{Description: err.Error()}
+			return &table.Error{Description: err.Error()}
 		}
+		add(tableSchema, tableName, strings.Split(keyColumns, ","))
+	}
+	err = rows.Err()
+	if err != nil {
+		return &table.Error{Description: err.Error()}
+	}
+
+	return nil
+}
+
+// ExtractColumns extracts columns metadata of all tables from the database, table names are prefixed by their schema.
+func (e *SQLExtractor) ExtractColumns() ([]table.Table, *table.Error) {
+	db, err := connection.Open(e.url, e.options)
+	if err != nil {
+		return nil, &table.Error{Description: err.Error()}
+	}
+	defer db.Close()
+
+	err = db.Ping()
+	if err != nil {
+		return nil, &table.Error{Description: err.Error()}
+	}
+
+	return e.extractColumns(db)
+}
 
-		table := table.Table{
+// extractColumns extracts columns metadata of all tables from the opened database, grouped by schema and table name
+func (e *SQLExtractor) extractColumns(db *sql.DB) ([]table.Table, *table.Error) {
+	rows, err := db.Query(e.dialect.ColumnsSQL(e.schema))
+	if err != nil {
+		return nil, &table.Error{Description: err.Error()}
+	}
+	defer rows.Close()
 
-			Name: tableName,
-			Keys: strings.Split(keyColumns, ","),
+	tables := []table.Table{}
+	index := map[string]int{}
+
+	var (
+		tableSchema string
+		tableName   string
+		columnName  string
+		columnType  string
+		nullable    int
+		hasDefault  int
+	)
+
+	for rows.Next() {
+		err := rows.Scan(&tableSchema, &tableName, &columnName, &columnType, &nullable, &hasDefault)
+		if err != nil {
+			return nil, &table.Error{Description: err.Error()}
+		}
+
+		name := tableSchema + "." + tableName
+		idx, ok := index[name]
+		if !ok {
+			idx = len(tables)
+			index[name] = idx
+			tables = append(tables, table.Table{Name: name, Keys: []string{}})
 		}
-		tables = append(tables, table)
+
+		tables[idx].Columns = append(tables[idx].Columns, table.Column{
+			Name:       columnName,
+			Type:       columnType,
+			Nullable:   nullable == 1,
+			HasDefault: hasDefault == 1,
+		})
 	}
 	err = rows.Err()
 	if err != nil {
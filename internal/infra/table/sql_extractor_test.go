@@ -0,0 +1,147 @@
+// Copyright (C) 2021 CGI France
+//
+// This file is part of LINO.
+//
+// LINO is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// LINO is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with LINO.  If not, see <http://www.gnu.org/licenses/>.
+
+package table
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/cgi-fr/lino/pkg/dataconnector"
+	"github.com/cgi-fr/lino/pkg/table"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// tablesDriver returns the rows of results for each query
+type tablesDriver struct {
+	results map[string][][]driver.Value
+}
+
+func (d *tablesDriver) Open(name string) (driver.Conn, error) { return &tablesConn{d}, nil }
+
+type tablesConn struct{ d *tablesDriver }
+
+func (c *tablesConn) Prepare(query string) (driver.Stmt, error) { return &tablesStmt{c.d, query}, nil }
+func (c *tablesConn) Close() error                              { return nil }
+func (c *tablesConn) Begin() (driver.Tx, error)                 { return nil, errors.New("not implemented") }
+
+type tablesStmt struct {
+	d     *tablesDriver
+	query string
+}
+
+func (s *tablesStmt) Close() error  { return nil }
+func (s *tablesStmt) NumInput() int { return -1 }
+func (s *tablesStmt) Exec(args []driver.Value) (driver.Result, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (s *tablesStmt) Query(args []driver.Value) (driver.Rows, error) {
+	return &tablesRows{s.d.results[s.query]}, nil
+}
+
+type tablesRows struct{ values [][]driver.Value }
+
+func (r *tablesRows) Columns() []string {
+	if len(r.values) == 0 {
+		return []string{}
+	}
+	return make([]string, len(r.values[0]))
+}
+func (r *tablesRows) Close() error { return nil }
+func (r *tablesRows) Next(dest []driver.Value) error {
+	if len(r.values) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.values[0])
+	r.values = r.values[1:]
+	return nil
+}
+
+func init() {
+	sql.Register("lino-tables", &tablesDriver{results: map[string][][]driver.Value{
+		"keys": {
+			{"public", "customer", "id"},
+			{"sales", "customer", "customer_id"},
+		},
+		"unique": {
+			{"public", "customer", "email"},
+		},
+		"tables": {
+			{"public", "customer"},
+			{"public", "store"},
+		},
+		"columns": {
+			{"public", "customer", "id", "integer", int64(0), int64(1)},
+			{"public", "customer", "email", "text", int64(1), int64(0)},
+			{"sales", "customer", "customer_id", "integer", int64(0), int64(0)},
+		},
+	}})
+}
+
+func newTestExtractor(t *testing.T, schema string) (*SQLExtractor, *sql.DB) {
+	db, err := sql.Open("lino-tables", "")
+	assert.Nil(t, err)
+
+	dialect := &MockDialect{}
+	dialect.On("SQL", mock.Anything).Return("keys")
+	dialect.On("UniqueSQL", mock.Anything).Return("unique")
+	dialect.On("TablesSQL", mock.Anything).Return("tables")
+	dialect.On("ColumnsSQL", schema).Return("columns")
+
+	return NewSQLExtractor("", schema, dataconnector.Options{}, dialect), db
+}
+
+func TestExtractTableNames(t *testing.T) {
+	extractor, db := newTestExtractor(t, "public")
+	defer db.Close()
+
+	tables, err := extractor.extract(db, table.Filter{})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"customer", "store"}, names(tables))
+
+	tables, err = extractor.extract(db, table.Filter{Schemas: []string{"public", "sales"}})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"public.customer", "sales.customer", "public.store"}, names(tables))
+	assert.Equal(t, [][]string{{"email"}}, tables[0].AlternateKeys)
+}
+
+func TestExtractColumnsOfSameTableInSeveralSchemas(t *testing.T) {
+	extractor, db := newTestExtractor(t, "")
+	defer db.Close()
+
+	tables, err := extractor.extractColumns(db)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"public.customer", "sales.customer"}, names(tables))
+	assert.Equal(t, []table.Column{
+		{Name: "id", Type: "integer", HasDefault: true},
+		{Name: "email", Type: "text", Nullable: true},
+	}, tables[0].Columns)
+	assert.Equal(t, []table.Column{{Name: "customer_id", Type: "integer"}}, tables[1].Columns)
+}
+
+func names(tables []table.Table) []string {
+	result := []string{}
+	for _, t := range tables {
+		result = append(result, t.Name)
+	}
+	return result
+}
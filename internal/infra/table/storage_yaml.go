@@ -19,6 +19,7 @@ package table
 
 import (
 	"io/ioutil"
+	"os"
 
 	"github.com/cgi-fr/lino/pkg/table"
 	"gopkg.in/yaml.v3"
@@ -35,8 +36,20 @@ type YAMLStructure struct {
 
 // YAMLTable defines how to store a table in YAML format.
 type YAMLTable struct {
-	Name string   `yaml:"name"`
-	Keys []string `yaml:"keys"`
+	Name          string       `yaml:"name"`
+	Keys          []string     `yaml:"keys"`
+	AlternateKeys [][]string   `yaml:"alternateKeys,omitempty"`
+	Columns       []YAMLColumn `yaml:"columns,omitempty"`
+	View          string       `yaml:"view,omitempty"`
+	Query         string       `yaml:"query,omitempty"`
+}
+
+// YAMLColumn defines how to store a column in YAML format.
+type YAMLColumn struct {
+	Name       string `yaml:"name"`
+	Type       string `yaml:"type,omitempty"`
+	Nullable   bool   `yaml:"nullable,omitempty"`
+	HasDefault bool   `yaml:"default,omitempty"`
 }
 
 // YAMLStorage provides storage in a local YAML file
@@ -57,8 +70,19 @@ func (s YAMLStorage) List() ([]table.Table, *table.Error) {
 
 	for _, ym := range list.Tables {
 		m := table.Table{
-			Name: ym.Name,
-			Keys: ym.Keys,
+			Name:          ym.Name,
+			Keys:          ym.Keys,
+			AlternateKeys: ym.AlternateKeys,
+			View:          ym.View,
+			Query:         ym.Query,
+		}
+		for _, yc := range ym.Columns {
+			m.Columns = append(m.Columns, table.Column{
+				Name:       yc.Name,
+				Type:       yc.Type,
+				Nullable:   yc.Nullable,
+				HasDefault: yc.HasDefault,
+			})
 		}
 		result = append(result, m)
 	}
@@ -74,8 +98,19 @@ func (s YAMLStorage) Store(tables []table.Table) *table.Error {
 
 	for _, r := range tables {
 		yml := YAMLTable{
-			Name: r.Name,
-			Keys: r.Keys,
+			Name:          r.Name,
+			Keys:          r.Keys,
+			AlternateKeys: r.AlternateKeys,
+			View:          r.View,
+			Query:         r.Query,
+		}
+		for _, c := range r.Columns {
+			yml.Columns = append(yml.Columns, YAMLColumn{
+				Name:       c.Name,
+				Type:       c.Type,
+				Nullable:   c.Nullable,
+				HasDefault: c.HasDefault,
+			})
 		}
 		list.Tables = append(list.Tables, yml)
 	}
@@ -93,6 +128,10 @@ func readFile() (*YAMLStructure, *table.Error) {
 		Version: Version,
 	}
 
+	if _, err := os.Stat("tables.yaml"); os.IsNotExist(err) {
+		return list, nil
+	}
+
 	dat, err := ioutil.ReadFile("tables.yaml")
 	if err != nil {
 		return nil, &table.Error{Description: err.Error()}
@@ -21,11 +21,14 @@ package dataconnector
 type Storage interface {
 	List() ([]DataConnector, *Error)
 	Store(*DataConnector) *Error
+	// Update replaces the dataconnector named name
+	Update(name string, m *DataConnector) *Error
+	Remove(name string) *Error
 }
 
 // DataPingerFactory create a DataPing for the given `url`
 type DataPingerFactory interface {
-	New(url string) DataPinger
+	New(url string, options Options) DataPinger
 }
 
 // Datapinger test connection
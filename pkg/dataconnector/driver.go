@@ -17,10 +17,18 @@
 
 package dataconnector
 
-import "github.com/rs/zerolog/log"
+import (
+	"fmt"
+
+	"github.com/rs/zerolog/log"
+)
 
 // Add an alias to the storage, if it does not exist
 func Add(s Storage, m *DataConnector) *Error {
+	if err := validate(m); err != nil {
+		return err
+	}
+
 	exist, err := Get(s, m.Name)
 	if err != nil {
 		log.Error().Err(err).Msg("")
@@ -65,3 +73,98 @@ func List(s Storage) ([]DataConnector, *Error) {
 	}
 	return aliases, err
 }
+
+// Update an alias of the storage
+func Update(s Storage, m *DataConnector) *Error {
+	if err := validate(m); err != nil {
+		return err
+	}
+
+	exist, err := Get(s, m.Name)
+	if err != nil {
+		return err
+	}
+
+	if exist == nil {
+		return &Error{Description: fmt.Sprintf("no dataconnector named %s", m.Name)}
+	}
+
+	err = s.Update(m.Name, m)
+	if err != nil {
+		log.Error().Msg(err.Description)
+		return err
+	}
+	return nil
+}
+
+// Rename an alias of the storage, the new name must not be used
+func Rename(s Storage, name string, newName string) *Error {
+	alias, err := Get(s, name)
+	if err != nil {
+		return err
+	}
+
+	if alias == nil {
+		return &Error{Description: fmt.Sprintf("no dataconnector named %s", name)}
+	}
+
+	exist, err := Get(s, newName)
+	if err != nil {
+		return err
+	}
+
+	if exist != nil {
+		return &Error{Description: fmt.Sprintf("dataconnector %s already exists", newName)}
+	}
+
+	alias.Name = newName
+	err = s.Update(name, alias)
+	if err != nil {
+		log.Error().Msg(err.Description)
+		return err
+	}
+	return nil
+}
+
+// Remove an alias from the storage and return it
+func Remove(s Storage, name string) (*DataConnector, *Error) {
+	alias, err := Get(s, name)
+	if err != nil {
+		return nil, err
+	}
+
+	if alias == nil {
+		return nil, &Error{Description: fmt.Sprintf("no dataconnector named %s", name)}
+	}
+
+	err = s.Remove(name)
+	if err != nil {
+		log.Error().Msg(err.Description)
+		return nil, err
+	}
+	return alias, nil
+}
+
+// validate checks that the user and the password are read from one source at most, and the connection options
+func validate(m *DataConnector) *Error {
+	if sources(m.User) > 1 {
+		return &Error{Description: fmt.Sprintf("user of dataconnector %s must have only one source", m.Name)}
+	}
+	if sources(m.Password) > 1 {
+		return &Error{Description: fmt.Sprintf("password of dataconnector %s must have only one source", m.Name)}
+	}
+	if m.Options.MaxOpenConns < 0 || m.Options.ConnectTimeout < 0 || m.Options.StatementTimeout < 0 {
+		return &Error{Description: fmt.Sprintf("connection options of dataconnector %s must not be negative", m.Name)}
+	}
+	return nil
+}
+
+func sources(vh ValueHolder) int {
+	count := 0
+	for _, source := range []string{vh.Value, vh.ValueFromEnv, vh.ValueFromFile, vh.ValueFromCommand} {
+		if source != "" {
+			count++
+		}
+	}
+	return count
+}
@@ -20,6 +20,7 @@ package dataconnector_test
 import (
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/cgi-fr/lino/pkg/dataconnector"
 	"github.com/stretchr/testify/assert"
@@ -42,6 +43,28 @@ func (s *MemoryStorage) Store(m *dataconnector.DataConnector) *dataconnector.Err
 	return nil
 }
 
+// Update a dataconnector in memory
+func (s *MemoryStorage) Update(name string, m *dataconnector.DataConnector) *dataconnector.Error {
+	for i, alias := range s.repo {
+		if alias.Name == name {
+			s.repo[i] = *m
+		}
+	}
+	return nil
+}
+
+// Remove a dataconnector from memory
+func (s *MemoryStorage) Remove(name string) *dataconnector.Error {
+	repo := []dataconnector.DataConnector{}
+	for _, alias := range s.repo {
+		if alias.Name != name {
+			repo = append(repo, alias)
+		}
+	}
+	s.repo = repo
+	return nil
+}
+
 // ErrorStorage always return an error
 type ErrorStorage struct {
 	ListError  *dataconnector.Error
@@ -58,6 +81,16 @@ func (s *ErrorStorage) Store(m *dataconnector.DataConnector) *dataconnector.Erro
 	return s.StoreError
 }
 
+// Update a dataconnector in memory
+func (s *ErrorStorage) Update(name string, m *dataconnector.DataConnector) *dataconnector.Error {
+	return s.StoreError
+}
+
+// Remove a dataconnector from memory
+func (s *ErrorStorage) Remove(name string) *dataconnector.Error {
+	return s.StoreError
+}
+
 func TestAddToNonEmptyStorage(t *testing.T) {
 	storage := &MemoryStorage{repo: []dataconnector.DataConnector{dataconnector.DataConnector{Name: "First", URL: "test://localhost:1234"}}}
 	alias := &dataconnector.DataConnector{Name: "Second", URL: "test://localhost:1234"}
@@ -205,3 +238,78 @@ func TestAdd(t *testing.T) {
 		})
 	}
 }
+
+func TestUpdate(t *testing.T) {
+	storage := &MemoryStorage{repo: []dataconnector.DataConnector{{Name: "First", URL: "test://localhost:1234"}, {Name: "Second", URL: "test://localhost:5678"}}}
+
+	err := dataconnector.Update(storage, &dataconnector.DataConnector{Name: "First", URL: "test://remote:1234", ReadOnly: true})
+
+	assert.Nil(t, err)
+	assert.Equal(t, []dataconnector.DataConnector{{Name: "First", URL: "test://remote:1234", ReadOnly: true}, {Name: "Second", URL: "test://localhost:5678"}}, storage.repo)
+
+	err = dataconnector.Update(storage, &dataconnector.DataConnector{Name: "Third"})
+
+	assert.Equal(t, "no dataconnector named Third", err.Error())
+}
+
+func TestRename(t *testing.T) {
+	storage := &MemoryStorage{repo: []dataconnector.DataConnector{{Name: "First", URL: "test://localhost:1234"}, {Name: "Second", URL: "test://localhost:5678"}}}
+
+	err := dataconnector.Rename(storage, "First", "Second")
+	assert.Equal(t, "dataconnector Second already exists", err.Error())
+
+	err = dataconnector.Rename(storage, "Third", "Fourth")
+	assert.Equal(t, "no dataconnector named Third", err.Error())
+
+	err = dataconnector.Rename(storage, "First", "Source")
+	assert.Nil(t, err)
+	assert.Equal(t, []dataconnector.DataConnector{{Name: "Source", URL: "test://localhost:1234"}, {Name: "Second", URL: "test://localhost:5678"}}, storage.repo)
+}
+
+func TestRemove(t *testing.T) {
+	storage := &MemoryStorage{repo: []dataconnector.DataConnector{{Name: "First", URL: "test://localhost:1234"}, {Name: "Second", URL: "test://localhost:5678"}}}
+
+	alias, err := dataconnector.Remove(storage, "First")
+
+	assert.Nil(t, err)
+	assert.Equal(t, "test://localhost:1234", alias.URL)
+	assert.Equal(t, []dataconnector.DataConnector{{Name: "Second", URL: "test://localhost:5678"}}, storage.repo)
+
+	_, err = dataconnector.Remove(storage, "First")
+
+	assert.Equal(t, "no dataconnector named First", err.Error())
+}
+
+func TestAddSeveralSources(t *testing.T) {
+	storage := &MemoryStorage{}
+
+	err := dataconnector.Add(storage, &dataconnector.DataConnector{
+		Name:     "First",
+		URL:      "test://localhost:1234",
+		Password: dataconnector.ValueHolder{ValueFromEnv: "PASSWORD", ValueFromFile: "/run/secrets/password"},
+	})
+
+	assert.Equal(t, "password of dataconnector First must have only one source", err.Error())
+	assert.Empty(t, storage.repo)
+
+	err = dataconnector.Update(storage, &dataconnector.DataConnector{
+		Name: "First",
+		URL:  "test://localhost:1234",
+		User: dataconnector.ValueHolder{Value: "user", ValueFromCommand: "echo user"},
+	})
+
+	assert.Equal(t, "user of dataconnector First must have only one source", err.Error())
+}
+
+func TestAddNegativeOptions(t *testing.T) {
+	storage := &MemoryStorage{}
+
+	err := dataconnector.Add(storage, &dataconnector.DataConnector{
+		Name:    "First",
+		URL:     "test://localhost:1234",
+		Options: dataconnector.Options{ConnectTimeout: -time.Second},
+	})
+
+	assert.Equal(t, "connection options of dataconnector First must not be negative", err.Error())
+	assert.Empty(t, storage.repo)
+}
@@ -9,13 +9,13 @@ type MockDataPingerFactory struct {
 	mock.Mock
 }
 
-// New provides a mock function with given fields: url
-func (_m *MockDataPingerFactory) New(url string) DataPinger {
-	ret := _m.Called(url)
+// New provides a mock function with given fields: url, options
+func (_m *MockDataPingerFactory) New(url string, options Options) DataPinger {
+	ret := _m.Called(url, options)
 
 	var r0 DataPinger
-	if rf, ok := ret.Get(0).(func(string) DataPinger); ok {
-		r0 = rf(url)
+	if rf, ok := ret.Get(0).(func(string, Options) DataPinger); ok {
+		r0 = rf(url, options)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(DataPinger)
@@ -34,6 +34,22 @@ func (_m *MockStorage) List() ([]DataConnector, *Error) {
 	return r0, r1
 }
 
+// Remove provides a mock function with given fields: name
+func (_m *MockStorage) Remove(name string) *Error {
+	ret := _m.Called(name)
+
+	var r0 *Error
+	if rf, ok := ret.Get(0).(func(string) *Error); ok {
+		r0 = rf(name)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*Error)
+		}
+	}
+
+	return r0
+}
+
 // Store provides a mock function with given fields: _a0
 func (_m *MockStorage) Store(_a0 *DataConnector) *Error {
 	ret := _m.Called(_a0)
@@ -49,3 +65,19 @@ func (_m *MockStorage) Store(_a0 *DataConnector) *Error {
 
 	return r0
 }
+
+// Update provides a mock function with given fields: name, m
+func (_m *MockStorage) Update(name string, m *DataConnector) *Error {
+	ret := _m.Called(name, m)
+
+	var r0 *Error
+	if rf, ok := ret.Get(0).(func(string, *DataConnector) *Error); ok {
+		r0 = rf(name, m)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*Error)
+		}
+	}
+
+	return r0
+}
@@ -17,6 +17,8 @@
 
 package dataconnector
 
+import "time"
+
 // DataConnector holds a name (alias) and a URI to a database.
 type DataConnector struct {
 	Name     string
@@ -25,11 +27,23 @@ type DataConnector struct {
 	Schema   string
 	User     ValueHolder
 	Password ValueHolder
+	Options  Options
+}
+
+// Options tune the connections opened by pull, push, ping and the extractors, zero values keep the defaults.
+type Options struct {
+	MaxOpenConns     int
+	ConnectTimeout   time.Duration
+	StatementTimeout time.Duration
+	SessionInit      []string
 }
 
+// ValueHolder holds a value or the source to read it from (environment variable, file or command output).
 type ValueHolder struct {
-	Value        string
-	ValueFromEnv string
+	Value            string
+	ValueFromEnv     string
+	ValueFromFile    string
+	ValueFromCommand string
 }
 
 // Error is the error type returned by the domain
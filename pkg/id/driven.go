@@ -17,17 +17,47 @@
 
 package id
 
+import "github.com/cgi-fr/lino/pkg/dataconnector"
+
 // Storage allows to store and retrieve ingress descriptor objects.
 type Storage interface {
 	Store(IngressDescriptor) *Error
 	Read() (IngressDescriptor, *Error)
 }
 
+// Catalog lists the named ingress descriptors.
+type Catalog interface {
+	List() ([]string, *Error)
+}
+
 // RelationReader read relations from a source.
 type RelationReader interface {
 	Read() (RelationList, *Error)
 }
 
+// PrimaryKeyReader read the primary keys of the tables, indexed by table name.
+type PrimaryKeyReader interface {
+	Read() (map[string][]string, *Error)
+}
+
+// RelationKeysReader read the columns joined by the relations, indexed by relation name.
+type RelationKeysReader interface {
+	Read() (map[string]RelationKeys, *Error)
+}
+
+// StatisticsReaderFactory exposes methods to create new statistics readers.
+type StatisticsReaderFactory interface {
+	New(url string, schema string, options dataconnector.Options) StatisticsReader
+}
+
+// StatisticsReader read the statistics gathered by the database optimizer, a value of 0 means no statistics.
+type StatisticsReader interface {
+	Rows(table string) (float64, *Error)
+	RowSize(table string) (float64, *Error)
+	Distinct(table string, columns []string) (float64, *Error)
+	Close() *Error
+}
+
 // Exporter export the puller plan.
 type Exporter interface {
 	Export(PullerPlan) *Error
@@ -19,6 +19,7 @@ package id
 
 import (
 	"fmt"
+	"sort"
 
 	"github.com/rs/zerolog/log"
 )
@@ -30,6 +31,92 @@ func Create(startTable string, relReader RelationReader, storage Storage) *Error
 		return err
 	}
 
+	adrelations, err := defaultIngressRelations(startTable, relations)
+	if err != nil {
+		return err
+	}
+
+	id := NewIngressDescriptor(NewTable(startTable), NewIngressRelationList(adrelations))
+
+	err = storage.Store(id)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Refresh merge the stored ingress descriptor with the relation set and returns the changes.
+// Lookups of existing relations are kept, new relations reachable from a root are added with default lookups and removed relations are dropped.
+func Refresh(relReader RelationReader, storage Storage) ([]Change, *Error) {
+	id, err := storage.Read()
+	if err != nil {
+		return nil, err
+	}
+
+	relations, err := relReader.Read()
+	if err != nil {
+		return nil, err
+	}
+
+	defaults := []IngressRelation{}
+	reachable := newSet()
+	for _, root := range id.Roots() {
+		rootRelations, err := defaultIngressRelations(root.Table, relations)
+		if err != nil {
+			return nil, err
+		}
+		for _, rel := range rootRelations {
+			if !reachable.contains(rel.Name()) {
+				reachable.add(rel.Name())
+				defaults = append(defaults, rel)
+			}
+		}
+	}
+
+	known := map[string]Relation{}
+	for i := uint(0); i < relations.Len(); i++ {
+		known[relations.Relation(i).Name()] = relations.Relation(i)
+	}
+
+	changes := []Change{}
+	merged := []IngressRelation{}
+	existing := newSet()
+
+	for i := uint(0); i < id.Relations().Len(); i++ {
+		rel := id.Relations().Relation(i)
+		existing.add(rel.Name())
+
+		modelRel, ok := known[rel.Name()]
+		switch {
+		case !ok:
+			changes = append(changes, Change{RelationRemoved, rel.Name(), fmt.Sprintf("relation %s no longer exists", rel.Name())})
+		case modelRel.Parent().Name() != rel.Parent().Name() || modelRel.Child().Name() != rel.Child().Name():
+			changes = append(changes, Change{RelationChanged, rel.Name(), fmt.Sprintf("relation %s now links %s to %s, lookups are kept", rel.Name(), modelRel.Parent().Name(), modelRel.Child().Name())})
+			merged = append(merged, NewBoundedIngressRelation(NewRelation(rel.Name(), modelRel.Parent(), modelRel.Child()), rel.LookUpParent(), rel.LookUpChild(), rel.MaxDepth()))
+		default:
+			merged = append(merged, rel)
+		}
+	}
+
+	for _, rel := range defaults {
+		if existing.contains(rel.Name()) {
+			continue
+		}
+		changes = append(changes, Change{RelationAdded, rel.Name(), fmt.Sprintf("relation %s from %s to %s added (lookup parent: %v, lookup child: %v)", rel.Name(), rel.Parent().Name(), rel.Child().Name(), rel.LookUpParent(), rel.LookUpChild())})
+		merged = append(merged, rel)
+	}
+
+	err = storage.Store(NewMultiRootIngressDescriptor(id.Roots(), id.ReferenceTables(), NewIngressRelationList(merged)))
+	if err != nil {
+		return nil, err
+	}
+
+	return changes, nil
+}
+
+// defaultIngressRelations returns the relations reachable from the start table, with child lookups enabled from the start table.
+func defaultIngressRelations(startTable string, relations RelationList) ([]IngressRelation, *Error) {
 	ingressRels := []IngressRelation{}
 	for i := uint(0); i < relations.Len(); i++ {
 		rel := relations.Relation(i)
@@ -40,7 +127,7 @@ func Create(startTable string, relReader RelationReader, storage Storage) *Error
 
 	connectedGraph, err := fullGraph.getConnectedGraph(startTable)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	setLookUpChild := newSet()
@@ -50,7 +137,7 @@ func Create(startTable string, relReader RelationReader, storage Storage) *Error
 			setLookUpChild.add(rel.Name())
 		}
 	}); err != nil {
-		return err
+		return nil, err
 	}
 
 	adrelations := []IngressRelation{}
@@ -63,14 +150,19 @@ func Create(startTable string, relReader RelationReader, storage Storage) *Error
 		}
 	}
 
-	id := NewIngressDescriptor(NewTable(startTable), NewIngressRelationList(adrelations))
+	return adrelations, nil
+}
 
-	err = storage.Store(id)
+// List returns the sorted names of the ingress descriptors in the catalog.
+func List(catalog Catalog) ([]string, *Error) {
+	names, err := catalog.List()
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	return nil
+	sort.Strings(names)
+
+	return names, nil
 }
 
 // SetStartTable update ingress descriptor start table
@@ -89,7 +181,44 @@ func SetStartTable(table Table, storage Storage) *Error {
 		return &Error{Description: fmt.Sprintf("Table %s doesn't exist", table.Name())}
 	}
 
-	updatedID := NewIngressDescriptor(table, id.Relations())
+	roots := append([]Root{{Table: table.Name()}}, id.Roots()[1:]...)
+	updatedID := NewMultiRootIngressDescriptor(roots, id.ReferenceTables(), id.Relations())
+
+	err = storage.Store(updatedID)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// SetReferenceTable add (flag true) or remove (flag false) a table from the reference tables of the ingress descriptor
+func SetReferenceTable(table Table, flag bool, storage Storage) *Error {
+	id, err := storage.Read()
+	if err != nil {
+		return err
+	}
+
+	tableExist := false
+	for i := uint(0); i < id.Relations().Len(); i++ {
+		rel := id.Relations().Relation(i)
+		tableExist = tableExist || rel.Parent().Name() == table.Name() || rel.Child().Name() == table.Name()
+	}
+	if !tableExist {
+		return &Error{Description: fmt.Sprintf("Table %s doesn't exist", table.Name())}
+	}
+
+	references := []Table{}
+	for i := uint(0); i < id.ReferenceTables().Len(); i++ {
+		reference := id.ReferenceTables().Table(i)
+		if reference.Name() != table.Name() {
+			references = append(references, reference)
+		}
+	}
+	if flag {
+		references = append(references, table)
+	}
+
+	updatedID := NewMultiRootIngressDescriptor(id.Roots(), NewTableList(references), id.Relations())
 
 	err = storage.Store(updatedID)
 	if err != nil {
@@ -114,12 +243,12 @@ func SetChildLookup(relation string, flag bool, storage Storage) *Error {
 	for i := uint(0); i < id.Relations().Len(); i++ {
 		rel := id.Relations().Relation(i)
 		if rel.Name() == relation {
-			rel = NewIngressRelation(NewRelation(rel.Name(), rel.Parent(), rel.Child()), rel.LookUpParent(), flag)
+			rel = NewBoundedIngressRelation(NewRelation(rel.Name(), rel.Parent(), rel.Child()), rel.LookUpParent(), flag, rel.MaxDepth())
 		}
 		relations[i] = rel
 	}
 
-	updatedID := NewIngressDescriptor(id.StartTable(), NewIngressRelationList(relations))
+	updatedID := NewMultiRootIngressDescriptor(id.Roots(), id.ReferenceTables(), NewIngressRelationList(relations))
 
 	err = storage.Store(updatedID)
 	if err != nil {
@@ -144,12 +273,42 @@ func SetParentLookup(relation string, flag bool, storage Storage) *Error {
 	for i := uint(0); i < id.Relations().Len(); i++ {
 		rel := id.Relations().Relation(i)
 		if rel.Name() == relation {
-			rel = NewIngressRelation(NewRelation(rel.Name(), rel.Parent(), rel.Child()), flag, rel.LookUpChild())
+			rel = NewBoundedIngressRelation(NewRelation(rel.Name(), rel.Parent(), rel.Child()), flag, rel.LookUpChild(), rel.MaxDepth())
+		}
+		relations[i] = rel
+	}
+
+	updatedID := NewMultiRootIngressDescriptor(id.Roots(), id.ReferenceTables(), NewIngressRelationList(relations))
+
+	err = storage.Store(updatedID)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// SetMaxDepth update the maximum number of times a relation is followed in a cycle (0 for no limit)
+func SetMaxDepth(relation string, depth uint, storage Storage) *Error {
+	id, err := storage.Read()
+	if err != nil {
+		return err
+	}
+
+	if !id.Relations().Contains(relation) {
+		return &Error{Description: fmt.Sprintf("Relation %s doesn't exist", relation)}
+	}
+
+	relations := make([]IngressRelation, id.Relations().Len())
+
+	for i := uint(0); i < id.Relations().Len(); i++ {
+		rel := id.Relations().Relation(i)
+		if rel.Name() == relation {
+			rel = NewBoundedIngressRelation(NewRelation(rel.Name(), rel.Parent(), rel.Child()), rel.LookUpParent(), rel.LookUpChild(), depth)
 		}
 		relations[i] = rel
 	}
 
-	updatedID := NewIngressDescriptor(id.StartTable(), NewIngressRelationList(relations))
+	updatedID := NewMultiRootIngressDescriptor(id.Roots(), id.ReferenceTables(), NewIngressRelationList(relations))
 
 	err = storage.Store(updatedID)
 	if err != nil {
@@ -159,6 +318,7 @@ func SetParentLookup(relation string, flag bool, storage Storage) *Error {
 }
 
 // GetPullerPlan returns the calculated puller plan.
+// With several roots, the steps of each root follow the steps of the previous root and the first step of a root has no previous step.
 func GetPullerPlan(storage Storage) (PullerPlan, *Error) {
 	id, err := storage.Read()
 	if err != nil {
@@ -168,34 +328,45 @@ func GetPullerPlan(storage Storage) (PullerPlan, *Error) {
 	g := newGraph(id.Relations())
 	g = g.slim() // remove inactive relations
 
-	var startComponent TableList
 	components := g.condense()
+
+	steps := []Step{}
+	for _, root := range id.Roots() {
+		steps = append(steps, g.rootSteps(NewTable(root.Table), components, uint(len(steps)))...)
+	}
+
+	return NewPullerPlan(steps, g.relations, g.tables), nil
+}
+
+// rootSteps returns the steps starting from the start table, numbered after offset.
+func (g graph) rootSteps(start Table, components []TableList, offset uint) []Step {
+	var startComponent TableList
 	for i, component := range components {
-		cycles := g.subGraph(component).relCycles(id.StartTable())
+		cycles := g.subGraph(component).relCycles(start)
 		log.Debug().Msg(fmt.Sprintf("component %v - %v - %v", i, component, cycles))
-		if component.Contains(id.StartTable().Name()) {
+		if component.Contains(start.Name()) {
 			startComponent = component
 		}
 	}
 	log.Debug().Msg("")
 
 	startRelationsList := NewIngressRelationList([]IngressRelation{})
-	startTableList := NewTableList([]Table{id.StartTable()})
+	startTableList := NewTableList([]Table{start})
 	startCycles := NewCycleList([]IngressRelationList{})
 	if startComponent != nil {
 		sg := g.subGraph(startComponent)
-		startCycles = sg.relCycles(id.StartTable())
+		startCycles = sg.relCycles(start)
 		startTableList = startComponent
 		startRelationsList = sg.relations
 	}
 	steps := []Step{
-		NewStep(1, id.StartTable(), NewIngressRelation(NewRelation("", nil, nil), false, false), startRelationsList, startTableList, startCycles, 0),
+		NewStep(offset+1, start, NewIngressRelation(NewRelation("", nil, nil), false, false), startRelationsList, startTableList, startCycles, 0),
 	}
 	log.Debug().Msg(fmt.Sprintf("%v", steps[0]))
 
-	err = g.visitComponents(id.StartTable().Name(), func(r IngressRelation, comingFrom, goingTo Table, fromComponent, toComponent TableList, fromIndex, toIndex int, thisStepNumber, fromStepNumber uint) bool {
+	err := g.visitComponents(start.Name(), func(r IngressRelation, comingFrom, goingTo Table, fromComponent, toComponent TableList, fromIndex, toIndex int, thisStepNumber, fromStepNumber uint) bool {
 		subgraph := g.subGraph(toComponent)
-		step := NewStep(thisStepNumber+1, goingTo, r, subgraph.relations, toComponent, subgraph.relCycles(goingTo), fromStepNumber+1)
+		step := NewStep(offset+thisStepNumber+1, goingTo, r, subgraph.relations, toComponent, subgraph.relCycles(goingTo), offset+fromStepNumber+1)
 		log.Debug().Msg(fmt.Sprintf("%v", step))
 		steps = append(steps, step)
 		return true
@@ -205,7 +376,7 @@ func GetPullerPlan(storage Storage) (PullerPlan, *Error) {
 		log.Warn().Msg(err.Error())
 	}
 
-	return NewPullerPlan(steps, g.relations, g.tables), nil
+	return steps
 }
 
 // Export the puller plan.
@@ -433,6 +433,27 @@ func TestUpdateChildLookup(t *testing.T) {
 	})), storage.id)
 }
 
+func TestUpdateReferenceTable(t *testing.T) {
+	relations := id.NewIngressRelationList([]id.IngressRelation{
+		adRelationString("A->B", false, true),
+	})
+	storage := &MemoryStorage{id: id.NewIngressDescriptor(id.NewTable("A"), relations)}
+
+	err := id.SetReferenceTable(id.NewTable("B"), true, storage)
+
+	assert.Nil(t, err)
+	assert.Equal(t, id.NewMultiRootIngressDescriptor([]id.Root{{Table: "A"}}, id.NewTableList([]id.Table{id.NewTable("B")}), relations), storage.id)
+
+	err = id.SetReferenceTable(id.NewTable("C"), true, storage)
+
+	assert.EqualError(t, err, "Table C doesn't exist")
+
+	err = id.SetReferenceTable(id.NewTable("B"), false, storage)
+
+	assert.Nil(t, err)
+	assert.Equal(t, id.NewIngressDescriptor(id.NewTable("A"), relations), storage.id)
+}
+
 func TestGetSteps(t *testing.T) {
 	for i, tt := range adShowTests {
 		t.Run(fmt.Sprintf("test get step %d", i), func(t *testing.T) {
@@ -450,3 +471,140 @@ func TestGetSteps(t *testing.T) {
 		})
 	}
 }
+
+func TestList(t *testing.T) {
+	catalog := &id.MockCatalog{}
+	catalog.On("List").Return([]string{"film", "customer"}, nil)
+
+	names, err := id.List(catalog)
+
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"customer", "film"}, names)
+}
+
+func TestValidate(t *testing.T) {
+	storage := &MemoryStorage{id: id.NewIngressDescriptor(id.NewTable("A"), id.NewIngressRelationList([]id.IngressRelation{
+		adRelationString("A->B", false, true),
+		adRelationString("B->A", false, true),
+		adRelationString("X->Y", false, true),
+		adRelationString("D->A", false, false),
+		adRelationString("A->E", false, true),
+	}))}
+	relReader := &MockRelationReader{func() (id.RelationList, *id.Error) {
+		return id.NewRelationList([]id.Relation{
+			relationString("A->B"),
+			relationString("B->A"),
+			relationString("X->Y"),
+		}), nil
+	}}
+	pkReader := &id.MockPrimaryKeyReader{}
+	pkReader.On("Read").Return(map[string][]string{"A": {"id"}}, nil)
+
+	issues, err := id.Validate(storage, relReader, pkReader)
+
+	assert.Nil(t, err)
+
+	summary := []string{}
+	for _, issue := range issues {
+		subject := issue.Relation
+		if issue.Table != "" {
+			subject = issue.Table
+		}
+		summary = append(summary, fmt.Sprintf("%s %s %s", issue.Level, issue.Kind, subject))
+	}
+	assert.ElementsMatch(t, []string{
+		"error lookup-on-removed-relation A_E",
+		"error missing-primary-key B",
+		"warning unknown-relation D_A",
+		"warning unreachable-relation X_Y",
+	}, summary)
+	assert.Equal(t, id.IssueError, issues[0].Level)
+	assert.Equal(t, id.IssueError, issues[1].Level)
+}
+
+func TestExplain(t *testing.T) {
+	storage := &MemoryStorage{id: id.NewIngressDescriptor(id.NewTable("A"), id.NewIngressRelationList([]id.IngressRelation{
+		adRelationString("A->B", false, true),
+		adRelationString("C->B", true, false),
+	}))}
+	keysReader := &id.MockRelationKeysReader{}
+	keysReader.On("Read").Return(map[string]id.RelationKeys{
+		"A_B": {Parent: []string{"id"}, Child: []string{"a_id"}},
+		"C_B": {Parent: []string{"id"}, Child: []string{"c_id"}},
+	}, nil)
+	stats := &id.MockStatisticsReader{}
+	stats.On("Rows", "A").Return(float64(50), nil)
+	stats.On("Rows", "B").Return(float64(1000), nil)
+	stats.On("Rows", "C").Return(float64(20), nil)
+	stats.On("RowSize", "A").Return(float64(10), nil)
+	stats.On("RowSize", "B").Return(float64(20), nil)
+	stats.On("RowSize", "C").Return(float64(30), nil)
+	stats.On("Distinct", "B", []string{"a_id"}).Return(float64(10), nil)
+	stats.On("Close").Return(nil)
+
+	explanation, err := id.Explain(storage, keysReader, stats, 5, []string{}, 50)
+
+	assert.Nil(t, err)
+	assert.Len(t, explanation.Steps, 3)
+	assert.Equal(t, float64(5), explanation.Steps[0].Rows)
+	assert.Equal(t, "child", explanation.Steps[1].Direction)
+	assert.Equal(t, float64(100), explanation.Steps[1].FanOut)
+	assert.Equal(t, float64(500), explanation.Steps[1].Rows)
+	assert.Len(t, explanation.Steps[1].Warnings, 1)
+	assert.Equal(t, "parent", explanation.Steps[2].Direction)
+	assert.Equal(t, float64(20), explanation.Steps[2].Rows)
+	assert.Empty(t, explanation.Steps[2].Warnings)
+	assert.Equal(t, float64(525), explanation.Rows)
+	assert.Equal(t, float64(10650), explanation.Bytes)
+	stats.AssertCalled(t, "Close")
+}
+
+func TestRefresh(t *testing.T) {
+	storage := &MemoryStorage{id: id.NewIngressDescriptor(id.NewTable("A"), id.NewIngressRelationList([]id.IngressRelation{
+		adRelationString("A->B", true, false),
+		adRelationString("B->C", false, true),
+	}))}
+	relReader := &MockRelationReader{func() (id.RelationList, *id.Error) {
+		return id.NewRelationList([]id.Relation{
+			relationString("A->B"),
+			relationString("A->D"),
+			relationString("X->Y"),
+		}), nil
+	}}
+
+	changes, err := id.Refresh(relReader, storage)
+
+	assert.Nil(t, err)
+	assert.Equal(t, []id.Change{
+		{Kind: id.RelationRemoved, Relation: "B_C", Message: "relation B_C no longer exists"},
+		{Kind: id.RelationAdded, Relation: "A_D", Message: "relation A_D from A to D added (lookup parent: false, lookup child: true)"},
+	}, changes)
+
+	refreshed, _ := storage.Read()
+	assert.Equal(t, "A", refreshed.StartTable().Name())
+	assert.Equal(t, uint(2), refreshed.Relations().Len())
+	assert.Equal(t, "A_B", refreshed.Relations().Relation(0).Name())
+	assert.True(t, refreshed.Relations().Relation(0).LookUpParent())
+	assert.False(t, refreshed.Relations().Relation(0).LookUpChild())
+	assert.Equal(t, "A_D", refreshed.Relations().Relation(1).Name())
+	assert.True(t, refreshed.Relations().Relation(1).LookUpChild())
+}
+
+func TestGetPullerPlanMultiRoot(t *testing.T) {
+	storage := &MemoryStorage{id: id.NewMultiRootIngressDescriptor([]id.Root{{Table: "A"}, {Table: "C", Limit: 5}}, id.NewTableList([]id.Table{}), id.NewIngressRelationList([]id.IngressRelation{
+		adRelationString("A->B", false, true),
+		adRelationString("C->D", false, true),
+	}))}
+
+	plan, err := id.GetPullerPlan(storage)
+
+	assert.Nil(t, err)
+	assert.Equal(t, uint(4), plan.Len())
+
+	summary := []string{}
+	for i := uint(0); i < plan.Len(); i++ {
+		step := plan.Step(i)
+		summary = append(summary, fmt.Sprintf("%d %s %d", step.Index(), step.Entry().Name(), step.PreviousStep()))
+	}
+	assert.Equal(t, []string{"1 A 0", "2 B 1", "3 C 0", "4 D 3"}, summary)
+}
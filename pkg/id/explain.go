@@ -0,0 +1,199 @@
+// Copyright (C) 2021 CGI France
+//
+// This file is part of LINO.
+//
+// LINO is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// LINO is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with LINO.  If not, see <http://www.gnu.org/licenses/>.
+
+package id
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// Explain estimate the number of rows and bytes pulled by each step of the puller plan from the database statistics.
+// Rows pulled by each root are bounded by limit (0 for no limit) and reduced by the selectivity of the filter columns, unless the root defines its own.
+// A step whose fan-out is greater than maxFanOut is flagged with a warning.
+func Explain(storage Storage, keysReader RelationKeysReader, stats StatisticsReader, limit uint, filter []string, maxFanOut float64) (Explanation, *Error) {
+	defer stats.Close()
+
+	descriptor, err := storage.Read()
+	if err != nil {
+		return Explanation{}, err
+	}
+
+	plan, err := GetPullerPlan(storage)
+	if err != nil {
+		return Explanation{}, err
+	}
+
+	keys, err := keysReader.Read()
+	if err != nil {
+		return Explanation{}, err
+	}
+
+	explanation := Explanation{Steps: []StepEstimate{}}
+	rows := map[uint]float64{}
+	roots := descriptor.Roots()
+	rootIdx := 0
+
+	for i := uint(0); i < plan.Len(); i++ {
+		step := plan.Step(i)
+		entry := step.Entry().Name()
+
+		estimate := StepEstimate{Index: step.Index(), Entry: entry, FanOut: 1, Warnings: []string{}}
+
+		tableRows, err := stats.Rows(entry)
+		if err != nil {
+			return Explanation{}, err
+		}
+		if tableRows <= 0 {
+			estimate.Warnings = append(estimate.Warnings, fmt.Sprintf("no statistics for table %s", entry))
+		}
+
+		if step.PreviousStep() == 0 {
+			rootLimit, rootFilter := limit, filter
+			if rootIdx < len(roots) {
+				rootLimit, rootFilter = rootSettings(roots[rootIdx], limit, filter)
+			}
+			rootIdx++
+
+			estimate.Rows, err = startRows(stats, entry, tableRows, rootLimit, rootFilter)
+			if err != nil {
+				return Explanation{}, err
+			}
+		} else {
+			rel := step.Following()
+			estimate.Follow = rel.Name()
+
+			if rel.Child().Name() == entry {
+				estimate.Direction = "child"
+
+				relKeys, ok := keys[rel.Name()]
+				if !ok {
+					return Explanation{}, &Error{Description: fmt.Sprintf("no relation named %s", rel.Name())}
+				}
+
+				distinct, err := stats.Distinct(entry, relKeys.Child)
+				if err != nil {
+					return Explanation{}, err
+				}
+				if distinct > 0 {
+					estimate.FanOut = tableRows / distinct
+				} else {
+					estimate.FanOut = tableRows
+				}
+			} else {
+				// a child row references at most one parent row
+				estimate.Direction = "parent"
+			}
+
+			estimate.Rows = rows[step.PreviousStep()] * estimate.FanOut
+			if tableRows > 0 {
+				estimate.Rows = math.Min(estimate.Rows, tableRows)
+			}
+
+			if estimate.FanOut > maxFanOut {
+				estimate.Warnings = append(estimate.Warnings, fmt.Sprintf("fan-out %.1f of relation %s exceeds %v", estimate.FanOut, rel.Name(), maxFanOut))
+			}
+		}
+
+		if step.Cycles().Len() > 0 {
+			warning, err := cycleWarning(stats, step.Tables())
+			if err != nil {
+				return Explanation{}, err
+			}
+			estimate.Warnings = append(estimate.Warnings, warning)
+		}
+
+		rowSize, err := stats.RowSize(entry)
+		if err != nil {
+			return Explanation{}, err
+		}
+
+		estimate.Bytes = estimate.Rows * rowSize
+		rows[step.Index()] = estimate.Rows
+
+		explanation.Steps = append(explanation.Steps, estimate)
+		explanation.Rows += estimate.Rows
+		explanation.Bytes += estimate.Bytes
+	}
+
+	return explanation, nil
+}
+
+// rootSettings returns the limit and filter columns of the root, limit and filter are used if the root doesn't define them.
+func rootSettings(root Root, limit uint, filter []string) (uint, []string) {
+	if root.Limit > 0 {
+		limit = root.Limit
+	}
+	columns := append([]string{}, filter...)
+	for column := range root.Filter {
+		if !contains(columns, column) {
+			columns = append(columns, column)
+		}
+	}
+	sort.Strings(columns)
+	return limit, columns
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// startRows estimate the rows pulled from the start table, assuming uniformly distributed filter values.
+func startRows(stats StatisticsReader, table string, tableRows float64, limit uint, filter []string) (float64, *Error) {
+	result := tableRows
+
+	if len(filter) > 0 && tableRows > 0 {
+		distinct, err := stats.Distinct(table, filter)
+		if err != nil {
+			return 0, err
+		}
+		if distinct > 0 {
+			result = tableRows / distinct
+		}
+	}
+
+	if limit > 0 && (tableRows <= 0 || result > float64(limit)) {
+		result = float64(limit)
+	}
+
+	return result, nil
+}
+
+// cycleWarning describe the rows that can be pulled in loop by the tables of a component.
+func cycleWarning(stats StatisticsReader, tables TableList) (string, *Error) {
+	names := []string{}
+	total := float64(0)
+	for i := uint(0); i < tables.Len(); i++ {
+		name := tables.Table(i).Name()
+		rows, err := stats.Rows(name)
+		if err != nil {
+			return "", err
+		}
+		names = append(names, name)
+		total += rows
+	}
+	sort.Strings(names)
+
+	return fmt.Sprintf("cycles between tables %s can pull up to %.0f rows", strings.Join(names, ", "), total), nil
+}
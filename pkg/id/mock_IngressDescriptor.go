@@ -9,6 +9,22 @@ type MockIngressDescriptor struct {
 	mock.Mock
 }
 
+// ReferenceTables provides a mock function with given fields:
+func (_m *MockIngressDescriptor) ReferenceTables() TableList {
+	ret := _m.Called()
+
+	var r0 TableList
+	if rf, ok := ret.Get(0).(func() TableList); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(TableList)
+		}
+	}
+
+	return r0
+}
+
 // Relations provides a mock function with given fields:
 func (_m *MockIngressDescriptor) Relations() IngressRelationList {
 	ret := _m.Called()
@@ -25,6 +41,22 @@ func (_m *MockIngressDescriptor) Relations() IngressRelationList {
 	return r0
 }
 
+// Roots provides a mock function with given fields:
+func (_m *MockIngressDescriptor) Roots() []Root {
+	ret := _m.Called()
+
+	var r0 []Root
+	if rf, ok := ret.Get(0).(func() []Root); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]Root)
+		}
+	}
+
+	return r0
+}
+
 // StartTable provides a mock function with given fields:
 func (_m *MockIngressDescriptor) StartTable() Table {
 	ret := _m.Called()
@@ -53,6 +53,20 @@ func (_m *MockIngressRelation) LookUpParent() bool {
 	return r0
 }
 
+// MaxDepth provides a mock function with given fields:
+func (_m *MockIngressRelation) MaxDepth() uint {
+	ret := _m.Called()
+
+	var r0 uint
+	if rf, ok := ret.Get(0).(func() uint); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(uint)
+	}
+
+	return r0
+}
+
 // Name provides a mock function with given fields:
 func (_m *MockIngressRelation) Name() string {
 	ret := _m.Called()
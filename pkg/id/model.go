@@ -52,6 +52,7 @@ type IngressRelation interface {
 	Relation
 	LookUpChild() bool
 	LookUpParent() bool
+	MaxDepth() uint
 }
 
 // IngressRelationList involved in an puller plan.
@@ -65,13 +66,25 @@ type IngressRelationList interface {
 // IngressDescriptor from which the puller plan will be computed.
 type IngressDescriptor interface {
 	StartTable() Table
+	Roots() []Root
+	ReferenceTables() TableList
 	Relations() IngressRelationList
 	String() string
 }
 
+// Root is a start table of the ingress descriptor, with its own filter, limit and where clause.
+type Root struct {
+	Name   string
+	Table  string
+	Filter map[string]string
+	Limit  uint
+	Where  string
+}
+
 // A Cycle in the puller plan.
 type Cycle interface {
 	IngressRelationList
+	MaxDepth() uint
 }
 
 // A CycleList in the puller plan.
@@ -102,6 +115,64 @@ type PullerPlan interface {
 	String() string
 }
 
+// IssueLevel is the severity of an issue.
+type IssueLevel string
+
+const (
+	// IssueError will make the pull fail or return wrong data.
+	IssueError IssueLevel = "error"
+	// IssueWarning is suspicious but does not prevent the pull.
+	IssueWarning IssueLevel = "warning"
+)
+
+// Issue found by the validation of an ingress descriptor.
+type Issue struct {
+	Level    IssueLevel
+	Kind     string
+	Relation string
+	Table    string
+	Message  string
+}
+
+// Kinds of changes reported by Refresh.
+const (
+	RelationAdded   = "added"
+	RelationRemoved = "removed"
+	RelationChanged = "changed"
+)
+
+// Change made to an ingress descriptor by a refresh.
+type Change struct {
+	Kind     string
+	Relation string
+	Message  string
+}
+
+// RelationKeys holds the columns joined by a relation.
+type RelationKeys struct {
+	Parent []string
+	Child  []string
+}
+
+// StepEstimate is the estimated volume pulled by a step of the puller plan.
+type StepEstimate struct {
+	Index     uint
+	Entry     string
+	Follow    string
+	Direction string
+	FanOut    float64
+	Rows      float64
+	Bytes     float64
+	Warnings  []string
+}
+
+// Explanation is the estimated volume pulled by a puller plan.
+type Explanation struct {
+	Steps []StepEstimate
+	Rows  float64
+	Bytes float64
+}
+
 // Error is the error type returned by the domain
 type Error struct {
 	Description string
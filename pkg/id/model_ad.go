@@ -26,11 +26,17 @@ type idrelation struct {
 	Relation
 	lookUpParent bool
 	lookUpChild  bool
+	maxDepth     uint
 }
 
 // NewIngressRelation initialize a new IngressRelation object
 func NewIngressRelation(rel Relation, lookUpParent bool, lookUpChild bool) IngressRelation {
-	return idrelation{Relation: rel, lookUpParent: lookUpParent, lookUpChild: lookUpChild}
+	return NewBoundedIngressRelation(rel, lookUpParent, lookUpChild, 0)
+}
+
+// NewBoundedIngressRelation initialize a new IngressRelation object followed at most maxDepth times in a cycle (0 for no limit)
+func NewBoundedIngressRelation(rel Relation, lookUpParent bool, lookUpChild bool, maxDepth uint) IngressRelation {
+	return idrelation{Relation: rel, lookUpParent: lookUpParent, lookUpChild: lookUpChild, maxDepth: maxDepth}
 }
 
 func (r idrelation) Name() string       { return r.Relation.Name() }
@@ -38,6 +44,7 @@ func (r idrelation) Parent() Table      { return r.Relation.Parent() }
 func (r idrelation) Child() Table       { return r.Relation.Child() }
 func (r idrelation) LookUpParent() bool { return r.lookUpParent }
 func (r idrelation) LookUpChild() bool  { return r.lookUpChild }
+func (r idrelation) MaxDepth() uint     { return r.maxDepth }
 func (r idrelation) String() string {
 	switch {
 	case r.LookUpChild() && r.LookUpParent():
@@ -86,14 +93,23 @@ func (l idrelationList) String() string {
 
 // NewIngressDescriptor initialize a new IngressDescriptor object
 func NewIngressDescriptor(start Table, relations IngressRelationList) IngressDescriptor {
-	return id{startTable: table{name: start.Name()}, relations: relations}
+	return NewMultiRootIngressDescriptor([]Root{{Table: start.Name()}}, NewTableList([]Table{}), relations)
+}
+
+// NewMultiRootIngressDescriptor initialize a new IngressDescriptor object with several start tables, the first root is the start table
+func NewMultiRootIngressDescriptor(roots []Root, references TableList, relations IngressRelationList) IngressDescriptor {
+	return id{startTable: table{name: roots[0].Table}, roots: roots, references: references, relations: relations}
 }
 
 type id struct {
 	startTable table
+	roots      []Root
+	references TableList
 	relations  IngressRelationList
 }
 
 func (id id) StartTable() Table              { return id.startTable }
+func (id id) Roots() []Root                  { return id.roots }
+func (id id) ReferenceTables() TableList     { return id.references }
 func (id id) Relations() IngressRelationList { return id.relations }
 func (id id) String() string                 { return fmt.Sprintf("%v (%v)", id.startTable, id.relations) }
@@ -22,6 +22,22 @@ import (
 	"strings"
 )
 
+type cycle struct {
+	IngressRelationList
+}
+
+// MaxDepth is the lowest maximum depth of the relations of the cycle (0 for no limit)
+func (c cycle) MaxDepth() uint {
+	result := uint(0)
+	for i := uint(0); i < c.Len(); i++ {
+		depth := c.Relation(i).MaxDepth()
+		if depth > 0 && (result == 0 || depth < result) {
+			result = depth
+		}
+	}
+	return result
+}
+
 type cycleList struct {
 	len   uint
 	slice []IngressRelationList
@@ -33,7 +49,7 @@ func NewCycleList(cycles []IngressRelationList) CycleList {
 }
 
 func (l cycleList) Len() uint            { return l.len }
-func (l cycleList) Cycle(idx uint) Cycle { return l.slice[idx] }
+func (l cycleList) Cycle(idx uint) Cycle { return cycle{l.slice[idx]} }
 func (l cycleList) String() string {
 	switch l.len {
 	case 0:
@@ -0,0 +1,159 @@
+// Copyright (C) 2021 CGI France
+//
+// This file is part of LINO.
+//
+// LINO is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// LINO is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with LINO.  If not, see <http://www.gnu.org/licenses/>.
+
+package id
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Kinds of issues reported by Validate.
+const (
+	UnknownRelation     = "unknown-relation"
+	LookupOnRemoved     = "lookup-on-removed-relation"
+	ChangedRelation     = "changed-relation"
+	MissingPrimaryKey   = "missing-primary-key"
+	UnreachableRelation = "unreachable-relation"
+)
+
+// Validate check the ingress descriptor against the relations and tables of the model.
+func Validate(storage Storage, relReader RelationReader, pkReader PrimaryKeyReader) ([]Issue, *Error) {
+	id, err := storage.Read()
+	if err != nil {
+		return nil, err
+	}
+
+	relations, err := relReader.Read()
+	if err != nil {
+		return nil, err
+	}
+
+	primaryKeys, err := pkReader.Read()
+	if err != nil {
+		return nil, err
+	}
+
+	issues := []Issue{}
+
+	known := map[string]Relation{}
+	for i := uint(0); i < relations.Len(); i++ {
+		known[relations.Relation(i).Name()] = relations.Relation(i)
+	}
+
+	validRelations := []IngressRelation{}
+	for i := uint(0); i < id.Relations().Len(); i++ {
+		rel := id.Relations().Relation(i)
+		modelRel, ok := known[rel.Name()]
+		switch {
+		case !ok && (rel.LookUpChild() || rel.LookUpParent()):
+			issues = append(issues, Issue{IssueError, LookupOnRemoved, rel.Name(), "", fmt.Sprintf("relation %s is followed but does not exist in the relations", rel.Name())})
+		case !ok:
+			issues = append(issues, Issue{IssueWarning, UnknownRelation, rel.Name(), "", fmt.Sprintf("relation %s does not exist in the relations", rel.Name())})
+		case modelRel.Parent().Name() != rel.Parent().Name() || modelRel.Child().Name() != rel.Child().Name():
+			issues = append(issues, Issue{IssueError, ChangedRelation, rel.Name(), "", fmt.Sprintf("relation %s links %s to %s in the relations but %s to %s in the ingress descriptor", rel.Name(), modelRel.Parent().Name(), modelRel.Child().Name(), rel.Parent().Name(), rel.Child().Name())})
+		default:
+			validRelations = append(validRelations, rel)
+		}
+	}
+
+	plan, err := GetPullerPlan(&memoryStorage{NewMultiRootIngressDescriptor(id.Roots(), id.ReferenceTables(), NewIngressRelationList(validRelations))})
+	if err != nil {
+		return nil, err
+	}
+
+	issues = append(issues, validatePrimaryKeys(plan, id.StartTable(), primaryKeys)...)
+	issues = append(issues, validateReachability(plan, validRelations)...)
+
+	sort.SliceStable(issues, func(i, j int) bool {
+		return issues[i].Level == IssueError && issues[j].Level != IssueError
+	})
+
+	return issues, nil
+}
+
+// validatePrimaryKeys report tables without primary key traversed in cycles or pulled by lookups
+func validatePrimaryKeys(plan PullerPlan, startTable Table, primaryKeys map[string][]string) []Issue {
+	issues := []Issue{}
+	reported := newSet()
+
+	for i := uint(0); i < plan.Len(); i++ {
+		cycles := plan.Step(i).Cycles()
+		for c := uint(0); c < cycles.Len(); c++ {
+			cycle := cycles.Cycle(c)
+			for r := uint(0); r < cycle.Len(); r++ {
+				for _, table := range []Table{cycle.Relation(r).Parent(), cycle.Relation(r).Child()} {
+					if len(primaryKeys[table.Name()]) == 0 && !reported.contains(table.Name()) {
+						reported.add(table.Name())
+						issues = append(issues, Issue{IssueError, MissingPrimaryKey, cycle.Relation(r).Name(), table.Name(), fmt.Sprintf("table %s has no primary key but is traversed in a cycle", table.Name())})
+					}
+				}
+			}
+		}
+	}
+
+	for i := uint(0); i < plan.Len(); i++ {
+		step := plan.Step(i)
+		if step.PreviousStep() == 0 {
+			continue
+		}
+		table := step.Entry()
+		if table.Name() != startTable.Name() && len(primaryKeys[table.Name()]) == 0 && !reported.contains(table.Name()) {
+			reported.add(table.Name())
+			issues = append(issues, Issue{IssueWarning, MissingPrimaryKey, step.Following().Name(), table.Name(), fmt.Sprintf("table %s has no primary key but is pulled by a lookup", table.Name())})
+		}
+	}
+
+	return issues
+}
+
+// validateReachability report followed relations that can not be reached from the start table
+func validateReachability(plan PullerPlan, relations []IngressRelation) []Issue {
+	issues := []Issue{}
+
+	reached := newSet()
+	for i := uint(0); i < plan.Len(); i++ {
+		step := plan.Step(i)
+		if step.Following() != nil {
+			reached.add(step.Following().Name())
+		}
+		for r := uint(0); r < step.Relations().Len(); r++ {
+			reached.add(step.Relations().Relation(r).Name())
+		}
+	}
+
+	for _, rel := range relations {
+		if (rel.LookUpChild() || rel.LookUpParent()) && !reached.contains(rel.Name()) {
+			issues = append(issues, Issue{IssueWarning, UnreachableRelation, rel.Name(), "", fmt.Sprintf("relation %s is followed but can not be reached from the start table", rel.Name())})
+		}
+	}
+
+	return issues
+}
+
+// memoryStorage is a read only storage of an ingress descriptor
+type memoryStorage struct {
+	id IngressDescriptor
+}
+
+func (s *memoryStorage) Store(id IngressDescriptor) *Error {
+	return &Error{Description: "read only storage"}
+}
+
+func (s *memoryStorage) Read() (IngressDescriptor, *Error) {
+	return s.id, nil
+}
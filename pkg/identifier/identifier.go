@@ -0,0 +1,40 @@
+// Copyright (C) 2021 CGI France
+//
+// This file is part of LINO.
+//
+// LINO is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// LINO is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with LINO.  If not, see <http://www.gnu.org/licenses/>.
+
+package identifier
+
+import (
+	"regexp"
+	"strings"
+)
+
+// identifier matches an unquoted relation, table, schema or column name.
+// Quoted identifiers are rejected, a mixed-case name is accepted but not quoted in SQL, so the database folds its case.
+var identifier = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_$#]*$`)
+
+// Valid returns true if name is an unquoted identifier.
+func Valid(name string) bool {
+	return identifier.MatchString(name)
+}
+
+// ValidTableName returns true if name is an identifier, optionally prefixed by a schema identifier.
+func ValidTableName(name string) bool {
+	if i := strings.Index(name, "."); i >= 0 {
+		return Valid(name[:i]) && Valid(name[i+1:])
+	}
+	return Valid(name)
+}
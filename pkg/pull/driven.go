@@ -17,14 +17,21 @@
 
 package pull
 
+import "github.com/cgi-fr/lino/pkg/dataconnector"
+
 // RowExporter receives pulled rows one by one.
 type RowExporter interface {
 	Export(Row) *Error
 }
 
+// StatsWriter write the statistics of a pull run.
+type StatsWriter interface {
+	Write(Stats) *Error
+}
+
 // DataSourceFactory exposes methods to create new datasources.
 type DataSourceFactory interface {
-	New(url string, schema string) DataSource
+	New(url string, schema string, options dataconnector.Options) DataSource
 }
 
 // DataSource to read in the pull process.
@@ -66,6 +73,7 @@ func (r OneEmptyRowReader) Error() *Error { return nil }
 // TraceListener receives diagnostic trace
 type TraceListener interface {
 	TraceStep(Step, Filter) TraceListener
+	TraceMaxDepth(Step, Cycle) TraceListener
 }
 
 // NoTraceListener default implementation do nothing.
@@ -73,3 +81,6 @@ type NoTraceListener struct{}
 
 // TraceStep catch Step event.
 func (t NoTraceListener) TraceStep(s Step, filter Filter) TraceListener { return t }
+
+// TraceMaxDepth catch the end of a cycle traversal on maximum depth.
+func (t NoTraceListener) TraceMaxDepth(s Step, cycle Cycle) TraceListener { return t }
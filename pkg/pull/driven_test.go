@@ -96,6 +96,23 @@ func (ds *MemoryDataSource) RowReader(source pull.Table, filter pull.Filter) (pu
 	return &MemoryDataIterator{result, nil}, nil
 }
 
+// FlakyDataSource fails the first queries with the given errors.
+type FlakyDataSource struct {
+	MemoryDataSource
+	failures []*pull.Error
+	queries  int
+}
+
+func (ds *FlakyDataSource) RowReader(source pull.Table, filter pull.Filter) (pull.RowReader, *pull.Error) {
+	ds.queries++
+	if len(ds.failures) > 0 {
+		err := ds.failures[0]
+		ds.failures = ds.failures[1:]
+		return nil, err
+	}
+	return ds.MemoryDataSource.RowReader(source, filter)
+}
+
 // MemoryRowExporter mock RowExporter.
 type MemoryRowExporter struct {
 	rows []pull.Row
@@ -19,28 +19,133 @@ package pull
 
 import (
 	"fmt"
+	"strings"
+	"time"
 
+	"github.com/cgi-fr/lino/pkg/retry"
 	"github.com/rs/zerolog/log"
 )
 
-// Pull data from source following the given puller plan.
-func Pull(plan Plan, filters RowReader, source DataSource, exporter RowExporter, diagnostic TraceListener) *Error {
+// Pull data from source following the given puller plan, queries failing with a retryable error are executed again (errors while reading their rows are not retried).
+// If refs is true, a parent row already exported in a previous document is replaced by a {"$ref": {"table": ..., "key": ...}} marker.
+func Pull(plan Plan, filters RowReader, source DataSource, exporter RowExporter, diagnostic TraceListener, policy retry.Policy, refs bool) (Stats, *Error) {
+	start := time.Now()
+	stats := NewStats()
+
 	if err := source.Open(); err != nil {
-		return err
+		return stats, err
 	}
 
 	defer source.Close()
 
-	e := puller{source}
-	if err := e.pull(plan, filters, exporter.Export, diagnostic); err != nil {
-		return err
+	references := map[string]bool{}
+	for _, table := range plan.References() {
+		references[table.Name()] = true
 	}
 
-	return nil
+	e := puller{
+		datasource: source,
+		retry:      policy,
+		stats:      stats,
+		references: references,
+		refs:       refs,
+		exported:   map[string]bool{},
+		pending:    map[string]bool{},
+	}
+	err := e.pull(plan, filters, exporter.Export, diagnostic)
+	stats.Duration = time.Since(start)
+
+	return stats, err
+}
+
+// PullRoots pull data following each plan in sequence and export the documents of a plan with the exporter of the same index.
+// A root row already exported by a previous plan is skipped and, if refs is true, a row exported by a previous plan
+// as a root or a parent is replaced by a {"$ref": {"table": ..., "key": ...}} marker, so rows of tables shared by several roots are exported once.
+func PullRoots(plans []Plan, exporters []RowExporter, source DataSource, diagnostic TraceListener, policy retry.Policy, refs bool) (Stats, *Error) {
+	start := time.Now()
+	stats := NewStats()
+
+	if err := source.Open(); err != nil {
+		return stats, err
+	}
+
+	defer source.Close()
+
+	e := puller{
+		datasource: source,
+		retry:      policy,
+		stats:      stats,
+		refs:       refs,
+		exported:   map[string]bool{},
+		pending:    map[string]bool{},
+		roots:      map[string]bool{},
+	}
+	for i, plan := range plans {
+		e.references = map[string]bool{}
+		for _, table := range plan.References() {
+			e.references[table.Name()] = true
+		}
+
+		if err := e.pull(plan, NewOneEmptyRowReader(), exporters[i].Export, diagnostic); err != nil {
+			stats.Duration = time.Since(start)
+			return stats, err
+		}
+	}
+	stats.Duration = time.Since(start)
+
+	return stats, nil
+}
+
+// PullReferences pull all rows of each reference table, queries failing with a retryable error are executed again.
+func PullReferences(tables []Table, source DataSource, exporter RowExporter, policy retry.Policy) (Stats, *Error) {
+	start := time.Now()
+	stats := NewStats()
+
+	if err := source.Open(); err != nil {
+		return stats, err
+	}
+
+	defer source.Close()
+
+	e := puller{datasource: source, retry: policy, stats: stats, references: map[string]bool{}}
+	for _, table := range tables {
+		log.Info().Msg(fmt.Sprintf("pull: reference table %v", table))
+
+		rowIterator, err := e.rowReader(table, NewFilter(0, Row{}, ""))
+		if err != nil {
+			stats.Duration = time.Since(start)
+			return stats, err
+		}
+
+		tableStats := stats.Table(table.Name())
+		for e.next(tableStats, rowIterator) {
+			tableStats.Read++
+			if err := exporter.Export(rowIterator.Value()); err != nil {
+				stats.Duration = time.Since(start)
+				return stats, err
+			}
+			tableStats.Exported++
+		}
+
+		if rowIterator.Error() != nil {
+			stats.Duration = time.Since(start)
+			return stats, rowIterator.Error()
+		}
+	}
+	stats.Duration = time.Since(start)
+
+	return stats, nil
 }
 
 type puller struct {
 	datasource DataSource
+	retry      retry.Policy
+	stats      Stats
+	references map[string]bool
+	refs       bool
+	exported   map[string]bool // keys of the parent rows exported in previous documents
+	pending    map[string]bool // keys of the parent rows of the current document
+	roots      map[string]bool // keys of the root rows exported by previous plans, nil if there is a single plan
 }
 
 func (e puller) pull(plan Plan, filters RowReader, export func(Row) *Error, diagnostic TraceListener) *Error {
@@ -48,7 +153,13 @@ func (e puller) pull(plan Plan, filters RowReader, export func(Row) *Error, diag
 		fileFilter := filters.Value()
 
 		initFilter := filter{plan.InitFilter().Limit(), fileFilter.Update(plan.InitFilter().Values()), plan.InitFilter().Where()}
-		if err := e.pullStep(plan.Steps().Step(0), initFilter, export, diagnostic); err != nil {
+		if err := e.pullStep(plan.Steps().Step(0), initFilter, true, func(r Row) *Error {
+			if err := export(r); err != nil {
+				return err
+			}
+			e.commitShared()
+			return nil
+		}, diagnostic); err != nil {
 			return err
 		}
 	}
@@ -56,8 +167,8 @@ func (e puller) pull(plan Plan, filters RowReader, export func(Row) *Error, diag
 	return filters.Error()
 }
 
-func (e puller) pullStep(step Step, filter Filter, export func(Row) *Error, diagnostic TraceListener) *Error {
-	rowIterator, err := e.datasource.RowReader(step.Entry(), filter)
+func (e puller) pullStep(step Step, filter Filter, root bool, export func(Row) *Error, diagnostic TraceListener) *Error {
+	rowIterator, err := e.rowReader(step.Entry(), filter)
 	if err != nil {
 		return err
 	}
@@ -65,17 +176,34 @@ func (e puller) pullStep(step Step, filter Filter, export func(Row) *Error, diag
 
 	log.Info().Msg(fmt.Sprintf("pull: from %v with filter %v", step.Entry(), filter))
 
+	tableStats := e.stats.Table(step.Entry().Name())
+
 	i := 0
-	for rowIterator.Next() {
+	for e.next(tableStats, rowIterator) {
 		row := rowIterator.Value()
+		tableStats.Read++
 		i++
 		log.Trace().Msg(fmt.Sprintf("pull: process row number %v", i))
 
+		if root && e.roots != nil {
+			if key, ok := primaryKey(step.Entry(), row); ok {
+				if e.roots[keyString(step.Entry(), key)] {
+					log.Trace().Msg(fmt.Sprintf("pull: row #%v, %v already exported by a previous root", i, key))
+					continue
+				}
+				e.roots[keyString(step.Entry(), key)] = true
+				if e.refs {
+					// a later root referencing this row as a parent gets a marker
+					e.pending[keyString(step.Entry(), key)] = true
+				}
+			}
+		}
+
 		allRows := map[string][]Row{}
 		allRows[step.Entry().Name()] = []Row{row}
 
 		if step.Relations().Len() > 0 {
-			if err := e.exhaust(step, allRows); err != nil {
+			if err := e.exhaust(step, allRows, diagnostic); err != nil {
 				return err
 			}
 		}
@@ -83,6 +211,10 @@ func (e puller) pullStep(step Step, filter Filter, export func(Row) *Error, diag
 		for stepIdx := uint(0); stepIdx < step.NextSteps().Len(); stepIdx++ {
 			nextStep := step.NextSteps().Step(stepIdx)
 			rel := nextStep.Follow()
+			if e.references[nextStep.Entry().Name()] {
+				log.Trace().Msg(fmt.Sprintf("pull: row #%v, skip %v leading to reference table %v", i, rel, nextStep.Entry().Name()))
+				continue
+			}
 			fromTable := findFromTable(rel, step.Relations(), step.Entry())
 			directionParent := rel.Child().Name() == fromTable.Name()
 			log.Trace().Msg(fmt.Sprintf("pull: row #%v, following %v from %v", i, rel, fromTable.Name()))
@@ -93,7 +225,14 @@ func (e puller) pullStep(step Step, filter Filter, export func(Row) *Error, diag
 				if relatedToRow[rel.Name()] == nil {
 					relatedToRow[rel.Name()] = []Row{}
 				}
-				if err := e.pullStep(nextStep, nextFilter, func(r Row) *Error {
+				if directionParent && e.refs {
+					if key, ok := e.sharedKey(nextStep.Entry(), nextFilter.Values()); ok && e.exported[keyString(nextStep.Entry(), key)] {
+						log.Trace().Msg(fmt.Sprintf("pull: row #%v, %v already exported", i, key))
+						relatedToRow[rel.Name()] = refMarker(nextStep.Entry(), key)
+						continue
+					}
+				}
+				if err := e.pullStep(nextStep, nextFilter, false, func(r Row) *Error {
 					if !directionParent {
 						rowArray, ok := relatedToRow[rel.Name()].([]Row)
 						if !ok {
@@ -102,7 +241,7 @@ func (e puller) pullStep(step Step, filter Filter, export func(Row) *Error, diag
 						rowArray = append(rowArray, r)
 						relatedToRow[rel.Name()] = rowArray
 					} else {
-						relatedToRow[rel.Name()] = r
+						relatedToRow[rel.Name()] = e.share(nextStep.Entry(), r)
 					}
 					return nil
 				}, diagnostic); err != nil {
@@ -113,6 +252,7 @@ func (e puller) pullStep(step Step, filter Filter, export func(Row) *Error, diag
 		if err := export(row); err != nil {
 			return err
 		}
+		tableStats.Exported++
 	}
 
 	if rowIterator.Error() != nil {
@@ -121,72 +261,192 @@ func (e puller) pullStep(step Step, filter Filter, export func(Row) *Error, diag
 	return nil
 }
 
-func (e puller) exhaust(step Step, allRows map[string][]Row) *Error {
+func (e puller) exhaust(step Step, allRows map[string][]Row, diagnostic TraceListener) *Error {
 	cycles := step.Cycles()
 
 	log.Trace().Msg(fmt.Sprintf("pull: %v cycle(s) to traverse", cycles.Len()))
 
-	fromTable := step.Entry()
 	for cycleIdx := uint(0); cycleIdx < step.Cycles().Len(); cycleIdx++ {
 		cycle := step.Cycles().Cycle(cycleIdx)
 		log.Trace().Msg(fmt.Sprintf("pull: traversing cycle %v", cycle))
-		for relationIdx := uint(0); relationIdx < cycle.Len(); relationIdx++ {
-			relation := cycle.Relation(relationIdx)
-			fromRows := allRows[fromTable.Name()]
-			log.Trace().Msg(fmt.Sprintf("pull: following relation %v has %v source row(s)", relation, len(fromRows)))
-			for i, fromRow := range fromRows {
-				log.Trace().Msg(fmt.Sprintf("pull: following relation %v on row #%v (%v)", relation, i, fromRow))
-				toTable := relation.OppositeOf(fromTable.Name())
-				nextFilter := relatedTo(toTable, relation, fromRow)
-				log.Trace().Msg(fmt.Sprintf("pull: following relation %v on row #%v with filter %v", relation, i, nextFilter))
-				directionParent := toTable.Name() == relation.Parent().Name()
-				rows, err := e.read(toTable, nextFilter)
-				if err != nil {
-					return err
-				}
 
-				log.Trace().Msg(fmt.Sprintf("pull: following relation %v on row #%v returned %v related row(s)", relation, i, len(rows)))
-				rows = removeDuplicate(toTable.PrimaryKey(), rows, allRows[toTable.Name()])
-				log.Trace().Msg(fmt.Sprintf("pull: following relation %v on row #%v returned %v unseen row(s)", relation, i, len(rows)))
+		fromRows := allRows[step.Entry().Name()]
+		for depth := uint(1); len(fromRows) > 0; depth++ {
+			if cycle.MaxDepth() > 0 && depth > cycle.MaxDepth() {
+				log.Warn().Msg(fmt.Sprintf("pull: maximum depth %v reached, stop traversing cycle %v", cycle.MaxDepth(), cycle))
+				diagnostic.TraceMaxDepth(step, cycle)
+				break
+			}
 
-				if len(rows) == 0 {
-					log.Trace().Msg(fmt.Sprintf("pull: stop traversing cycle %v", cycle))
-					break
-				}
+			var err *Error
+			fromRows, err = e.traverse(step.Entry(), cycle, fromRows, allRows)
+			if err != nil {
+				return err
+			}
+		}
+	}
 
-				if !directionParent {
-					if fromRow[relation.Name()] == nil {
-						fromRow[relation.Name()] = []Row{}
-					}
-					rowArray, ok := fromRow[relation.Name()].([]Row)
-					if !ok {
-						return &Error{Description: fmt.Sprintf("table %v has a column whose name collides with the relation name %v", fromTable.Name(), relation.Name())}
-					}
-					rowArray = append(rowArray, rows...)
-					fromRow[relation.Name()] = rowArray
-				} else {
-					fromRow[relation.Name()] = rows[0]
-				}
+	return nil
+}
+
+// traverse follows once the relations of the cycle from the given rows and returns the unseen rows pulled back in the entry table.
+func (e puller) traverse(entry Table, cycle Cycle, fromRows []Row, allRows map[string][]Row) ([]Row, *Error) {
+	fromTable := entry
+	for relationIdx := uint(0); relationIdx < cycle.Len(); relationIdx++ {
+		relation := cycle.Relation(relationIdx)
+		log.Trace().Msg(fmt.Sprintf("pull: following relation %v has %v source row(s)", relation, len(fromRows)))
+		toTable := relation.OppositeOf(fromTable.Name())
+		if e.references[toTable.Name()] {
+			log.Trace().Msg(fmt.Sprintf("pull: stop traversing cycle %v at reference table %v", cycle, toTable.Name()))
+			return nil, nil
+		}
+		directionParent := toTable.Name() == relation.Parent().Name()
+		toRows := []Row{}
+		for i, fromRow := range fromRows {
+			log.Trace().Msg(fmt.Sprintf("pull: following relation %v on row #%v (%v)", relation, i, fromRow))
+			nextFilter := relatedTo(toTable, relation, fromRow)
+			log.Trace().Msg(fmt.Sprintf("pull: following relation %v on row #%v with filter %v", relation, i, nextFilter))
+			rows, err := e.read(toTable, nextFilter)
+			if err != nil {
+				return nil, err
+			}
+
+			log.Trace().Msg(fmt.Sprintf("pull: following relation %v on row #%v returned %v related row(s)", relation, i, len(rows)))
+			rows = removeDuplicate(toTable.PrimaryKey(), rows, allRows[toTable.Name()])
+			log.Trace().Msg(fmt.Sprintf("pull: following relation %v on row #%v returned %v unseen row(s)", relation, i, len(rows)))
 
-				allRows[toTable.Name()] = append(allRows[toTable.Name()], rows...)
+			// the other rows of this depth may still lead to unseen rows, the traversal stops below when none of them does
+			if len(rows) == 0 {
+				continue
+			}
 
-				fromTable = toTable
+			if !directionParent {
+				if fromRow[relation.Name()] == nil {
+					fromRow[relation.Name()] = []Row{}
+				}
+				rowArray, ok := fromRow[relation.Name()].([]Row)
+				if !ok {
+					return nil, &Error{Description: fmt.Sprintf("table %v has a column whose name collides with the relation name %v", fromTable.Name(), relation.Name())}
+				}
+				rowArray = append(rowArray, rows...)
+				fromRow[relation.Name()] = rowArray
+			} else {
+				fromRow[relation.Name()] = rows[0]
 			}
+
+			allRows[toTable.Name()] = append(allRows[toTable.Name()], rows...)
+			e.stats.Table(toTable.Name()).Exported += uint(len(rows))
+			toRows = append(toRows, rows...)
 		}
+
+		if len(toRows) == 0 {
+			log.Trace().Msg(fmt.Sprintf("pull: stop traversing cycle %v", cycle))
+			return nil, nil
+		}
+
+		fromTable = toTable
+		fromRows = toRows
 	}
 
-	return nil
+	if fromTable.Name() != entry.Name() {
+		return nil, nil
+	}
+
+	return fromRows, nil
 }
 
-func (e puller) read(t Table, f Filter) ([]Row, *Error) {
+// sharedKey returns the primary key of table found in values, if refs are enabled and the whole key is known.
+func (e puller) sharedKey(table Table, values Row) (Row, bool) {
+	if !e.refs {
+		return nil, false
+	}
+	return primaryKey(table, values)
+}
+
+// primaryKey returns the primary key of table found in values, if the whole key is known.
+func primaryKey(table Table, values Row) (Row, bool) {
+	if len(table.PrimaryKey()) == 0 {
+		return nil, false
+	}
+	key := Row{}
+	for _, pk := range table.PrimaryKey() {
+		value, ok := values[pk]
+		if !ok {
+			return nil, false
+		}
+		key[pk] = value
+	}
+	return key, true
+}
+
+// share returns a marker if the parent row was exported in a previous document, else the row itself.
+func (e puller) share(table Table, row Row) Row {
+	key, ok := e.sharedKey(table, row)
+	if !ok {
+		return row
+	}
+	if e.exported[keyString(table, key)] {
+		return refMarker(table, key)
+	}
+	e.pending[keyString(table, key)] = true
+	return row
+}
+
+// commitShared marks the parent rows of the document as exported.
+func (e puller) commitShared() {
+	for key := range e.pending {
+		e.exported[key] = true
+		delete(e.pending, key)
+	}
+}
+
+func keyString(table Table, key Row) string {
+	sb := &strings.Builder{}
+	sb.WriteString(table.Name())
+	for _, pk := range table.PrimaryKey() {
+		fmt.Fprintf(sb, "\x00%v", key[pk])
+	}
+	return sb.String()
+}
+
+func refMarker(table Table, key Row) Row {
+	return Row{RefKey: Row{"table": table.Name(), "key": key}}
+}
+
+// rowReader query the datasource, retrying on retryable error
+func (e puller) rowReader(t Table, f Filter) (RowReader, *Error) {
+	tableStats := e.stats.Table(t.Name())
+	start := time.Now()
+	defer func() { tableStats.Duration += time.Since(start) }()
+
 	iter, err := e.datasource.RowReader(t, f)
+	for attempt := uint(1); err != nil && e.retry.IsRetryable(err.Code) && attempt <= e.retry.Attempts; attempt++ {
+		log.Warn().Msg(fmt.Sprintf("pull: retryable error (attempt %d/%d) : %s", attempt, e.retry.Attempts, err.Error()))
+		time.Sleep(e.retry.Delay(attempt))
+		iter, err = e.datasource.RowReader(t, f)
+	}
+	return iter, err
+}
+
+// next reads the next row and add the time spent to the table counters
+func (e puller) next(tableStats *TableStats, iter RowReader) bool {
+	start := time.Now()
+	defer func() { tableStats.Duration += time.Since(start) }()
+
+	return iter.Next()
+}
+
+func (e puller) read(t Table, f Filter) ([]Row, *Error) {
+	iter, err := e.rowReader(t, f)
 	if err != nil {
 		return nil, err
 	}
+	tableStats := e.stats.Table(t.Name())
 	result := []Row{}
-	for iter.Next() {
+	for e.next(tableStats, iter) {
 		row := iter.Value()
 		result = append(result, row)
+		tableStats.Read++
 	}
 	if iter.Error() != nil {
 		return nil, iter.Error()
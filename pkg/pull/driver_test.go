@@ -23,7 +23,9 @@ import (
 	"testing"
 
 	"github.com/cgi-fr/lino/pkg/pull"
+	"github.com/cgi-fr/lino/pkg/retry"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
 func makeTable(name string) pull.Table {
@@ -100,10 +102,13 @@ func TestPull1(t *testing.T) {
 	}
 	datasource := &MemoryDataSource{source}
 
-	err := pull.Pull(plan, pull.NewOneEmptyRowReader(), datasource, exporter, pull.NoTraceListener{})
+	stats, err := pull.Pull(plan, pull.NewOneEmptyRowReader(), datasource, exporter, pull.NoTraceListener{}, retry.None, false)
 
 	assert.Nil(t, err)
 	assert.Len(t, exporter.rows, int(plan.InitFilter().Limit()))
+	assert.Equal(t, uint(2), stats.Tables[A.Name()].Read)
+	assert.Equal(t, uint(2), stats.Tables[A.Name()].Exported)
+	assert.Equal(t, uint(2), stats.Tables[C.Name()].Exported)
 
 	B1 := assertFollowedChild(t, source[A.Name()][0], exporter.rows[0], AB)
 	B2 := assertFollowedChild(t, source[A.Name()][1], exporter.rows[1], AB)
@@ -153,7 +158,7 @@ func TestPull2(t *testing.T) {
 	}
 	datasource := &MemoryDataSource{source}
 
-	err := pull.Pull(plan, pull.NewOneEmptyRowReader(), datasource, exporter, pull.NoTraceListener{})
+	_, err := pull.Pull(plan, pull.NewOneEmptyRowReader(), datasource, exporter, pull.NoTraceListener{}, retry.None, false)
 
 	assert.Nil(t, err)
 	assert.Len(t, exporter.rows, int(plan.InitFilter().Limit()))
@@ -219,7 +224,7 @@ func TestPull3(t *testing.T) {
 	}
 	datasource := &MemoryDataSource{source}
 
-	err := pull.Pull(plan, pull.NewOneEmptyRowReader(), datasource, exporter, pull.NoTraceListener{})
+	_, err := pull.Pull(plan, pull.NewOneEmptyRowReader(), datasource, exporter, pull.NoTraceListener{}, retry.None, false)
 
 	assert.Nil(t, err)
 	assert.Len(t, exporter.rows, int(plan.InitFilter().Limit()))
@@ -250,7 +255,7 @@ func TestPull4(t *testing.T) {
 	AB := makeRel(A, B)
 	BA := makeRel(B, A)
 
-	cycle1 := pull.NewRelationList([]pull.Relation{AB, BA})
+	cycle1 := pull.NewCycle([]pull.Relation{AB, BA}, 0)
 	step1 := pull.NewStep(1, A, nil, cycle1, pull.NewCycleList([]pull.Cycle{cycle1}), pull.NewStepList([]pull.Step{}))
 
 	plan := pull.NewPlan(
@@ -272,7 +277,7 @@ func TestPull4(t *testing.T) {
 	}
 	datasource := &MemoryDataSource{source}
 
-	err := pull.Pull(plan, pull.NewOneEmptyRowReader(), datasource, exporter, pull.NoTraceListener{})
+	_, err := pull.Pull(plan, pull.NewOneEmptyRowReader(), datasource, exporter, pull.NoTraceListener{}, retry.None, false)
 
 	/* Expected result
 	map[
@@ -293,3 +298,269 @@ func TestPull4(t *testing.T) {
 	assert.Equal(t, source[B.Name()][0], B1[0])
 	assert.Equal(t, source[B.Name()][1], B2[0])
 }
+
+func TestPullMaxDepth(t *testing.T) {
+	A := makeTable("A")
+	B := makeTable("B")
+
+	AB := makeRel(A, B)
+	BA := makeRel(B, A)
+
+	source := map[string][]pull.Row{
+		A.Name(): {
+			{A.PrimaryKey()[0]: 1, AB.ParentKey()[0]: 1},
+			{A.PrimaryKey()[0]: 2, AB.ParentKey()[0]: 2},
+			{A.PrimaryKey()[0]: 3, AB.ParentKey()[0]: 3},
+			{A.PrimaryKey()[0]: 4, AB.ParentKey()[0]: 4},
+		},
+		B.Name(): {
+			{B.PrimaryKey()[0]: 1, BA.ParentKey()[0]: 2},
+			{B.PrimaryKey()[0]: 2, BA.ParentKey()[0]: 3},
+			{B.PrimaryKey()[0]: 3, BA.ParentKey()[0]: 4},
+			{B.PrimaryKey()[0]: 4, BA.ParentKey()[0]: 1},
+		},
+	}
+
+	tests := []struct {
+		maxDepth uint
+		pulledA  uint
+		pulledB  uint
+		traced   bool
+	}{
+		{0, 4, 4, false},
+		{2, 3, 2, true},
+	}
+
+	for _, tt := range tests {
+		cycle := pull.NewCycle([]pull.Relation{AB, BA}, tt.maxDepth)
+		step1 := pull.NewStep(1, A, nil, cycle, pull.NewCycleList([]pull.Cycle{cycle}), pull.NewStepList([]pull.Step{}))
+		plan := pull.NewPlan(
+			pull.NewFilter(1, pull.Row{A.PrimaryKey()[0]: 1}, ""),
+			pull.NewStepList([]pull.Step{step1}),
+		)
+
+		tracer := &pull.MockTraceListener{}
+		tracer.On("TraceStep", mock.Anything, mock.Anything).Return(tracer)
+		tracer.On("TraceMaxDepth", step1, cycle).Return(tracer)
+
+		stats, err := pull.Pull(plan, pull.NewOneEmptyRowReader(), &MemoryDataSource{source}, &MemoryRowExporter{[]pull.Row{}}, tracer, retry.None, false)
+
+		assert.Nil(t, err)
+		assert.Equal(t, tt.pulledA, stats.Tables[A.Name()].Exported)
+		assert.Equal(t, tt.pulledB, stats.Tables[B.Name()].Exported)
+		if tt.traced {
+			tracer.AssertCalled(t, "TraceMaxDepth", step1, cycle)
+		} else {
+			tracer.AssertNotCalled(t, "TraceMaxDepth", step1, cycle)
+		}
+	}
+}
+
+// TestPullCycleSkipsSeenRows checks that a row whose related rows are already pulled does not stop the traversal of the other rows of the same depth.
+func TestPullCycleSkipsSeenRows(t *testing.T) {
+	A := pull.NewTable("A", []string{"A_ID"})
+	B := pull.NewTable("B", []string{"B_ID"})
+
+	AB := pull.NewRelation("A->B", A, B, []string{"A_ID"}, []string{"A_ID"})
+	BA := pull.NewRelation("B->A", A, B, []string{"A_ID"}, []string{"NEXT_ID"})
+
+	source := map[string][]pull.Row{
+		A.Name(): {
+			{"A_ID": 1},
+			{"A_ID": 2},
+			{"A_ID": 3},
+		},
+		B.Name(): {
+			{"B_ID": 1, "A_ID": 1, "NEXT_ID": 1},
+			{"B_ID": 2, "A_ID": 1, "NEXT_ID": 2},
+			{"B_ID": 3, "A_ID": 2, "NEXT_ID": 3},
+		},
+	}
+
+	cycle := pull.NewCycle([]pull.Relation{AB, BA}, 0)
+	step1 := pull.NewStep(1, A, nil, cycle, pull.NewCycleList([]pull.Cycle{cycle}), pull.NewStepList([]pull.Step{}))
+	plan := pull.NewPlan(
+		pull.NewFilter(1, pull.Row{"A_ID": 1}, ""),
+		pull.NewStepList([]pull.Step{step1}),
+	)
+
+	// B 1 leads back to A 1 that is already pulled, B 2 must still be followed to A 2, then A 2 to B 3 and A 3
+	stats, err := pull.Pull(plan, pull.NewOneEmptyRowReader(), &MemoryDataSource{source}, &MemoryRowExporter{[]pull.Row{}}, pull.NoTraceListener{}, retry.None, false)
+
+	assert.Nil(t, err)
+	assert.Equal(t, uint(3), stats.Tables[A.Name()].Exported)
+	assert.Equal(t, uint(3), stats.Tables[B.Name()].Exported)
+}
+
+func TestPullReferences(t *testing.T) {
+	exporter := &MemoryRowExporter{[]pull.Row{}}
+
+	A := makeTable("A")
+	B := makeTable("B")
+	C := makeTable("C")
+
+	AB := makeRel(A, B)
+	AC := makeRel(A, C)
+
+	step3 := pull.NewStep(3, C, AC, pull.NewRelationList([]pull.Relation{}), pull.NewCycleList([]pull.Cycle{}), pull.NewStepList([]pull.Step{}))
+	step2 := pull.NewStep(2, B, AB, pull.NewRelationList([]pull.Relation{}), pull.NewCycleList([]pull.Cycle{}), pull.NewStepList([]pull.Step{}))
+	step1 := pull.NewStep(1, A, nil, pull.NewRelationList([]pull.Relation{}), pull.NewCycleList([]pull.Cycle{}), pull.NewStepList([]pull.Step{step2, step3}))
+
+	plan := pull.NewPlanWithReferences(
+		pull.NewFilter(1, pull.Row{}, ""),
+		pull.NewStepList([]pull.Step{step1, step2, step3}),
+		[]pull.Table{C},
+	)
+
+	source := map[string][]pull.Row{
+		A.Name(): {
+			{A.PrimaryKey()[0]: 10, AB.ParentKey()[0]: 20, AC.ParentKey()[0]: 30},
+		},
+		B.Name(): {
+			{B.PrimaryKey()[0]: 20},
+			{B.PrimaryKey()[0]: 21},
+		},
+		C.Name(): {
+			{C.PrimaryKey()[0]: 30},
+			{C.PrimaryKey()[0]: 31},
+		},
+	}
+	datasource := &MemoryDataSource{source}
+
+	stats, err := pull.Pull(plan, pull.NewOneEmptyRowReader(), datasource, exporter, pull.NoTraceListener{}, retry.None, false)
+
+	assert.Nil(t, err)
+	assert.Len(t, exporter.rows, 1)
+	assert.Nil(t, exporter.rows[0][AC.Name()])
+	assert.Len(t, exporter.rows[0][AB.Name()], 1)
+	assert.Nil(t, stats.Tables[C.Name()])
+
+	references := &MemoryRowExporter{[]pull.Row{}}
+
+	stats, err = pull.PullReferences(plan.References(), datasource, references, retry.None)
+
+	assert.Nil(t, err)
+	assert.Equal(t, source[C.Name()], references.rows)
+	assert.Equal(t, uint(2), stats.Tables[C.Name()].Exported)
+}
+
+func TestPullRefs(t *testing.T) {
+	exporter := &MemoryRowExporter{[]pull.Row{}}
+
+	A := makeTable("A")
+	S := makeTable("S")
+
+	// each row of A has a parent in S
+	AS := pull.NewRelation("A->S", S, A, []string{S.PrimaryKey()[0]}, []string{S.PrimaryKey()[0]})
+
+	step2 := pull.NewStep(2, S, AS, pull.NewRelationList([]pull.Relation{}), pull.NewCycleList([]pull.Cycle{}), pull.NewStepList([]pull.Step{}))
+	step1 := pull.NewStep(1, A, nil, pull.NewRelationList([]pull.Relation{}), pull.NewCycleList([]pull.Cycle{}), pull.NewStepList([]pull.Step{step2}))
+
+	plan := pull.NewPlan(
+		pull.NewFilter(0, pull.Row{}, ""),
+		pull.NewStepList([]pull.Step{step1, step2}),
+	)
+
+	source := map[string][]pull.Row{
+		A.Name(): {
+			{A.PrimaryKey()[0]: 10, S.PrimaryKey()[0]: 1},
+			{A.PrimaryKey()[0]: 11, S.PrimaryKey()[0]: 1},
+			{A.PrimaryKey()[0]: 12, S.PrimaryKey()[0]: 2},
+		},
+		S.Name(): {
+			{S.PrimaryKey()[0]: 1},
+			{S.PrimaryKey()[0]: 2},
+		},
+	}
+	datasource := &MemoryDataSource{source}
+
+	stats, err := pull.Pull(plan, pull.NewOneEmptyRowReader(), datasource, exporter, pull.NoTraceListener{}, retry.None, true)
+
+	assert.Nil(t, err)
+	assert.Len(t, exporter.rows, 3)
+	assert.Equal(t, source[S.Name()][0], exporter.rows[0][AS.Name()])
+	assert.Equal(t, pull.Row{pull.RefKey: pull.Row{"table": S.Name(), "key": pull.Row{S.PrimaryKey()[0]: 1}}}, exporter.rows[1][AS.Name()])
+	assert.Equal(t, source[S.Name()][1], exporter.rows[2][AS.Name()])
+	assert.Equal(t, uint(2), stats.Tables[S.Name()].Read)
+}
+
+func TestPullRetry(t *testing.T) {
+	exporter := &MemoryRowExporter{[]pull.Row{}}
+
+	A := makeTable("A")
+
+	step1 := pull.NewStep(1, A, nil, pull.NewRelationList([]pull.Relation{}), pull.NewCycleList([]pull.Cycle{}), pull.NewStepList([]pull.Step{}))
+
+	plan := pull.NewPlan(
+		pull.NewFilter(0, pull.Row{}, ""),
+		pull.NewStepList([]pull.Step{step1}),
+	)
+
+	source := map[string][]pull.Row{
+		A.Name(): {
+			{A.PrimaryKey()[0]: 10},
+			{A.PrimaryKey()[0]: 11},
+		},
+	}
+	deadlock := &pull.Error{Description: "ORA-00060: deadlock detected while waiting for resource", Code: "ORA-00060"}
+
+	datasource := &FlakyDataSource{MemoryDataSource{source}, []*pull.Error{deadlock, deadlock}, 0}
+	stats, err := pull.Pull(plan, pull.NewOneEmptyRowReader(), datasource, exporter, pull.NoTraceListener{}, retry.NewPolicy(2, 0, retry.DefaultCodes), false)
+
+	assert.Nil(t, err)
+	assert.Equal(t, 3, datasource.queries)
+	assert.Len(t, exporter.rows, 2)
+	assert.Equal(t, uint(2), stats.Tables[A.Name()].Read)
+	assert.Equal(t, uint(2), stats.Tables[A.Name()].Exported)
+
+	datasource = &FlakyDataSource{MemoryDataSource{source}, []*pull.Error{deadlock, deadlock}, 0}
+	_, err = pull.Pull(plan, pull.NewOneEmptyRowReader(), datasource, exporter, pull.NoTraceListener{}, retry.NewPolicy(1, 0, retry.DefaultCodes), false)
+
+	assert.Equal(t, deadlock, err)
+	assert.Equal(t, 2, datasource.queries)
+}
+
+func TestPullRoots(t *testing.T) {
+	customers := &MemoryRowExporter{[]pull.Row{}}
+	active := &MemoryRowExporter{[]pull.Row{}}
+
+	A := makeTable("A")
+	S := makeTable("S")
+
+	// each row of A has a parent in S
+	AS := pull.NewRelation("A->S", S, A, []string{S.PrimaryKey()[0]}, []string{S.PrimaryKey()[0]})
+
+	step2 := pull.NewStep(2, S, AS, pull.NewRelationList([]pull.Relation{}), pull.NewCycleList([]pull.Cycle{}), pull.NewStepList([]pull.Step{}))
+	step1 := pull.NewStep(1, A, nil, pull.NewRelationList([]pull.Relation{}), pull.NewCycleList([]pull.Cycle{}), pull.NewStepList([]pull.Step{step2}))
+
+	// both roots start from A, the second one overlaps the first one
+	first := pull.NewPlan(pull.NewFilter(2, pull.Row{}, ""), pull.NewStepList([]pull.Step{step1, step2}))
+	second := pull.NewPlan(pull.NewFilter(0, pull.Row{S.PrimaryKey()[0]: 1}, ""), pull.NewStepList([]pull.Step{step1, step2}))
+
+	source := map[string][]pull.Row{
+		A.Name(): {
+			{A.PrimaryKey()[0]: 10, S.PrimaryKey()[0]: 1},
+			{A.PrimaryKey()[0]: 11, S.PrimaryKey()[0]: 2},
+			{A.PrimaryKey()[0]: 12, S.PrimaryKey()[0]: 1},
+		},
+		S.Name(): {
+			{S.PrimaryKey()[0]: 1},
+			{S.PrimaryKey()[0]: 2},
+		},
+	}
+	datasource := &MemoryDataSource{source}
+
+	stats, err := pull.PullRoots([]pull.Plan{first, second}, []pull.RowExporter{customers, active}, datasource, pull.NoTraceListener{}, retry.None, true)
+
+	assert.Nil(t, err)
+	assert.Len(t, customers.rows, 2)
+	assert.Equal(t, source[S.Name()][0], customers.rows[0][AS.Name()])
+	assert.Equal(t, source[S.Name()][1], customers.rows[1][AS.Name()])
+
+	// row 10 was exported by the first root, row 12 refers to a parent already exported
+	assert.Len(t, active.rows, 1)
+	assert.Equal(t, 12, active.rows[0][A.PrimaryKey()[0]])
+	assert.Equal(t, pull.Row{pull.RefKey: pull.Row{"table": S.Name(), "key": pull.Row{S.PrimaryKey()[0]: 1}}}, active.rows[0][AS.Name()])
+	assert.Equal(t, uint(3), stats.Tables[A.Name()].Exported)
+	assert.Equal(t, uint(2), stats.Tables[S.Name()].Read)
+}
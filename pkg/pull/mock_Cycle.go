@@ -23,6 +23,20 @@ func (_m *MockCycle) Len() uint {
 	return r0
 }
 
+// MaxDepth provides a mock function with given fields:
+func (_m *MockCycle) MaxDepth() uint {
+	ret := _m.Called()
+
+	var r0 uint
+	if rf, ok := ret.Get(0).(func() uint); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(uint)
+	}
+
+	return r0
+}
+
 // Relation provides a mock function with given fields: idx
 func (_m *MockCycle) Relation(idx uint) Relation {
 	ret := _m.Called(idx)
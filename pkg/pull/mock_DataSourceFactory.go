@@ -2,20 +2,23 @@
 
 package pull
 
-import mock "github.com/stretchr/testify/mock"
+import (
+	dataconnector "github.com/cgi-fr/lino/pkg/dataconnector"
+	mock "github.com/stretchr/testify/mock"
+)
 
 // MockDataSourceFactory is an autogenerated mock type for the DataSourceFactory type
 type MockDataSourceFactory struct {
 	mock.Mock
 }
 
-// New provides a mock function with given fields: url, schema
-func (_m *MockDataSourceFactory) New(url string, schema string) DataSource {
-	ret := _m.Called(url, schema)
+// New provides a mock function with given fields: url, schema, options
+func (_m *MockDataSourceFactory) New(url string, schema string, options dataconnector.Options) DataSource {
+	ret := _m.Called(url, schema, options)
 
 	var r0 DataSource
-	if rf, ok := ret.Get(0).(func(string, string) DataSource); ok {
-		r0 = rf(url, schema)
+	if rf, ok := ret.Get(0).(func(string, string, dataconnector.Options) DataSource); ok {
+		r0 = rf(url, schema, options)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(DataSource)
@@ -25,6 +25,22 @@ func (_m *MockPlan) InitFilter() Filter {
 	return r0
 }
 
+// References provides a mock function with given fields:
+func (_m *MockPlan) References() []Table {
+	ret := _m.Called()
+
+	var r0 []Table
+	if rf, ok := ret.Get(0).(func() []Table); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]Table)
+		}
+	}
+
+	return r0
+}
+
 // Steps provides a mock function with given fields:
 func (_m *MockPlan) Steps() StepList {
 	ret := _m.Called()
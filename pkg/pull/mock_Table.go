@@ -38,3 +38,31 @@ func (_m *MockTable) PrimaryKey() []string {
 
 	return r0
 }
+
+// Query provides a mock function with given fields:
+func (_m *MockTable) Query() string {
+	ret := _m.Called()
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
+
+// View provides a mock function with given fields:
+func (_m *MockTable) View() string {
+	ret := _m.Called()
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
@@ -9,6 +9,22 @@ type MockTraceListener struct {
 	mock.Mock
 }
 
+// TraceMaxDepth provides a mock function with given fields: _a0, _a1
+func (_m *MockTraceListener) TraceMaxDepth(_a0 Step, _a1 Cycle) TraceListener {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 TraceListener
+	if rf, ok := ret.Get(0).(func(Step, Cycle) TraceListener); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(TraceListener)
+		}
+	}
+
+	return r0
+}
+
 // TraceStep provides a mock function with given fields: _a0, _a1
 func (_m *MockTraceListener) TraceStep(_a0 Step, _a1 Filter) TraceListener {
 	ret := _m.Called(_a0, _a1)
@@ -17,10 +17,12 @@
 
 package pull
 
-// Table from which to pull data.
+// Table from which to pull data, a virtual table reads the rows of a View or of a SQL Query.
 type Table interface {
 	Name() string
 	PrimaryKey() []string
+	View() string
+	Query() string
 }
 
 // Relation between two tables.
@@ -39,9 +41,10 @@ type RelationList interface {
 	Relation(idx uint) Relation
 }
 
-// Cycle is a list of relations.
+// Cycle is a list of relations, followed in loop at most MaxDepth times (0 for no limit).
 type Cycle interface {
 	RelationList
+	MaxDepth() uint
 }
 
 // CycleList is a list of cycles.
@@ -66,10 +69,11 @@ type StepList interface {
 	Step(uint) Step
 }
 
-// Plan of the puller process.
+// Plan of the puller process, relations leading to a reference table are not followed.
 type Plan interface {
 	InitFilter() Filter
 	Steps() StepList
+	References() []Table
 }
 
 // Value is an untyped data.
@@ -85,6 +89,15 @@ type Filter interface {
 // Row of data.
 type Row map[string]Value
 
+// RefKey is the key of the marker replacing a parent row already exported.
+const RefKey = "$ref"
+
+// RootKey is the key added with the name of their root to the rows of an ingress descriptor with several roots.
+const RootKey = "$root"
+
+// ReferenceKey is the key added with the name of their table to the rows of reference tables.
+const ReferenceKey = "$reference"
+
 // Update Row with an other Row to generate a new one
 func (r Row) Update(other Row) Row {
 	for k, v := range other {
@@ -96,6 +109,7 @@ func (r Row) Update(other Row) Row {
 // Error is the error type returned by the domain
 type Error struct {
 	Description string
+	Code        string
 }
 
 func (e *Error) Error() string {
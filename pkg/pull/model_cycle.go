@@ -22,11 +22,18 @@ import (
 	"strings"
 )
 
+type cycle struct {
+	relationList
+	maxDepth uint
+}
+
 // NewCycle initialize a new Cycle object
-func NewCycle(relations []Relation) Cycle {
-	return relationList{uint(len(relations)), relations}
+func NewCycle(relations []Relation, maxDepth uint) Cycle {
+	return cycle{relationList{uint(len(relations)), relations}, maxDepth}
 }
 
+func (c cycle) MaxDepth() uint { return c.maxDepth }
+
 type cycleList struct {
 	len   uint
 	slice []Cycle
@@ -23,17 +23,24 @@ import (
 )
 
 type plan struct {
-	filter Filter
-	steps  StepList
+	filter     Filter
+	steps      StepList
+	references []Table
 }
 
 // NewPlan initialize a new Plan object
 func NewPlan(filter Filter, steps StepList) Plan {
-	return plan{filter: filter, steps: steps}
+	return plan{filter: filter, steps: steps, references: []Table{}}
 }
 
-func (p plan) InitFilter() Filter { return p.filter }
-func (p plan) Steps() StepList    { return p.steps }
+// NewPlanWithReferences initialize a new Plan object that will not follow relations leading to the reference tables
+func NewPlanWithReferences(filter Filter, steps StepList, references []Table) Plan {
+	return plan{filter: filter, steps: steps, references: references}
+}
+
+func (p plan) InitFilter() Filter  { return p.filter }
+func (p plan) Steps() StepList     { return p.steps }
+func (p plan) References() []Table { return p.references }
 func (p plan) String() string {
 	sb := &strings.Builder{}
 	for i := uint(0); i < p.Steps().Len(); i++ {
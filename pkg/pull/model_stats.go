@@ -0,0 +1,61 @@
+// Copyright (C) 2021 CGI France
+//
+// This file is part of LINO.
+//
+// LINO is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// LINO is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with LINO.  If not, see <http://www.gnu.org/licenses/>.
+
+package pull
+
+import (
+	"time"
+)
+
+// TableStats are the counters of a table during a pull.
+type TableStats struct {
+	Read     uint
+	Exported uint
+	Duration time.Duration
+}
+
+// Stats of a pull run.
+type Stats struct {
+	Tables   map[string]*TableStats
+	Duration time.Duration
+}
+
+// NewStats initialize an empty Stats object.
+func NewStats() Stats {
+	return Stats{Tables: map[string]*TableStats{}}
+}
+
+// Table returns the counters of a table, created on first access.
+func (s Stats) Table(name string) *TableStats {
+	ts, ok := s.Tables[name]
+	if !ok {
+		ts = &TableStats{}
+		s.Tables[name] = ts
+	}
+	return ts
+}
+
+// Merge adds the counters and duration of other to the stats.
+func (s *Stats) Merge(other Stats) {
+	for name, ts := range other.Tables {
+		table := s.Table(name)
+		table.Read += ts.Read
+		table.Exported += ts.Exported
+		table.Duration += ts.Duration
+	}
+	s.Duration += other.Duration
+}
@@ -18,8 +18,10 @@
 package pull
 
 type table struct {
-	name string
-	pk   []string
+	name  string
+	pk    []string
+	view  string
+	query string
 }
 
 // NewTable initialize a new Table object
@@ -27,6 +29,13 @@ func NewTable(name string, pk []string) Table {
 	return table{name: name, pk: pk}
 }
 
+// NewVirtualTable initialize a new Table object backed by a view or a SQL query
+func NewVirtualTable(name string, pk []string, view string, query string) Table {
+	return table{name: name, pk: pk, view: view, query: query}
+}
+
 func (t table) Name() string         { return t.name }
 func (t table) PrimaryKey() []string { return t.pk }
+func (t table) View() string         { return t.view }
+func (t table) Query() string        { return t.query }
 func (t table) String() string       { return t.name }
@@ -17,15 +17,18 @@
 
 package push
 
+import "github.com/cgi-fr/lino/pkg/dataconnector"
+
 // DataDestinationFactory exposes methods to create new datadestinations.
 type DataDestinationFactory interface {
-	New(url string, schema string) DataDestination
+	New(url string, schema string, options dataconnector.Options) DataDestination
 }
 
 // DataDestination to write in the push process.
 type DataDestination interface {
 	Open(plan Plan, mode Mode, disableConstraints bool) *Error
 	Commit() *Error
+	Rollback() *Error
 	RowWriter(table Table) (RowWriter, *Error)
 	Close() *Error
 }
@@ -38,7 +41,17 @@ type RowWriter interface {
 type NoErrorCaptureRowWriter struct{}
 
 func (necrw NoErrorCaptureRowWriter) Write(row Row) *Error {
-	return &Error{"No error capture configured"}
+	return &Error{Description: "No error capture configured"}
+}
+
+// StatsWriter write the statistics of a push run
+type StatsWriter interface {
+	Write(stats Stats) *Error
+}
+
+// MetadataReader reads the columns metadata of destination tables.
+type MetadataReader interface {
+	Columns(table Table) ([]Column, *Error)
 }
 
 // RowIterator iter over a collection of rows
@@ -51,10 +51,11 @@ func (ri *rowIterator) Close() *push.Error {
 }
 
 type memoryDataDestination struct {
-	tables    map[string]*rowWriter
-	closed    bool
-	committed bool
-	opened    bool
+	tables     map[string]*rowWriter
+	closed     bool
+	committed  bool
+	opened     bool
+	rolledBack int
 }
 
 func (mdd *memoryDataDestination) RowWriter(table push.Table) (push.RowWriter, *push.Error) {
@@ -68,6 +69,17 @@ func (mdd *memoryDataDestination) Open(pla push.Plan, mode push.Mode, disableCon
 
 func (mdd *memoryDataDestination) Commit() *push.Error {
 	mdd.committed = true
+	for _, rw := range mdd.tables {
+		rw.committed = len(rw.rows)
+	}
+	return nil
+}
+
+func (mdd *memoryDataDestination) Rollback() *push.Error {
+	mdd.rolledBack++
+	for _, rw := range mdd.tables {
+		rw.rows = rw.rows[:rw.committed]
+	}
 	return nil
 }
 
@@ -77,11 +89,53 @@ func (mdd *memoryDataDestination) Close() *push.Error {
 }
 
 type rowWriter struct {
-	rows []push.Row
+	rows      []push.Row
+	committed int
+	writes    int
+	failAt    int
+	failure   *push.Error
 }
 
 func (rw *rowWriter) Write(row push.Row) *push.Error {
+	rw.writes++
+	if rw.writes == rw.failAt {
+		return rw.failure
+	}
 	log.Trace().Msg(fmt.Sprintf("append row %s to %s", row, rw.rows))
 	rw.rows = append(rw.rows, row)
 	return nil
 }
+
+type memoryMetadataReader struct {
+	columns map[string][]push.Column
+}
+
+func (mmr *memoryMetadataReader) Columns(table push.Table) ([]push.Column, *push.Error) {
+	return mmr.columns[table.Name()], nil
+}
+
+// sliceRowIterator iterates over the given rows
+type sliceRowIterator struct {
+	rows    []push.Row
+	current push.Row
+}
+
+func (ri *sliceRowIterator) Error() *push.Error {
+	return nil
+}
+
+func (ri *sliceRowIterator) Value() *push.Row {
+	return &ri.current
+}
+
+func (ri *sliceRowIterator) Next() bool {
+	if len(ri.rows) == 0 {
+		return false
+	}
+	ri.current, ri.rows = ri.rows[0], ri.rows[1:]
+	return true
+}
+
+func (ri *sliceRowIterator) Close() *push.Error {
+	return nil
+}
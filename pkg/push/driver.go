@@ -19,51 +19,170 @@ package push
 
 import (
 	"fmt"
+	"time"
 
+	"github.com/cgi-fr/lino/pkg/retry"
 	"github.com/rs/zerolog/log"
 )
 
-// Push write rows to target table
-func Push(ri RowIterator, destination DataDestination, plan Plan, mode Mode, commitSize uint, disableConstraints bool, catchError RowWriter) *Error {
+// Push write rows to target table, on a retryable error the rows since the last commit are pushed again
+func Push(ri RowIterator, destination DataDestination, plan Plan, mode Mode, commitSize uint, disableConstraints bool, catchError RowWriter, policy retry.Policy) (Stats, *Error) {
+	start := time.Now()
+	stats := NewStats()
+
 	err1 := destination.Open(plan, mode, disableConstraints)
 	if err1 != nil {
-		return err1
+		return stats, err1
 	}
 	defer destination.Close()
 	defer ri.Close()
 
+	p := pusher{
+		destination: destination,
+		plan:        plan,
+		mode:        mode,
+		catchError:  catchError,
+		retry:       policy,
+		buffer:      []Row{},
+		caught:      map[int]bool{},
+		stats:       &stats,
+		committed:   stats.copy(),
+		pushed:      NewKeySet(),
+		commitKeys:  NewKeySet(),
+	}
+
 	i := uint(0)
 	for ri.Next() {
 		row := ri.Value()
 
-		err2 := pushRow(*row, destination, plan.FirstTable(), plan, mode)
+		err2 := p.push(*row)
 		if err2 != nil {
-			err4 := catchError.Write(*row)
-			if err4 != nil {
-				return &Error{Description: fmt.Sprintf("%s (%s)", err2.Error(), err4.Error())}
-			}
-			log.Info().Msg(fmt.Sprintf("Error catched : %s", err2.Error()))
+			stats.Duration = time.Since(start)
+			return stats, err2
 		}
 		i++
 		if i%commitSize == 0 {
 			log.Info().Msg("Intermediate commit")
-			errCommit := destination.Commit()
+			errCommit := p.commit()
 			if errCommit != nil {
-				return errCommit
+				stats.Duration = time.Since(start)
+				return stats, errCommit
 			}
 		}
 	}
 
+	if len(p.buffer) > 0 && ri.Error() == nil {
+		errCommit := p.commit()
+		if errCommit != nil {
+			stats.Duration = time.Since(start)
+			return stats, errCommit
+		}
+	}
+
+	stats.Duration = time.Since(start)
+
 	if ri.Error() != nil {
-		return ri.Error()
+		return stats, ri.Error()
 	}
 
 	log.Info().Msg("End of stream")
+	return stats, nil
+}
+
+type pusher struct {
+	destination DataDestination
+	plan        Plan
+	mode        Mode
+	catchError  RowWriter
+	retry       retry.Policy
+	buffer      []Row        // rows pushed since the last commit, only kept if retry is enabled
+	caught      map[int]bool // index in buffer of rows already written to catchError
+	stats       *Stats
+	committed   Stats  // counters at the last commit, restored on rollback
+	pushed      KeySet // keys of the rows already pushed, to resolve $ref markers
+	commitKeys  KeySet // keys of the rows pushed at the last commit, restored on rollback
+}
+
+// push a row, keeping it until the next commit to replay it on retryable error
+func (p *pusher) push(row Row) *Error {
+	if p.retry.Attempts == 0 {
+		return p.pushRow(row, -1)
+	}
+
+	p.buffer = append(p.buffer, row)
+	last := len(p.buffer) - 1
+	return p.withRetry(last, func() *Error { return p.pushRow(row, last) })
+}
+
+// commit the destination, replaying rows since the last commit on retryable error
+func (p *pusher) commit() *Error {
+	if p.retry.Attempts == 0 {
+		return p.destination.Commit()
+	}
+
+	err := p.withRetry(len(p.buffer), p.destination.Commit)
+	if err != nil {
+		return err
+	}
+	p.buffer = []Row{}
+	p.caught = map[int]bool{}
+	p.committed = p.stats.copy()
+	p.commitKeys = p.pushed.copy()
 	return nil
 }
 
-// FilterRelation split values and relations to follow
-func FilterRelation(row Row, relations map[string]Relation) (Row, map[string]Row, map[string][]Row, *Error) {
+// pushRow push a row in the first table, or in the table of its root or reference tag, and write it to catchError if it fails with a non retryable error
+func (p *pusher) pushRow(row Row, index int) *Error {
+	table, untagged, err2 := p.plan.Route(row)
+	if err2 == nil {
+		err2 = pushRow(untagged, p.destination, table, p.plan, p.mode, *p.stats, p.pushed)
+	}
+	if err2 == nil || p.retry.IsRetryable(err2.Code) {
+		return err2
+	}
+
+	if p.caught[index] {
+		return nil
+	}
+	err4 := p.catchError.Write(row)
+	if err4 != nil {
+		return &Error{Description: fmt.Sprintf("%s (%s)", err2.Error(), err4.Error())}
+	}
+	if index >= 0 {
+		p.caught[index] = true
+	}
+	log.Info().Msg(fmt.Sprintf("Error catched : %s", err2.Error()))
+	return nil
+}
+
+// withRetry run action, on retryable error it rollbacks the destination, pushes again the first replay rows of the buffer and run action again
+func (p *pusher) withRetry(replay int, action func() *Error) *Error {
+	err := action()
+	for attempt := uint(1); err != nil && p.retry.IsRetryable(err.Code) && attempt <= p.retry.Attempts; attempt++ {
+		log.Warn().Msg(fmt.Sprintf("Retryable error (attempt %d/%d) : %s", attempt, p.retry.Attempts, err.Error()))
+
+		errRollback := p.destination.Rollback()
+		if errRollback != nil {
+			return errRollback
+		}
+		p.stats.Tables = p.committed.copy().Tables
+		p.pushed = p.commitKeys.copy()
+
+		time.Sleep(p.retry.Delay(attempt))
+
+		err = nil
+		for i := 0; i < replay && err == nil; i++ {
+			err = p.pushRow(p.buffer[i], i)
+		}
+		if err == nil {
+			err = action()
+		}
+	}
+	return err
+}
+
+// FilterRelation split values and relations to follow, a $ref marker is dropped if the referenced row is in pushed
+func FilterRelation(row Row, relations map[string]Relation, pushed KeySet) (Row, map[string]Row, map[string][]Row, *Error) {
 	frow := Row{}
 	frel := map[string]Row{}
 	fInverseRel := map[string][]Row{}
@@ -72,6 +191,13 @@ func FilterRelation(row Row, relations map[string]Relation) (Row, map[string]Row
 		if rel, ok := relations[name]; ok {
 			switch tv := val.(type) {
 			case map[string]interface{}:
+				if ref, isRef := tv[RefKey]; isRef {
+					if err := resolveRef(ref, pushed); err != nil {
+						return frow, frel, fInverseRel, err
+					}
+					continue
+				}
+
 				sr := Row{}
 				for k, v := range tv {
 					sr[k] = v
@@ -110,11 +236,38 @@ func FilterRelation(row Row, relations map[string]Relation) (Row, map[string]Row
 	return frow, frel, fInverseRel, nil
 }
 
+// resolveRef check that the row referenced by a $ref marker is in pushed
+func resolveRef(ref Value, pushed KeySet) *Error {
+	marker, ok := ref.(map[string]interface{})
+	if !ok {
+		return &Error{Description: fmt.Sprintf("%v is not a valid %s marker", ref, RefKey)}
+	}
+	table, ok := marker["table"].(string)
+	if !ok {
+		return &Error{Description: fmt.Sprintf("%v is not a valid %s marker", ref, RefKey)}
+	}
+	key, ok := marker["key"].(map[string]interface{})
+	if !ok {
+		return &Error{Description: fmt.Sprintf("%v is not a valid %s marker", ref, RefKey)}
+	}
+
+	keyRow := Row{}
+	for k, v := range key {
+		keyRow[k] = v
+	}
+
+	if !pushed.Contains(table, keyRow) {
+		return &Error{Description: fmt.Sprintf("row %v of table %s is referenced before being pushed", key, table)}
+	}
+	return nil
+}
+
 // pushRow push a row in a specific table
-func pushRow(row Row, ds DataDestination, table Table, plan Plan, mode Mode) *Error {
-	frow, frel, fInverseRel, err1 := FilterRelation(row, plan.RelationsFromTable(table))
+func pushRow(row Row, ds DataDestination, table Table, plan Plan, mode Mode, stats Stats, pushed KeySet) *Error {
+	frow, frel, fInverseRel, err1 := FilterRelation(row, plan.RelationsFromTable(table), pushed)
 
 	if err1 != nil {
+		stats.Table(table.Name()).Failed++
 		return err1
 	}
 
@@ -128,7 +281,7 @@ func pushRow(row Row, ds DataDestination, table Table, plan Plan, mode Mode) *Er
 		for relName, subArray := range fInverseRel {
 			for _, subRow := range subArray {
 				rel := plan.RelationsFromTable(table)[relName]
-				err5 := pushRow(subRow, ds, rel.OppositeOf(table), plan, mode)
+				err5 := pushRow(subRow, ds, rel.OppositeOf(table), plan, mode, stats, pushed)
 				if err5 != nil {
 					return err5
 				}
@@ -136,16 +289,17 @@ func pushRow(row Row, ds DataDestination, table Table, plan Plan, mode Mode) *Er
 		}
 
 		// Current table
-		err3 := rw.Write(frow)
+		err3 := writeRow(rw, frow, stats.Table(table.Name()), mode)
 
 		if err3 != nil {
 			return err3
 		}
+		pushed.Add(table, frow)
 
 		// and parents
 		for relName, subRow := range frel {
 			rel := plan.RelationsFromTable(table)[relName]
-			err4 := pushRow(subRow, ds, rel.OppositeOf(table), plan, mode)
+			err4 := pushRow(subRow, ds, rel.OppositeOf(table), plan, mode, stats, pushed)
 			if err4 != nil {
 				return err4
 			}
@@ -154,24 +308,25 @@ func pushRow(row Row, ds DataDestination, table Table, plan Plan, mode Mode) *Er
 		// insert parent first
 		for relName, subRow := range frel {
 			rel := plan.RelationsFromTable(table)[relName]
-			err4 := pushRow(subRow, ds, rel.OppositeOf(table), plan, mode)
+			err4 := pushRow(subRow, ds, rel.OppositeOf(table), plan, mode, stats, pushed)
 			if err4 != nil {
 				return err4
 			}
 		}
 
 		// current
-		err3 := rw.Write(frow)
+		err3 := writeRow(rw, frow, stats.Table(table.Name()), mode)
 
 		if err3 != nil {
 			return err3
 		}
+		pushed.Add(table, frow)
 
 		// and children
 		for relName, subArray := range fInverseRel {
 			for _, subRow := range subArray {
 				rel := plan.RelationsFromTable(table)[relName]
-				err5 := pushRow(subRow, ds, rel.OppositeOf(table), plan, mode)
+				err5 := pushRow(subRow, ds, rel.OppositeOf(table), plan, mode, stats, pushed)
 				if err5 != nil {
 					return err5
 				}
@@ -181,3 +336,23 @@ func pushRow(row Row, ds DataDestination, table Table, plan Plan, mode Mode) *Er
 
 	return nil
 }
+
+// writeRow write a row in a table and update the table counters
+func writeRow(rw RowWriter, row Row, stats *TableStats, mode Mode) *Error {
+	start := time.Now()
+	err := rw.Write(row)
+	stats.Duration += time.Since(start)
+	stats.Read++
+
+	switch {
+	case err == ErrDuplicate:
+		stats.Duplicates++
+		return nil
+	case err != nil:
+		stats.Failed++
+		return err
+	}
+
+	stats.written(mode)
+	return nil
+}
@@ -21,6 +21,7 @@ import (
 	"testing"
 
 	"github.com/cgi-fr/lino/pkg/push"
+	"github.com/cgi-fr/lino/pkg/retry"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -47,9 +48,9 @@ func TestSimplePush(t *testing.T) {
 		B.Name(): &rowWriter{},
 		C.Name(): &rowWriter{},
 	}
-	dest := memoryDataDestination{tables, false, false, false}
+	dest := memoryDataDestination{tables, false, false, false, 0}
 
-	err := push.Push(&ri, &dest, plan, push.Insert, 2, true, push.NoErrorCaptureRowWriter{})
+	_, err := push.Push(&ri, &dest, plan, push.Insert, 2, true, push.NoErrorCaptureRowWriter{}, retry.None)
 
 	assert.Nil(t, err)
 	assert.Equal(t, true, dest.closed)
@@ -86,9 +87,9 @@ func TestRelationPush(t *testing.T) {
 		B.Name(): &rowWriter{},
 		C.Name(): &rowWriter{},
 	}
-	dest := memoryDataDestination{tables, false, false, false}
+	dest := memoryDataDestination{tables, false, false, false, 0}
 
-	err := push.Push(&ri, &dest, plan, push.Insert, 2, true, push.NoErrorCaptureRowWriter{})
+	_, err := push.Push(&ri, &dest, plan, push.Insert, 2, true, push.NoErrorCaptureRowWriter{}, retry.None)
 
 	// no error
 	assert.Nil(t, err)
@@ -135,9 +136,9 @@ func TestRelationPushWithEmptyRelation(t *testing.T) {
 		B.Name(): &rowWriter{},
 		C.Name(): &rowWriter{},
 	}
-	dest := memoryDataDestination{tables, false, false, false}
+	dest := memoryDataDestination{tables, false, false, false, 0}
 
-	err := push.Push(&ri, &dest, plan, push.Insert, 2, true, push.NoErrorCaptureRowWriter{})
+	_, err := push.Push(&ri, &dest, plan, push.Insert, 2, true, push.NoErrorCaptureRowWriter{}, retry.None)
 
 	// no error
 	assert.Nil(t, err)
@@ -186,9 +187,9 @@ func TestInversseRelationPush(t *testing.T) {
 		B.Name(): &rowWriter{},
 		C.Name(): &rowWriter{},
 	}
-	dest := memoryDataDestination{tables, false, false, false}
+	dest := memoryDataDestination{tables, false, false, false, 0}
 
-	err := push.Push(&ri, &dest, plan, push.Insert, 5, true, push.NoErrorCaptureRowWriter{})
+	_, err := push.Push(&ri, &dest, plan, push.Insert, 5, true, push.NoErrorCaptureRowWriter{}, retry.None)
 
 	// no error
 	assert.Nil(t, err)
@@ -209,3 +210,169 @@ func TestInversseRelationPush(t *testing.T) {
 	assert.Equal(t, 1, len(dest.tables[C.Name()].rows[0]))
 	assert.Equal(t, "1", dest.tables[C.Name()].rows[0]["history"])
 }
+
+func TestPushRetryFromLastCommit(t *testing.T) {
+	A := makeTable("A")
+
+	plan := push.NewPlan(
+		A,
+		[]push.Relation{},
+	)
+	ri := rowIterator{limit: 10, row: push.Row{"name": "John"}}
+	tables := map[string]*rowWriter{
+		A.Name(): &rowWriter{failAt: 7, failure: &push.Error{Description: "deadlock detected", Code: "40P01"}},
+	}
+	dest := memoryDataDestination{tables, false, false, false, 0}
+
+	stats, err := push.Push(&ri, &dest, plan, push.Insert, 4, true, push.NoErrorCaptureRowWriter{}, retry.NewPolicy(2, 0, retry.DefaultCodes))
+
+	// no error
+	assert.Nil(t, err)
+	// rows since the last commit are rolled back once
+	assert.Equal(t, 1, dest.rolledBack)
+	// replayed rows are counted once
+	assert.Equal(t, uint(10), stats.Tables[A.Name()].Inserted)
+	// all rows are inserted only once
+	assert.Equal(t, 10, len(dest.tables[A.Name()].rows))
+	// rows 5 and 6 are replayed
+	assert.Equal(t, 13, dest.tables[A.Name()].writes)
+}
+
+func TestPushRetryForgetsRolledBackRows(t *testing.T) {
+	customer := push.NewTable("customer", []string{"customer_id"})
+	store := push.NewTable("store", []string{"store_id"})
+	CS := push.NewRelation("customer_store", store, customer)
+
+	plan := push.NewPlanWithRoots(customer, []push.Relation{CS}, map[string]push.Table{"customer": customer, "store": store}, []push.Table{})
+	ri := sliceRowIterator{rows: []push.Row{
+		{push.RootKey: "store", "store_id": 1},
+		{"customer_id": 1, "customer_store": map[string]interface{}{
+			push.RefKey: map[string]interface{}{"table": "store", "key": map[string]interface{}{"store_id": 1}},
+		}},
+	}}
+	tables := map[string]*rowWriter{
+		// the store is rolled back then fails when it is pushed again
+		store.Name():    {failAt: 2, failure: &push.Error{Description: "null value in column", Code: "23502"}},
+		customer.Name(): {failAt: 1, failure: &push.Error{Description: "deadlock detected", Code: "40P01"}},
+	}
+	dest := memoryDataDestination{tables, false, false, false, 0}
+	caught := &rowWriter{}
+
+	_, err := push.Push(&ri, &dest, plan, push.Insert, 10, true, caught, retry.NewPolicy(1, 0, retry.DefaultCodes))
+
+	assert.Nil(t, err)
+	assert.Equal(t, 1, dest.rolledBack)
+	// the customer can't reference the rolled back store
+	assert.Len(t, dest.tables[store.Name()].rows, 0)
+	assert.Len(t, dest.tables[customer.Name()].rows, 0)
+	assert.Len(t, caught.rows, 2)
+}
+
+func TestPushNoRetryOnOtherError(t *testing.T) {
+	A := makeTable("A")
+
+	plan := push.NewPlan(
+		A,
+		[]push.Relation{},
+	)
+	ri := rowIterator{limit: 10, row: push.Row{"name": "John"}}
+	tables := map[string]*rowWriter{
+		A.Name(): &rowWriter{failAt: 3, failure: &push.Error{Description: "null value in column", Code: "23502"}},
+	}
+	dest := memoryDataDestination{tables, false, false, false, 0}
+
+	_, err := push.Push(&ri, &dest, plan, push.Insert, 4, true, push.NoErrorCaptureRowWriter{}, retry.NewPolicy(2, 0, retry.DefaultCodes))
+
+	assert.NotNil(t, err)
+	assert.Equal(t, 0, dest.rolledBack)
+}
+
+func TestPushStats(t *testing.T) {
+	A := makeTable("A")
+	B := makeTable("B")
+
+	plan := push.NewPlan(
+		A,
+		[]push.Relation{makeRel(A, B)},
+	)
+	ri := rowIterator{limit: 5, row: push.Row{
+		"name": "John",
+		"A->B": map[string]interface{}{
+			"age": 42,
+		},
+	}}
+	tables := map[string]*rowWriter{
+		A.Name(): &rowWriter{},
+		B.Name(): &rowWriter{failAt: 2, failure: push.ErrDuplicate},
+	}
+	dest := memoryDataDestination{tables, false, false, false, 0}
+
+	stats, err := push.Push(&ri, &dest, plan, push.Insert, 2, true, push.NoErrorCaptureRowWriter{}, retry.NewPolicy(1, 0, retry.DefaultCodes))
+
+	assert.Nil(t, err)
+	assert.Equal(t, uint(5), stats.Tables[A.Name()].Read)
+	assert.Equal(t, uint(5), stats.Tables[A.Name()].Inserted)
+	assert.Equal(t, uint(5), stats.Tables[B.Name()].Read)
+	assert.Equal(t, uint(4), stats.Tables[B.Name()].Inserted)
+	assert.Equal(t, uint(1), stats.Tables[B.Name()].Duplicates)
+	assert.Equal(t, uint(0), stats.Tables[B.Name()].Failed)
+}
+
+func TestPushSeveralRoots(t *testing.T) {
+	customer := push.NewTable("customer", []string{"customer_id"})
+	store := push.NewTable("store", []string{"store_id"})
+	CS := push.NewRelation("customer_store", store, customer)
+
+	plan := push.NewPlanWithRoots(customer, []push.Relation{CS}, map[string]push.Table{"customer": customer, "small_store": store}, []push.Table{})
+	ri := sliceRowIterator{rows: []push.Row{
+		{push.RootKey: "customer", "customer_id": 1, "customer_store": map[string]interface{}{"store_id": 1}},
+		{push.RootKey: "small_store", "store_id": 2},
+		{"customer_id": 2},
+	}}
+	tables := map[string]*rowWriter{
+		customer.Name(): {},
+		store.Name():    {},
+	}
+	dest := memoryDataDestination{tables, false, false, false, 0}
+
+	_, err := push.Push(&ri, &dest, plan, push.Insert, 10, true, push.NoErrorCaptureRowWriter{}, retry.None)
+
+	assert.Nil(t, err)
+	// the $root tag is not a column, untagged rows go to the start table
+	assert.Equal(t, []push.Row{{"customer_id": 1}, {"customer_id": 2}}, dest.tables[customer.Name()].rows)
+	assert.Equal(t, []push.Row{{"store_id": 1}, {"store_id": 2}}, dest.tables[store.Name()].rows)
+
+	ri = sliceRowIterator{rows: []push.Row{{push.RootKey: "unknown", "customer_id": 3}}}
+	_, err = push.Push(&ri, &dest, plan, push.Insert, 10, true, push.NoErrorCaptureRowWriter{}, retry.None)
+
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Description, "unknown is not a $root of the ingress descriptor")
+}
+
+func TestFilterRelationRef(t *testing.T) {
+	A := makeTable("A")
+	B := push.NewTable("B", []string{"id"})
+
+	AB := makeRel(A, B)
+
+	pushed := push.NewKeySet()
+	pushed.Add(B, push.Row{"id": float64(1), "name": "store"})
+
+	row := push.Row{
+		"name": "John",
+		"A->B": map[string]interface{}{
+			push.RefKey: map[string]interface{}{"table": "B", "key": map[string]interface{}{"id": float64(1)}},
+		},
+	}
+
+	frow, frel, fInverseRel, err := push.FilterRelation(row, map[string]push.Relation{AB.Name(): AB}, pushed)
+
+	assert.Nil(t, err)
+	assert.Equal(t, push.Row{"name": "John"}, frow)
+	assert.Len(t, frel, 0)
+	assert.Len(t, fInverseRel, 0)
+
+	_, _, _, err = push.FilterRelation(row, map[string]push.Relation{AB.Name(): AB}, push.NewKeySet())
+
+	assert.EqualError(t, err, "row map[id:1] of table B is referenced before being pushed")
+}
@@ -57,6 +57,22 @@ func (_m *MockDataDestination) Open(plan Plan, mode Mode, disableConstraints boo
 	return r0
 }
 
+// Rollback provides a mock function with given fields:
+func (_m *MockDataDestination) Rollback() *Error {
+	ret := _m.Called()
+
+	var r0 *Error
+	if rf, ok := ret.Get(0).(func() *Error); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*Error)
+		}
+	}
+
+	return r0
+}
+
 // RowWriter provides a mock function with given fields: table
 func (_m *MockDataDestination) RowWriter(table Table) (RowWriter, *Error) {
 	ret := _m.Called(table)
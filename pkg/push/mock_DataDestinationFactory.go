@@ -2,20 +2,23 @@
 
 package push
 
-import mock "github.com/stretchr/testify/mock"
+import (
+	dataconnector "github.com/cgi-fr/lino/pkg/dataconnector"
+	mock "github.com/stretchr/testify/mock"
+)
 
 // MockDataDestinationFactory is an autogenerated mock type for the DataDestinationFactory type
 type MockDataDestinationFactory struct {
 	mock.Mock
 }
 
-// New provides a mock function with given fields: url, schema
-func (_m *MockDataDestinationFactory) New(url string, schema string) DataDestination {
-	ret := _m.Called(url, schema)
+// New provides a mock function with given fields: url, schema, options
+func (_m *MockDataDestinationFactory) New(url string, schema string, options dataconnector.Options) DataDestination {
+	ret := _m.Called(url, schema, options)
 
 	var r0 DataDestination
-	if rf, ok := ret.Get(0).(func(string, string) DataDestination); ok {
-		r0 = rf(url, schema)
+	if rf, ok := ret.Get(0).(func(string, string, dataconnector.Options) DataDestination); ok {
+		r0 = rf(url, schema, options)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(DataDestination)
@@ -41,6 +41,40 @@ func (_m *MockPlan) RelationsFromTable(table Table) map[string]Relation {
 	return r0
 }
 
+// Route provides a mock function with given fields: row
+func (_m *MockPlan) Route(row Row) (Table, Row, *Error) {
+	ret := _m.Called(row)
+
+	var r0 Table
+	if rf, ok := ret.Get(0).(func(Row) Table); ok {
+		r0 = rf(row)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(Table)
+		}
+	}
+
+	var r1 Row
+	if rf, ok := ret.Get(1).(func(Row) Row); ok {
+		r1 = rf(row)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(Row)
+		}
+	}
+
+	var r2 *Error
+	if rf, ok := ret.Get(2).(func(Row) *Error); ok {
+		r2 = rf(row)
+	} else {
+		if ret.Get(2) != nil {
+			r2 = ret.Get(2).(*Error)
+		}
+	}
+
+	return r0, r1, r2
+}
+
 // Tables provides a mock function with given fields:
 func (_m *MockPlan) Tables() []Table {
 	ret := _m.Called()
@@ -17,6 +17,12 @@
 
 package push
 
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
 // Table from which to push data.
 type Table interface {
 	Name() string
@@ -28,6 +34,8 @@ type Plan interface {
 	FirstTable() Table
 	RelationsFromTable(table Table) map[string]Relation
 	Tables() []Table
+	// Route returns the table of a row tagged by pull with its root or its reference table, and the row without the tag
+	Route(row Row) (Table, Row, *Error)
 }
 
 // Relation between two tables.
@@ -38,20 +46,98 @@ type Relation interface {
 	OppositeOf(table Table) Table
 }
 
+// Column of a destination table.
+type Column struct {
+	Name       string
+	Type       string
+	Nullable   bool
+	HasDefault bool
+}
+
+// Incompatibility between the input rows and the destination columns.
+type Incompatibility struct {
+	Table     string
+	Column    string
+	Reason    string
+	Count     uint
+	FirstLine uint
+}
+
 // Value is an untyped data.
 type Value interface{}
 
 // Row of data.
 type Row map[string]Value
 
+// RefKey is the key of the marker written by pull in place of a parent row already exported.
+const RefKey = "$ref"
+
+// RootKey is the key added by pull with the name of their root to the rows of an ingress descriptor with several roots.
+const RootKey = "$root"
+
+// ReferenceKey is the key added by pull with the name of their table to the rows of reference tables.
+const ReferenceKey = "$reference"
+
+// KeySet records the primary keys of the rows already pushed.
+type KeySet map[string]bool
+
+// NewKeySet initialize a new empty KeySet
+func NewKeySet() KeySet {
+	return KeySet{}
+}
+
+// Add the primary key of row in table, rows of a table without primary key are ignored.
+func (s KeySet) Add(table Table, row Row) {
+	if len(table.PrimaryKey()) == 0 {
+		return
+	}
+	key := Row{}
+	for _, pk := range table.PrimaryKey() {
+		key[pk] = row[pk]
+	}
+	s[keyString(table.Name(), key)] = true
+}
+
+// Contains returns true if the row of table with the given key was added.
+func (s KeySet) Contains(table string, key Row) bool {
+	return s[keyString(table, key)]
+}
+
+func (s KeySet) copy() KeySet {
+	result := make(KeySet, len(s))
+	for key := range s {
+		result[key] = true
+	}
+	return result
+}
+
+func keyString(table string, key Row) string {
+	columns := make([]string, 0, len(key))
+	for column := range key {
+		columns = append(columns, column)
+	}
+	sort.Strings(columns)
+
+	sb := &strings.Builder{}
+	sb.WriteString(table)
+	for _, column := range columns {
+		fmt.Fprintf(sb, "\x00%s=%v", column, key[column])
+	}
+	return sb.String()
+}
+
 // Error is the error type returned by the domain
 type Error struct {
 	Description string
+	Code        string
 }
 
 func (e *Error) Error() string {
 	return e.Description
 }
 
+// ErrDuplicate is returned by a table RowWriter when the row already exists and was skipped
+var ErrDuplicate = &Error{Description: "duplicate key"}
+
 // StopIteratorError signal the end of iterator
 type StopIteratorError struct{}
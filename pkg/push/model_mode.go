@@ -59,7 +59,7 @@ func ParseMode(mode string) (Mode, *Error) {
 			return Mode(i), nil
 		}
 	}
-	return end, &Error{mode + " is not a valide pushing mode"}
+	return end, &Error{Description: mode + " is not a valide pushing mode"}
 }
 
 // String representation
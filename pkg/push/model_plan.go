@@ -17,14 +17,27 @@
 
 package push
 
+import "fmt"
+
 type plan struct {
 	firstTable Table
 	relations  []Relation
+	roots      map[string]Table
+	references map[string]Table
 }
 
 // NewPlan initialize a new Plan object
 func NewPlan(first Table, relations []Relation) Plan {
-	return plan{firstTable: first, relations: relations}
+	return NewPlanWithRoots(first, relations, map[string]Table{}, []Table{})
+}
+
+// NewPlanWithRoots initialize a new Plan object with the table of each root name and the reference tables
+func NewPlanWithRoots(first Table, relations []Relation, roots map[string]Table, references []Table) Plan {
+	rmap := map[string]Table{}
+	for _, t := range references {
+		rmap[t.Name()] = t
+	}
+	return plan{firstTable: first, relations: relations, roots: roots, references: rmap}
 }
 
 func (p plan) FirstTable() Table { return p.firstTable }
@@ -48,8 +61,43 @@ func (p plan) Tables() []Table {
 		tables[r.Parent().Name()] = r.Parent()
 	}
 
+	for _, t := range p.roots {
+		tables[t.Name()] = t
+	}
+	for _, t := range p.references {
+		tables[t.Name()] = t
+	}
+
 	for _, v := range tables {
 		result = append(result, v)
 	}
 	return result
 }
+
+func (p plan) Route(row Row) (Table, Row, *Error) {
+	for _, key := range []string{RootKey, ReferenceKey} {
+		value, ok := row[key]
+		if !ok {
+			continue
+		}
+
+		var table Table
+		if name, isString := value.(string); isString && key == RootKey {
+			table, ok = p.roots[name]
+		} else if isString {
+			table, ok = p.references[name]
+		}
+		if !ok {
+			return nil, row, &Error{Description: fmt.Sprintf("%v is not a %s of the ingress descriptor", value, key)}
+		}
+
+		untagged := Row{}
+		for k, v := range row {
+			if k != key {
+				untagged[k] = v
+			}
+		}
+		return table, untagged, nil
+	}
+	return p.firstTable, row, nil
+}
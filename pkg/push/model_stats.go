@@ -0,0 +1,76 @@
+// Copyright (C) 2021 CGI France
+//
+// This file is part of LINO.
+//
+// LINO is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// LINO is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with LINO.  If not, see <http://www.gnu.org/licenses/>.
+
+package push
+
+import (
+	"time"
+)
+
+// TableStats are the counters of a table during a push.
+type TableStats struct {
+	Read       uint
+	Inserted   uint
+	Updated    uint
+	Deleted    uint
+	Duplicates uint
+	Failed     uint
+	Duration   time.Duration
+}
+
+// Stats of a push run.
+type Stats struct {
+	Tables   map[string]*TableStats
+	Duration time.Duration
+}
+
+// NewStats initialize an empty Stats object.
+func NewStats() Stats {
+	return Stats{Tables: map[string]*TableStats{}}
+}
+
+// Table returns the counters of a table, created on first access.
+func (s Stats) Table(name string) *TableStats {
+	ts, ok := s.Tables[name]
+	if !ok {
+		ts = &TableStats{}
+		s.Tables[name] = ts
+	}
+	return ts
+}
+
+// copy returns a deep copy of the counters.
+func (s Stats) copy() Stats {
+	result := Stats{Tables: map[string]*TableStats{}, Duration: s.Duration}
+	for name, ts := range s.Tables {
+		tsCopy := *ts
+		result.Tables[name] = &tsCopy
+	}
+	return result
+}
+
+// written count a row successfully written with the given mode.
+func (ts *TableStats) written(mode Mode) {
+	switch mode {
+	case Delete:
+		ts.Deleted++
+	case Update:
+		ts.Updated++
+	default:
+		ts.Inserted++
+	}
+}
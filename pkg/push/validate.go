@@ -0,0 +1,234 @@
+// Copyright (C) 2021 CGI France
+//
+// This file is part of LINO.
+//
+// LINO is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// LINO is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with LINO.  If not, see <http://www.gnu.org/licenses/>.
+
+package push
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Validate check rows against the destination columns without writing anything, only the first limit rows are read (0 to read all rows).
+func Validate(ri RowIterator, plan Plan, mode Mode, reader MetadataReader, limit uint) ([]Incompatibility, *Error) {
+	defer ri.Close()
+
+	v := validator{
+		plan:    plan,
+		mode:    mode,
+		reader:  reader,
+		columns: map[string]map[string]Column{},
+		found:   map[string]*Incompatibility{},
+		seen:    NewKeySet(),
+	}
+
+	line := uint(0)
+	for ri.Next() {
+		line++
+		table, row, err := plan.Route(*ri.Value())
+		if err != nil {
+			v.report(plan.FirstTable().Name(), "", err.Description, line)
+		} else if err := v.validateRow(row, table, line); err != nil {
+			return nil, err
+		}
+		if limit > 0 && line >= limit {
+			break
+		}
+	}
+
+	if ri.Error() != nil {
+		return nil, ri.Error()
+	}
+
+	log.Info().Msg(fmt.Sprintf("%v row(s) validated", line))
+
+	return v.result(), nil
+}
+
+type validator struct {
+	plan    Plan
+	mode    Mode
+	reader  MetadataReader
+	columns map[string]map[string]Column
+	found   map[string]*Incompatibility
+	seen    KeySet
+}
+
+func (v validator) tableColumns(table Table) (map[string]Column, *Error) {
+	if columns, ok := v.columns[table.Name()]; ok {
+		return columns, nil
+	}
+
+	list, err := v.reader.Columns(table)
+	if err != nil {
+		return nil, err
+	}
+
+	columns := map[string]Column{}
+	for _, column := range list {
+		columns[column.Name] = column
+	}
+	v.columns[table.Name()] = columns
+
+	return columns, nil
+}
+
+func (v validator) validateRow(row Row, table Table, line uint) *Error {
+	frow, frel, fInverseRel, err := FilterRelation(row, v.plan.RelationsFromTable(table), v.seen)
+	if err != nil {
+		v.report(table.Name(), "", err.Description, line)
+		return nil
+	}
+
+	columns, err := v.tableColumns(table)
+	if err != nil {
+		return err
+	}
+
+	if len(columns) == 0 {
+		v.report(table.Name(), "", "table not found in destination", line)
+	} else {
+		v.validateColumns(frow, table, columns, line)
+	}
+	v.seen.Add(table, frow)
+
+	for relName, subRow := range frel {
+		rel := v.plan.RelationsFromTable(table)[relName]
+		if err := v.validateRow(subRow, rel.OppositeOf(table), line); err != nil {
+			return err
+		}
+	}
+
+	for relName, subArray := range fInverseRel {
+		rel := v.plan.RelationsFromTable(table)[relName]
+		for _, subRow := range subArray {
+			if err := v.validateRow(subRow, rel.OppositeOf(table), line); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (v validator) validateColumns(row Row, table Table, columns map[string]Column, line uint) {
+	for _, pk := range table.PrimaryKey() {
+		if _, ok := row[pk]; !ok && (v.mode == Delete || v.mode == Update) {
+			v.report(table.Name(), pk, "missing primary key value", line)
+		}
+	}
+
+	if v.mode == Delete {
+		return
+	}
+
+	for name, value := range row {
+		column, ok := columns[name]
+		if !ok {
+			v.report(table.Name(), name, "unknown column", line)
+			continue
+		}
+		if value == nil {
+			if !column.Nullable {
+				v.report(table.Name(), name, "null value in NOT NULL column", line)
+			}
+			continue
+		}
+		if !isCompatible(column.Type, value) {
+			v.report(table.Name(), name, fmt.Sprintf("%T value is not compatible with type %s", value, column.Type), line)
+		}
+	}
+
+	if v.mode == Update {
+		return
+	}
+
+	for name, column := range columns {
+		if _, ok := row[name]; !ok && !column.Nullable && !column.HasDefault {
+			v.report(table.Name(), name, "missing value for NOT NULL column without default", line)
+		}
+	}
+}
+
+func (v validator) report(table, column, reason string, line uint) {
+	key := table + "\x00" + column + "\x00" + reason
+	if incompatibility, ok := v.found[key]; ok {
+		incompatibility.Count++
+		return
+	}
+	v.found[key] = &Incompatibility{Table: table, Column: column, Reason: reason, Count: 1, FirstLine: line}
+}
+
+func (v validator) result() []Incompatibility {
+	result := []Incompatibility{}
+	for _, incompatibility := range v.found {
+		result = append(result, *incompatibility)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Table != result[j].Table {
+			return result[i].Table < result[j].Table
+		}
+		if result[i].Column != result[j].Column {
+			return result[i].Column < result[j].Column
+		}
+		return result[i].Reason < result[j].Reason
+	})
+	return result
+}
+
+// isCompatible returns false if the value cannot be loaded in a column of the given SQL type.
+func isCompatible(columnType string, value Value) bool {
+	t := strings.ToLower(columnType)
+
+	switch value.(type) {
+	case map[string]interface{}, []interface{}:
+		return strings.Contains(t, "json") || strings.Contains(t, "array") || strings.HasPrefix(t, "_")
+	}
+
+	switch {
+	case strings.Contains(t, "json"), strings.Contains(t, "interval"), strings.Contains(t, "point"):
+		return true
+	case strings.Contains(t, "bool"):
+		switch tv := value.(type) {
+		case bool:
+			return true
+		case string:
+			_, err := strconv.ParseBool(tv)
+			return err == nil
+		}
+		return false
+	case strings.Contains(t, "int"), strings.Contains(t, "num"), strings.Contains(t, "dec"),
+		strings.Contains(t, "float"), strings.Contains(t, "double"), strings.Contains(t, "real"):
+		switch tv := value.(type) {
+		case float64, float32, int, int64, int32, uint, uint64, uint32, json.Number:
+			return true
+		case string:
+			_, err := strconv.ParseFloat(tv, 64)
+			return err == nil
+		}
+		return false
+	case strings.Contains(t, "date"), strings.Contains(t, "time"):
+		_, ok := value.(string)
+		return ok
+	}
+
+	return true
+}
@@ -0,0 +1,77 @@
+// Copyright (C) 2021 CGI France
+//
+// This file is part of LINO.
+//
+// LINO is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// LINO is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with LINO.  If not, see <http://www.gnu.org/licenses/>.
+
+package push_test
+
+import (
+	"testing"
+
+	"github.com/cgi-fr/lino/pkg/push"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidate(t *testing.T) {
+	A := push.NewTable("A", []string{"id"})
+	B := makeTable("B")
+
+	plan := push.NewPlan(A, []push.Relation{makeRel(A, B)})
+	ri := rowIterator{limit: 10, row: push.Row{
+		"id":      "one",
+		"name":    nil,
+		"unknown": true,
+		"A->B": map[string]interface{}{
+			"age": 42,
+		},
+	}}
+	reader := memoryMetadataReader{map[string][]push.Column{
+		"A": {
+			{Name: "id", Type: "integer"},
+			{Name: "name", Type: "varchar"},
+			{Name: "created", Type: "timestamp", HasDefault: true},
+			{Name: "email", Type: "varchar"},
+		},
+	}}
+
+	result, err := push.Validate(&ri, plan, push.Insert, &reader, 4)
+
+	assert.Nil(t, err)
+	assert.Equal(t, []push.Incompatibility{
+		{Table: "A", Column: "email", Reason: "missing value for NOT NULL column without default", Count: 4, FirstLine: 1},
+		{Table: "A", Column: "id", Reason: "string value is not compatible with type integer", Count: 4, FirstLine: 1},
+		{Table: "A", Column: "name", Reason: "null value in NOT NULL column", Count: 4, FirstLine: 1},
+		{Table: "A", Column: "unknown", Reason: "unknown column", Count: 4, FirstLine: 1},
+		{Table: "B", Column: "", Reason: "table not found in destination", Count: 4, FirstLine: 1},
+	}, result)
+}
+
+func TestValidateNoIncompatibility(t *testing.T) {
+	A := push.NewTable("A", []string{"id"})
+
+	plan := push.NewPlan(A, []push.Relation{})
+	ri := rowIterator{limit: 10, row: push.Row{"id": 1.0, "name": nil}}
+	reader := memoryMetadataReader{map[string][]push.Column{
+		"A": {
+			{Name: "id", Type: "integer"},
+			{Name: "name", Type: "varchar", Nullable: true},
+		},
+	}}
+
+	result, err := push.Validate(&ri, plan, push.Update, &reader, 0)
+
+	assert.Nil(t, err)
+	assert.Empty(t, result)
+}
@@ -0,0 +1,53 @@
+// Copyright (C) 2021 CGI France
+//
+// This file is part of LINO.
+//
+// LINO is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// LINO is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with LINO.  If not, see <http://www.gnu.org/licenses/>.
+
+package relation
+
+// Check runs the relations of the storage against the data read by the checker, keeping the keys of at most sampleSize orphans per relation.
+func Check(checker IntegrityChecker, s Storage, sampleSize uint) ([]Integrity, *Error) {
+	defer checker.Close()
+
+	relations, err := s.List()
+	if err != nil {
+		return nil, err
+	}
+
+	result := []Integrity{}
+	for _, rel := range relations {
+		integrity := Integrity{Relation: rel}
+
+		if len(rel.Child.Keys) != len(rel.Parent.Keys) || len(rel.Child.Keys) == 0 {
+			integrity.KeyMismatch = true
+			result = append(result, integrity)
+			continue
+		}
+
+		integrity.Rows, integrity.NullKeys, err = checker.NullKeys(rel)
+		if err != nil {
+			return nil, err
+		}
+
+		integrity.Orphans, integrity.Samples, err = checker.Orphans(rel, sampleSize)
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, integrity)
+	}
+
+	return result, nil
+}
@@ -17,14 +17,44 @@
 
 package relation
 
+import "github.com/cgi-fr/lino/pkg/dataconnector"
+
 // ExtractorFactory exposes methods to create new extractors.
 type ExtractorFactory interface {
-	New(url string, schema string) Extractor
+	New(url string, schema string, options dataconnector.Options) Extractor
 }
 
 // Extractor allows to extract relations from a relational database.
 type Extractor interface {
-	Extract() ([]Relation, *Error)
+	Extract(filter Filter) ([]Relation, *Error)
+}
+
+// InferenceReaderFactory exposes methods to create new inference readers.
+type InferenceReaderFactory interface {
+	New(url string, schema string, options dataconnector.Options) InferenceReader
+}
+
+// InferenceReader reads the columns and samples the data of a relational database to infer relations.
+type InferenceReader interface {
+	// Columns returns the column names of each table
+	Columns() (map[string][]string, *Error)
+	// Coverage returns the ratio of sampleSize non null values of childColumn found in parentColumn
+	Coverage(child string, childColumn string, parent string, parentColumn string, sampleSize uint) (float64, *Error)
+	Close() *Error
+}
+
+// IntegrityCheckerFactory exposes methods to create new integrity checkers.
+type IntegrityCheckerFactory interface {
+	New(url string, schema string, options dataconnector.Options) IntegrityChecker
+}
+
+// IntegrityChecker counts the child rows of a relation that don't honour it.
+type IntegrityChecker interface {
+	// NullKeys returns the number of child rows and the number of them with a null key column
+	NullKeys(rel Relation) (uint, uint, *Error)
+	// Orphans returns the number of child rows with non null keys not found in the parent table and the keys of at most sampleSize of them
+	Orphans(rel Relation, sampleSize uint) (uint, [][]string, *Error)
+	Close() *Error
 }
 
 // Storage allows to store and retrieve Relations objects.
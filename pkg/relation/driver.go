@@ -17,9 +17,16 @@
 
 package relation
 
-// Extract relations from a relational database.
-func Extract(e Extractor, s Storage) *Error {
-	relations, err := e.Extract()
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/cgi-fr/lino/pkg/identifier"
+)
+
+// Extract relations from a relational database, restricted to the child tables matching filter.
+func Extract(e Extractor, s Storage, filter Filter) *Error {
+	relations, err := extract(e, filter)
 	if err != nil {
 		return err
 	}
@@ -29,3 +36,184 @@ func Extract(e Extractor, s Storage) *Error {
 	}
 	return nil
 }
+
+// Merge relations extracted from a relational database with the stored relations.
+// Extracted relations are added, updated or removed, manual relations are kept even if an extracted relation has the same name.
+// Relations of child tables outside of the filter are kept, and so are relations without source that are not extracted.
+func Merge(e Extractor, s Storage, filter Filter) ([]Change, *Error) {
+	extracted, err := extract(e, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	stored, err := s.List()
+	if err != nil {
+		return nil, err
+	}
+
+	emap := map[string]Relation{}
+	for _, rel := range extracted {
+		emap[rel.Name] = rel
+	}
+
+	changes := []Change{}
+	merged := []Relation{}
+	known := map[string]bool{}
+
+	for _, rel := range stored {
+		known[rel.Name] = true
+
+		if rel.Source == Manual {
+			merged = append(merged, rel)
+			continue
+		}
+
+		newRel, ok := emap[rel.Name]
+		if !ok {
+			switch {
+			case !filter.Contains(rel.Child.Name):
+				// the child table was not extracted
+				merged = append(merged, rel)
+			case rel.Source == "":
+				changes = append(changes, Change{Kind: RelationKept, Relation: rel.Name, Message: fmt.Sprintf("relation %s has no source and is not in the database, it is kept", rel.Name)})
+				merged = append(merged, rel)
+			default:
+				changes = append(changes, Change{Kind: RelationRemoved, Relation: rel.Name, Message: fmt.Sprintf("relation %s no longer exists", rel.Name)})
+			}
+			continue
+		}
+
+		if !reflect.DeepEqual(rel.Parent, newRel.Parent) || !reflect.DeepEqual(rel.Child, newRel.Child) {
+			changes = append(changes, Change{Kind: RelationChanged, Relation: rel.Name, Message: fmt.Sprintf("relation %s changed from %s to %s", rel.Name, describe(rel), describe(newRel))})
+		}
+		merged = append(merged, newRel)
+	}
+
+	for _, rel := range extracted {
+		if known[rel.Name] {
+			continue
+		}
+		changes = append(changes, Change{Kind: RelationAdded, Relation: rel.Name, Message: fmt.Sprintf("relation %s %s added", rel.Name, describe(rel))})
+		merged = append(merged, rel)
+	}
+
+	err = s.Store(merged)
+	if err != nil {
+		return nil, err
+	}
+	return changes, nil
+}
+
+// extract relations and mark them as extracted
+func extract(e Extractor, filter Filter) ([]Relation, *Error) {
+	relations, err := e.Extract(filter)
+	if err != nil {
+		return nil, err
+	}
+	for i := range relations {
+		relations[i].Source = Extracted
+	}
+	return relations, nil
+}
+
+func describe(rel Relation) string {
+	return fmt.Sprintf("%s %v -> %s %v", rel.Parent.Name, rel.Parent.Keys, rel.Child.Name, rel.Child.Keys)
+}
+
+// Add a relation to the storage, as a manual relation if it has no source.
+// The names must be valid identifiers, the relation must not exist and the parent and the child must have the same number of keys.
+func Add(s Storage, r Relation) *Error {
+	if err := validate(r); err != nil {
+		return err
+	}
+
+	relations, err := s.List()
+	if err != nil {
+		return err
+	}
+
+	for _, rel := range relations {
+		if rel.Name == r.Name {
+			return &Error{Description: fmt.Sprintf("relation %s already exists", r.Name)}
+		}
+	}
+
+	if r.Source == "" {
+		r.Source = Manual
+	}
+
+	return s.Store(append(relations, r))
+}
+
+// Remove a relation from the storage.
+func Remove(s Storage, name string) *Error {
+	relations, err := s.List()
+	if err != nil {
+		return err
+	}
+
+	result := []Relation{}
+	for _, rel := range relations {
+		if rel.Name != name {
+			result = append(result, rel)
+		}
+	}
+
+	if len(result) == len(relations) {
+		return &Error{Description: fmt.Sprintf("no relation named %s", name)}
+	}
+
+	return s.Store(result)
+}
+
+// Get a relation from the storage, nil if it does not exist.
+func Get(s Storage, name string) (*Relation, *Error) {
+	relations, err := s.List()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, rel := range relations {
+		if rel.Name == name {
+			return &rel, nil
+		}
+	}
+	return nil, nil
+}
+
+// List all stored relations.
+func List(s Storage) ([]Relation, *Error) {
+	relations, err := s.List()
+	if err != nil {
+		return nil, err
+	}
+	if relations == nil {
+		relations = []Relation{}
+	}
+	return relations, nil
+}
+
+func validate(r Relation) *Error {
+	if !identifier.Valid(r.Name) {
+		return &Error{Description: fmt.Sprintf("invalid relation name '%s'", r.Name)}
+	}
+
+	for _, t := range []Table{r.Parent, r.Child} {
+		if !identifier.ValidTableName(t.Name) {
+			return &Error{Description: fmt.Sprintf("invalid table name '%s' in relation %s", t.Name, r.Name)}
+		}
+		if len(t.Keys) == 0 {
+			return &Error{Description: fmt.Sprintf("table %s must have at least one key in relation %s", t.Name, r.Name)}
+		}
+		for _, key := range t.Keys {
+			if !identifier.Valid(key) {
+				return &Error{Description: fmt.Sprintf("invalid key '%s' of table %s in relation %s", key, t.Name, r.Name)}
+			}
+		}
+	}
+
+	if len(r.Parent.Keys) != len(r.Child.Keys) {
+		return &Error{Description: fmt.Sprintf("relation %s has %d parent key(s) and %d child key(s)", r.Name, len(r.Parent.Keys), len(r.Child.Keys))}
+	}
+	return nil
+}
@@ -62,7 +62,7 @@ type MockExtractor struct {
 }
 
 // Store a dataconnector in memory
-func (e *MockExtractor) Extract() ([]relation.Relation, *relation.Error) {
+func (e *MockExtractor) Extract(filter relation.Filter) ([]relation.Relation, *relation.Error) {
 	return e.fn()
 }
 
@@ -72,7 +72,7 @@ func TestExtractEmptyDatabase(t *testing.T) {
 		return []relation.Relation{}, nil
 	}}
 
-	err := relation.Extract(Extractor, storage)
+	err := relation.Extract(Extractor, storage, relation.Filter{})
 
 	assert.Nil(t, err, "An error occurred while using Add method")
 	assert.Empty(t, storage.repo, "The relations storage should be empty")
@@ -89,13 +89,14 @@ func TestExtractNonEmptyDatabase(t *testing.T) {
 			Name: "Table2",
 			Keys: []string{"Table2_key"},
 		},
+		Source: relation.Extracted,
 	}
 	storage := &MemoryStorage{}
 	Extractor := &MockExtractor{fn: func() ([]relation.Relation, *relation.Error) {
 		return []relation.Relation{relation1}, nil
 	}}
 
-	err := relation.Extract(Extractor, storage)
+	err := relation.Extract(Extractor, storage, relation.Filter{})
 
 	assert.Nil(t, err, "An error occurred while using Add method")
 	assert.Len(t, storage.repo, 1, "The relations storage should contains 1 relation")
@@ -108,7 +109,7 @@ func TestExtractorror(t *testing.T) {
 		return nil, &relation.Error{Description: "expected error"}
 	}}
 
-	err := relation.Extract(Extractor, storage)
+	err := relation.Extract(Extractor, storage, relation.Filter{})
 
 	assert.NotNil(t, err, "An error should occur while using Extract method")
 	assert.EqualError(t, err, "expected error")
@@ -135,8 +136,230 @@ func TestStoreError(t *testing.T) {
 		},
 	}
 
-	err := relation.Extract(Extractor, storage)
+	err := relation.Extract(Extractor, storage, relation.Filter{})
 
 	assert.NotNil(t, err, "An error should occur while using Extract method")
 	assert.EqualError(t, err, "expected error")
 }
+
+func TestMerge(t *testing.T) {
+	rel := func(name string, parentKey string, source string) relation.Relation {
+		return relation.Relation{
+			Name:   name,
+			Parent: relation.Table{Name: "Table1", Keys: []string{parentKey}},
+			Child:  relation.Table{Name: "Table2", Keys: []string{"Table2_key"}},
+			Source: source,
+		}
+	}
+
+	storage := &MemoryStorage{repo: []relation.Relation{
+		rel("Kept", "key", relation.Extracted),
+		rel("Changed", "old_key", relation.Extracted),
+		rel("Removed", "key", relation.Extracted),
+		rel("Manual", "key", relation.Manual),
+	}}
+	Extractor := &MockExtractor{fn: func() ([]relation.Relation, *relation.Error) {
+		return []relation.Relation{
+			rel("Kept", "key", ""),
+			rel("Changed", "new_key", ""),
+			rel("Added", "key", ""),
+		}, nil
+	}}
+
+	changes, err := relation.Merge(Extractor, storage, relation.Filter{})
+
+	assert.Nil(t, err)
+	assert.Equal(t, []relation.Change{
+		{Kind: relation.RelationChanged, Relation: "Changed", Message: "relation Changed changed from Table1 [old_key] -> Table2 [Table2_key] to Table1 [new_key] -> Table2 [Table2_key]"},
+		{Kind: relation.RelationRemoved, Relation: "Removed", Message: "relation Removed no longer exists"},
+		{Kind: relation.RelationAdded, Relation: "Added", Message: "relation Added Table1 [key] -> Table2 [Table2_key] added"},
+	}, changes)
+	assert.Equal(t, []relation.Relation{
+		rel("Kept", "key", relation.Extracted),
+		rel("Changed", "new_key", relation.Extracted),
+		rel("Manual", "key", relation.Manual),
+		rel("Added", "key", relation.Extracted),
+	}, storage.repo)
+}
+
+func TestInfer(t *testing.T) {
+	reader := &relation.MockInferenceReader{}
+	reader.On("Columns").Return(map[string][]string{
+		"customer": {"id", "store_id", "address_id"},
+		"store":    {"id", "name"},
+		"rental":   {"rental_id", "customer_id", "store_id"},
+	}, nil)
+	reader.On("Coverage", "customer", "store_id", "store", "id", uint(100)).Return(1.0, nil)
+	reader.On("Coverage", "rental", "customer_id", "customer", "id", uint(100)).Return(0.9, nil)
+	reader.On("Coverage", "rental", "store_id", "store", "id", uint(100)).Return(0.0, nil)
+	reader.On("Close").Return(nil)
+
+	keys := map[string][]string{
+		"customer": {"id"},
+		"store":    {"id"},
+		"rental":   {"rental_id"},
+	}
+
+	declared := relation.Relation{
+		Name:   "rental_store",
+		Parent: relation.Table{Name: "store", Keys: []string{"id"}},
+		Child:  relation.Table{Name: "rental", Keys: []string{"store_id"}},
+		Source: relation.Extracted,
+	}
+	storage := &MemoryStorage{repo: []relation.Relation{declared}}
+
+	proposed, err := relation.Infer(reader, keys, relation.DefaultPatterns, 0, 0, storage, false)
+
+	assert.Nil(t, err)
+	assert.Equal(t, []relation.Relation{
+		{
+			Name:       "customer_store_id_store_inferred",
+			Parent:     relation.Table{Name: "store", Keys: []string{"id"}},
+			Child:      relation.Table{Name: "customer", Keys: []string{"store_id"}},
+			Source:     relation.Manual,
+			Confidence: 0.5,
+		},
+		{
+			Name:       "rental_customer_id_customer_inferred",
+			Parent:     relation.Table{Name: "customer", Keys: []string{"id"}},
+			Child:      relation.Table{Name: "rental", Keys: []string{"customer_id"}},
+			Source:     relation.Manual,
+			Confidence: 0.5,
+		},
+	}, proposed)
+	assert.Equal(t, []relation.Relation{declared}, storage.repo)
+
+	proposed, err = relation.Infer(reader, keys, relation.DefaultPatterns, 100, 0.95, storage, true)
+
+	assert.Nil(t, err)
+	assert.Len(t, proposed, 1)
+	assert.Equal(t, "customer_store_id_store_inferred", proposed[0].Name)
+	assert.Equal(t, 1.0, proposed[0].Confidence)
+	assert.Equal(t, []relation.Relation{declared, proposed[0]}, storage.repo)
+}
+
+func TestCheck(t *testing.T) {
+	valid := relation.Relation{
+		Name:   "rental_customer",
+		Parent: relation.Table{Name: "customer", Keys: []string{"id"}},
+		Child:  relation.Table{Name: "rental", Keys: []string{"customer_id"}},
+	}
+	broken := relation.Relation{
+		Name:   "rental_store",
+		Parent: relation.Table{Name: "store", Keys: []string{"id"}},
+		Child:  relation.Table{Name: "rental", Keys: []string{"store_id"}},
+	}
+	mismatch := relation.Relation{
+		Name:   "rental_inventory",
+		Parent: relation.Table{Name: "inventory", Keys: []string{"film_id", "store_id"}},
+		Child:  relation.Table{Name: "rental", Keys: []string{"inventory_id"}},
+	}
+
+	checker := &relation.MockIntegrityChecker{}
+	checker.On("NullKeys", valid).Return(uint(10), uint(0), nil)
+	checker.On("Orphans", valid, uint(3)).Return(uint(0), [][]string{}, nil)
+	checker.On("NullKeys", broken).Return(uint(10), uint(2), nil)
+	checker.On("Orphans", broken, uint(3)).Return(uint(4), [][]string{{"7"}, {"8"}, {"9"}}, nil)
+	checker.On("Close").Return(nil)
+
+	storage := &MemoryStorage{repo: []relation.Relation{valid, broken, mismatch}}
+
+	result, err := relation.Check(checker, storage, 3)
+
+	assert.Nil(t, err)
+	assert.Equal(t, []relation.Integrity{
+		{Relation: valid, Rows: 10, Samples: [][]string{}},
+		{Relation: broken, Rows: 10, NullKeys: 2, Orphans: 4, Samples: [][]string{{"7"}, {"8"}, {"9"}}},
+		{Relation: mismatch, KeyMismatch: true},
+	}, result)
+	checker.AssertExpectations(t)
+}
+
+func TestAddRemoveRelation(t *testing.T) {
+	storage := &MemoryStorage{}
+
+	rel := relation.Relation{
+		Name:   "film_language",
+		Parent: relation.Table{Name: "public.language", Keys: []string{"language_id"}},
+		Child:  relation.Table{Name: "public.film", Keys: []string{"language_id"}},
+	}
+
+	err := relation.Add(storage, rel)
+	assert.Nil(t, err)
+	rel.Source = relation.Manual
+	assert.Equal(t, []relation.Relation{rel}, storage.repo)
+
+	err = relation.Add(storage, rel)
+	assert.Equal(t, "relation film_language already exists", err.Description)
+
+	arity := relation.Relation{
+		Name:   "film_inventory",
+		Parent: relation.Table{Name: "inventory", Keys: []string{"film_id", "store_id"}},
+		Child:  relation.Table{Name: "film", Keys: []string{"film_id"}},
+	}
+	err = relation.Add(storage, arity)
+	assert.Equal(t, "relation film_inventory has 2 parent key(s) and 1 child key(s)", err.Description)
+
+	arity.Name = "film inventory"
+	err = relation.Add(storage, arity)
+	assert.Equal(t, "invalid relation name 'film inventory'", err.Description)
+
+	got, err := relation.Get(storage, "film_language")
+	assert.Nil(t, err)
+	assert.Equal(t, &rel, got)
+
+	err = relation.Remove(storage, "film_language")
+	assert.Nil(t, err)
+	assert.Equal(t, []relation.Relation{}, storage.repo)
+
+	err = relation.Remove(storage, "film_language")
+	assert.Equal(t, "no relation named film_language", err.Description)
+}
+
+func TestMergeKeepsOutOfScopeAndUnmarked(t *testing.T) {
+	rel := func(name string, child string, source string) relation.Relation {
+		return relation.Relation{
+			Name:   name,
+			Parent: relation.Table{Name: "sales.customer", Keys: []string{"id"}},
+			Child:  relation.Table{Name: child, Keys: []string{"customer_id"}},
+			Source: source,
+		}
+	}
+
+	storage := &MemoryStorage{repo: []relation.Relation{
+		rel("OutOfSchema", "stock.delivery", relation.Extracted),
+		rel("Excluded", "sales.audit_log", relation.Extracted),
+		rel("Unmarked", "sales.invoice", ""),
+		rel("UnmarkedExtracted", "sales.payment", ""),
+		rel("Removed", "sales.rental", relation.Extracted),
+	}}
+	Extractor := &MockExtractor{fn: func() ([]relation.Relation, *relation.Error) {
+		return []relation.Relation{
+			rel("UnmarkedExtracted", "sales.payment", ""),
+		}, nil
+	}}
+
+	changes, err := relation.Merge(Extractor, storage, relation.Filter{Schemas: []string{"sales"}, Exclude: []string{"audit_*"}})
+
+	assert.Nil(t, err)
+	assert.Equal(t, []relation.Change{
+		{Kind: relation.RelationKept, Relation: "Unmarked", Message: "relation Unmarked has no source and is not in the database, it is kept"},
+		{Kind: relation.RelationRemoved, Relation: "Removed", Message: "relation Removed no longer exists"},
+	}, changes)
+	assert.Equal(t, []relation.Relation{
+		rel("OutOfSchema", "stock.delivery", relation.Extracted),
+		rel("Excluded", "sales.audit_log", relation.Extracted),
+		rel("Unmarked", "sales.invoice", ""),
+		rel("UnmarkedExtracted", "sales.payment", relation.Extracted),
+	}, storage.repo)
+}
+
+func TestFilterContains(t *testing.T) {
+	assert.True(t, relation.Filter{}.Contains("customer"))
+	assert.False(t, relation.Filter{}.Contains("sales.customer"))
+	assert.True(t, relation.Filter{Schemas: []string{"sales"}}.Contains("sales.customer"))
+	assert.False(t, relation.Filter{Schemas: []string{"sales"}}.Contains("customer"))
+	assert.True(t, relation.Filter{Include: []string{"cust*", "/^store_[0-9]+$/"}}.Contains("store_12"))
+	assert.False(t, relation.Filter{Include: []string{"cust?"}}.Contains("customer"))
+	assert.False(t, relation.Filter{Exclude: []string{"cust*"}}.Contains("customer"))
+}
@@ -0,0 +1,73 @@
+// Copyright (C) 2021 CGI France
+//
+// This file is part of LINO.
+//
+// LINO is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// LINO is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with LINO.  If not, see <http://www.gnu.org/licenses/>.
+
+package relation
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Contains returns true if the table is in the scope of the filter.
+// The name of the table is prefixed by its schema if the filter has schemas, as the extracted relations.
+func (f Filter) Contains(name string) bool {
+	schema, table := "", name
+	if i := strings.LastIndex(name, "."); i >= 0 {
+		schema, table = name[:i], name[i+1:]
+	}
+
+	if len(f.Schemas) == 0 && schema != "" {
+		return false
+	}
+
+	if len(f.Schemas) > 0 && !contains(f.Schemas, schema) {
+		return false
+	}
+
+	if len(f.Include) > 0 && !match(table, f.Include) {
+		return false
+	}
+
+	return !match(table, f.Exclude)
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// match returns true if the name matches one of the patterns, a pattern between slashes is a regular expression, else a glob.
+func match(name string, patterns []string) bool {
+	for _, pattern := range patterns {
+		var expr string
+		if len(pattern) > 1 && strings.HasPrefix(pattern, "/") && strings.HasSuffix(pattern, "/") {
+			expr = pattern[1 : len(pattern)-1]
+		} else {
+			expr = "^" + strings.NewReplacer(`\*`, ".*", `\?`, ".").Replace(regexp.QuoteMeta(pattern)) + "$"
+		}
+
+		re, err := regexp.Compile(expr)
+		if err == nil && re.MatchString(name) {
+			return true
+		}
+	}
+	return false
+}
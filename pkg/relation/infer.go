@@ -0,0 +1,148 @@
+// Copyright (C) 2021 CGI France
+//
+// This file is part of LINO.
+//
+// LINO is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// LINO is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with LINO.  If not, see <http://www.gnu.org/licenses/>.
+
+package relation
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// DefaultPatterns are the names of a column referencing the primary key of a table,
+// {table} is replaced by the table name without schema and {key} by the primary key column.
+var DefaultPatterns = []string{"{key}", "{table}_{key}", "{table}_id"}
+
+// candidate is a column whose name matches the primary key of a parent table.
+type candidate struct {
+	child  string
+	column string
+	parent string
+	key    string
+}
+
+// Infer proposes relations between the tables by matching the column names with the patterns built from the primary keys of tables (single column keys only).
+// Without sampling (sampleSize 0), the confidence of a candidate is 0.5 divided by the number of tables matched by the column.
+// With sampling, it is the ratio of sampled values found in the parent table divided by the number of tables matched by the column.
+// Candidates with a confidence lower than minConfidence or already declared in the storage are dropped, the others are stored as manual relations if store is true.
+func Infer(reader InferenceReader, keys map[string][]string, patterns []string, sampleSize uint, minConfidence float64, s Storage, store bool) ([]Relation, *Error) {
+	defer reader.Close()
+
+	columns, err := reader.Columns()
+	if err != nil {
+		return nil, err
+	}
+
+	relations, err := s.List()
+	if err != nil {
+		return nil, err
+	}
+
+	declared := map[string]bool{}
+	for _, rel := range relations {
+		declared[declaredKey(rel.Child.Name, rel.Child.Keys, rel.Parent.Name, rel.Parent.Keys)] = true
+	}
+
+	parents := []string{}
+	for name, pk := range keys {
+		if len(pk) == 1 {
+			parents = append(parents, name)
+		}
+	}
+	sort.Strings(parents)
+
+	children := []string{}
+	for name := range columns {
+		children = append(children, name)
+	}
+	sort.Strings(children)
+
+	proposed := []Relation{}
+	for _, child := range children {
+		for _, column := range columns[child] {
+			candidates := []candidate{}
+			for _, parent := range parents {
+				key := keys[parent][0]
+				if shortName(parent) == shortName(child) && strings.EqualFold(column, key) {
+					continue
+				}
+				if declared[declaredKey(child, []string{column}, parent, []string{key})] {
+					continue
+				}
+				if matchPatterns(column, shortName(parent), key, patterns) {
+					candidates = append(candidates, candidate{child, column, parent, key})
+				}
+			}
+
+			for _, c := range candidates {
+				confidence := 0.5
+				if sampleSize > 0 {
+					coverage, err := reader.Coverage(c.child, c.column, c.parent, c.key, sampleSize)
+					if err != nil {
+						return nil, err
+					}
+					confidence = coverage
+				}
+				confidence /= float64(len(candidates))
+
+				if confidence < minConfidence || confidence == 0 {
+					continue
+				}
+
+				proposed = append(proposed, Relation{
+					Name:       fmt.Sprintf("%s_%s_%s_inferred", shortName(c.child), c.column, shortName(c.parent)),
+					Parent:     Table{Name: c.parent, Keys: []string{c.key}},
+					Child:      Table{Name: c.child, Keys: []string{c.column}},
+					Source:     Manual,
+					Confidence: confidence,
+				})
+			}
+		}
+	}
+
+	if store {
+		err = s.Store(append(relations, proposed...))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return proposed, nil
+}
+
+// matchPatterns returns true if the column name is built by a pattern, names that don't contain the table name (like a generic id key) are ignored.
+func matchPatterns(column string, table string, key string, patterns []string) bool {
+	for _, pattern := range patterns {
+		name := strings.NewReplacer("{table}", table, "{key}", key).Replace(pattern)
+		if !strings.Contains(strings.ToLower(name), strings.ToLower(table)) {
+			continue
+		}
+		if strings.EqualFold(column, name) {
+			return true
+		}
+	}
+	return false
+}
+
+func declaredKey(child string, childKeys []string, parent string, parentKeys []string) string {
+	return strings.ToLower(fmt.Sprintf("%s%v%s%v", shortName(child), childKeys, shortName(parent), parentKeys))
+}
+
+// shortName returns the table name without schema
+func shortName(table string) string {
+	return table[strings.LastIndex(table, ".")+1:]
+}
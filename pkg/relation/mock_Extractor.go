@@ -9,13 +9,13 @@ type MockExtractor struct {
 	mock.Mock
 }
 
-// Extract provides a mock function with given fields:
-func (_m *MockExtractor) Extract() ([]Relation, *Error) {
-	ret := _m.Called()
+// Extract provides a mock function with given fields: filter
+func (_m *MockExtractor) Extract(filter Filter) ([]Relation, *Error) {
+	ret := _m.Called(filter)
 
 	var r0 []Relation
-	if rf, ok := ret.Get(0).(func() []Relation); ok {
-		r0 = rf()
+	if rf, ok := ret.Get(0).(func(Filter) []Relation); ok {
+		r0 = rf(filter)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]Relation)
@@ -23,8 +23,8 @@ func (_m *MockExtractor) Extract() ([]Relation, *Error) {
 	}
 
 	var r1 *Error
-	if rf, ok := ret.Get(1).(func() *Error); ok {
-		r1 = rf()
+	if rf, ok := ret.Get(1).(func(Filter) *Error); ok {
+		r1 = rf(filter)
 	} else {
 		if ret.Get(1) != nil {
 			r1 = ret.Get(1).(*Error)
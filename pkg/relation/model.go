@@ -24,10 +24,57 @@ type Table struct {
 }
 
 // Relation holds a parent Table and a child Table.
+// Confidence is the score between 0 and 1 of an inferred relation, 0 for other relations.
 type Relation struct {
-	Name   string
-	Parent Table
-	Child  Table
+	Name       string
+	Parent     Table
+	Child      Table
+	Source     string
+	Confidence float64
+}
+
+// Sources of a relation, the source is empty for relations stored before the marker.
+const (
+	// Extracted relations come from the foreign key constraints of the database
+	Extracted = "extracted"
+	// Manual relations are written by hand in relations.yaml
+	Manual = "manual"
+)
+
+// Kinds of change made to the relations by a merge.
+const (
+	RelationAdded   = "added"
+	RelationRemoved = "removed"
+	RelationChanged = "changed"
+	RelationKept    = "kept"
+)
+
+// Change made to the relations by a merge.
+type Change struct {
+	Kind     string
+	Relation string
+	Message  string
+}
+
+// Integrity is the result of the check of a relation against the data of the database.
+// Rows is the number of child rows, NullKeys the number of them with a null key column
+// and Orphans the number of them with non null keys not found in the parent table, Samples holds the keys of some orphans.
+// KeyMismatch is true if the child and the parent don't have the same number of key columns, the data is not checked then.
+type Integrity struct {
+	Relation    Relation
+	Rows        uint
+	NullKeys    uint
+	Orphans     uint
+	Samples     [][]string
+	KeyMismatch bool
+}
+
+// Filter restricts the extraction to the relations of the child tables of Schemas (the dataconnector schema if empty) whose name matches an Include pattern (all if empty) and no Exclude pattern.
+// A pattern is a glob (with * and ?) or a regular expression between slashes (/^sales_/).
+type Filter struct {
+	Schemas []string
+	Include []string
+	Exclude []string
 }
 
 // Error is the error type returned by the domain.
@@ -0,0 +1,68 @@
+// Copyright (C) 2021 CGI France
+//
+// This file is part of LINO.
+//
+// LINO is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// LINO is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with LINO.  If not, see <http://www.gnu.org/licenses/>.
+
+package retry
+
+import (
+	"time"
+)
+
+// DefaultCodes are the deadlock and serialization failure codes of PostgreSQL and Oracle.
+var DefaultCodes = []string{"40001", "40P01", "ORA-00060", "ORA-08177"}
+
+// maxBackoffShift bounds the exponential growth of the delay between two attempts.
+const maxBackoffShift = 16
+
+// Policy describe how transient database errors are retried.
+type Policy struct {
+	Attempts uint
+	Backoff  time.Duration
+	Codes    []string
+}
+
+// None policy never retries.
+var None = Policy{}
+
+// NewPolicy initialize a new Policy object.
+func NewPolicy(attempts uint, backoff time.Duration, codes []string) Policy {
+	return Policy{Attempts: attempts, Backoff: backoff, Codes: codes}
+}
+
+// IsRetryable returns true if the error code is one of the retryable codes.
+func (p Policy) IsRetryable(code string) bool {
+	if code == "" {
+		return false
+	}
+	for _, c := range p.Codes {
+		if c == code {
+			return true
+		}
+	}
+	return false
+}
+
+// Delay returns the time to wait before the given attempt (starting at 1), doubled at each attempt.
+func (p Policy) Delay(attempt uint) time.Duration {
+	shift := attempt - 1
+	if attempt == 0 {
+		shift = 0
+	}
+	if shift > maxBackoffShift {
+		shift = maxBackoffShift
+	}
+	return p.Backoff << shift
+}
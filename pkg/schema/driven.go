@@ -0,0 +1,50 @@
+// Copyright (C) 2021 CGI France
+//
+// This file is part of LINO.
+//
+// LINO is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// LINO is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with LINO.  If not, see <http://www.gnu.org/licenses/>.
+
+package schema
+
+import "github.com/cgi-fr/lino/pkg/dataconnector"
+
+// ExtractorFactory exposes methods to create new extractors.
+type ExtractorFactory interface {
+	New(url string, schema string, options dataconnector.Options) Extractor
+}
+
+// Extractor allows to extract the structure of a relational database.
+type Extractor interface {
+	Extract() (Schema, *Error)
+}
+
+// ApplierFactory exposes methods to create new appliers, a dry run applier returns the statements without executing them.
+type ApplierFactory interface {
+	New(url string, schema string, options dataconnector.Options, dryRun bool) Applier
+}
+
+// Applier creates the structure of a relational database, each method returns the executed statement.
+type Applier interface {
+	CreateSequence(sequence Sequence) (string, *Error)
+	// CreateTable creates the table with its foreign keys
+	CreateTable(table Table) (string, *Error)
+	AddForeignKey(table string, foreignKey ForeignKey) (string, *Error)
+	Close() *Error
+}
+
+// Storage allows to store and retrieve Schema objects.
+type Storage interface {
+	Read() (Schema, *Error)
+	Write(schema Schema) *Error
+}
@@ -0,0 +1,160 @@
+// Copyright (C) 2021 CGI France
+//
+// This file is part of LINO.
+//
+// LINO is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// LINO is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with LINO.  If not, see <http://www.gnu.org/licenses/>.
+
+package schema
+
+import "sort"
+
+// Extract the structure of a relational database to the storage.
+func Extract(e Extractor, s Storage) (Schema, *Error) {
+	schema, err := e.Extract()
+	if err != nil {
+		return Schema{}, err
+	}
+
+	err = s.Write(schema)
+	if err != nil {
+		return Schema{}, err
+	}
+	return schema, nil
+}
+
+// Apply the stored structure with the applier and return the executed statements.
+// Sequences are created first, then tables are created after the tables they reference.
+// Foreign keys of a cycle are added once all the tables of the cycle exist.
+func Apply(s Storage, a Applier) ([]string, *Error) {
+	defer a.Close()
+
+	schema, err := s.Read()
+	if err != nil {
+		return nil, err
+	}
+
+	statements := []string{}
+	for _, sequence := range schema.Sequences {
+		statement, err := a.CreateSequence(sequence)
+		if err != nil {
+			return statements, err
+		}
+		statements = append(statements, statement)
+	}
+
+	tables, deferred := order(schema.Tables)
+
+	for _, table := range tables {
+		statement, err := a.CreateTable(table)
+		if err != nil {
+			return statements, err
+		}
+		statements = append(statements, statement)
+	}
+
+	for _, table := range deferred {
+		for _, foreignKey := range table.ForeignKeys {
+			statement, err := a.AddForeignKey(table.Name, foreignKey)
+			if err != nil {
+				return statements, err
+			}
+			statements = append(statements, statement)
+		}
+	}
+
+	return statements, nil
+}
+
+// order sorts the tables so that a table comes after the tables it references.
+// The foreign keys referencing a table not created yet because of a cycle are removed from the table and returned as deferred tables.
+func order(tables []Table) ([]Table, []Table) {
+	pending := map[string]Table{}
+	names := []string{}
+	for _, table := range tables {
+		pending[table.Name] = table
+		names = append(names, table.Name)
+	}
+	sort.Strings(names)
+
+	created := map[string]bool{}
+	result := []Table{}
+	deferred := []Table{}
+
+	ready := func(table Table) bool {
+		for _, foreignKey := range table.ForeignKeys {
+			_, known := pending[foreignKey.References]
+			if known && foreignKey.References != table.Name && !created[foreignKey.References] {
+				return false
+			}
+		}
+		return true
+	}
+
+	for len(result) < len(tables) {
+		next := ""
+		for _, name := range names {
+			if !created[name] && ready(pending[name]) {
+				next = name
+				break
+			}
+		}
+
+		table := Table{}
+		if next == "" {
+			// cycle: create a table of the cycle, its foreign keys to pending tables are deferred
+			next = inCycle(names, pending, created)
+			table = pending[next]
+			inline, later := []ForeignKey{}, []ForeignKey{}
+			for _, foreignKey := range table.ForeignKeys {
+				if _, known := pending[foreignKey.References]; known && foreignKey.References != next && !created[foreignKey.References] {
+					later = append(later, foreignKey)
+				} else {
+					inline = append(inline, foreignKey)
+				}
+			}
+			table.ForeignKeys = inline
+			deferred = append(deferred, Table{Name: next, ForeignKeys: later})
+		} else {
+			table = pending[next]
+		}
+
+		created[next] = true
+		result = append(result, table)
+	}
+
+	return result, deferred
+}
+
+// inCycle returns a table of a dependency cycle by following the references of the first table not created until a table is visited twice.
+func inCycle(names []string, pending map[string]Table, created map[string]bool) string {
+	current := ""
+	for _, name := range names {
+		if !created[name] {
+			current = name
+			break
+		}
+	}
+
+	visited := map[string]bool{}
+	for !visited[current] {
+		visited[current] = true
+		for _, foreignKey := range pending[current].ForeignKeys {
+			if _, known := pending[foreignKey.References]; known && foreignKey.References != current && !created[foreignKey.References] {
+				current = foreignKey.References
+				break
+			}
+		}
+	}
+	return current
+}
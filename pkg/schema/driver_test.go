@@ -0,0 +1,85 @@
+// Copyright (C) 2021 CGI France
+//
+// This file is part of LINO.
+//
+// LINO is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// LINO is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with LINO.  If not, see <http://www.gnu.org/licenses/>.
+
+package schema_test
+
+import (
+	"testing"
+
+	"github.com/cgi-fr/lino/pkg/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestExtract(t *testing.T) {
+	extracted := schema.Schema{
+		Tables: []schema.Table{{Name: "film", Columns: []schema.Column{{Name: "film_id", Type: "integer"}}, PrimaryKey: []string{"film_id"}}},
+	}
+
+	extractor := &schema.MockExtractor{}
+	extractor.On("Extract").Return(extracted, nil)
+
+	storage := &schema.MockStorage{}
+	storage.On("Write", extracted).Return(nil)
+
+	result, err := schema.Extract(extractor, storage)
+
+	assert.Nil(t, err)
+	assert.Equal(t, extracted, result)
+	storage.AssertExpectations(t)
+}
+
+func TestApplyDependencyOrder(t *testing.T) {
+	storeManager := schema.ForeignKey{Name: "store_manager", Columns: []string{"manager_id"}, References: "staff", ReferencedColumns: []string{"staff_id"}}
+	staffStore := schema.ForeignKey{Name: "staff_store", Columns: []string{"store_id"}, References: "store", ReferencedColumns: []string{"store_id"}}
+	rentalStaff := schema.ForeignKey{Name: "rental_staff", Columns: []string{"staff_id"}, References: "staff", ReferencedColumns: []string{"staff_id"}}
+	staffBoss := schema.ForeignKey{Name: "staff_boss", Columns: []string{"boss_id"}, References: "staff", ReferencedColumns: []string{"staff_id"}}
+	storeCountry := schema.ForeignKey{Name: "store_country", Columns: []string{"country"}, References: "country", ReferencedColumns: []string{"code"}}
+
+	sequence := schema.Sequence{Name: "rental_seq", Start: 1, Increment: 1}
+
+	storage := &schema.MockStorage{}
+	storage.On("Read").Return(schema.Schema{
+		Tables: []schema.Table{
+			{Name: "rental", ForeignKeys: []schema.ForeignKey{rentalStaff}},
+			{Name: "store", ForeignKeys: []schema.ForeignKey{storeManager, storeCountry}},
+			{Name: "staff", ForeignKeys: []schema.ForeignKey{staffStore, staffBoss}},
+		},
+		Sequences: []schema.Sequence{sequence},
+	}, nil)
+
+	calls := []string{}
+	applier := &schema.MockApplier{}
+	applier.On("CreateSequence", sequence).Return("CREATE SEQUENCE rental_seq", nil)
+	applier.On("CreateTable", mock.Anything).Return(func(table schema.Table) string {
+		calls = append(calls, table.Name)
+		for _, foreignKey := range table.ForeignKeys {
+			calls = append(calls, table.Name+"."+foreignKey.Name)
+		}
+		return "CREATE TABLE " + table.Name
+	}, nil)
+	applier.On("AddForeignKey", "staff", staffStore).Return("ALTER TABLE staff", nil)
+	applier.On("Close").Return(nil)
+
+	statements, err := schema.Apply(storage, applier)
+
+	assert.Nil(t, err)
+	// staff and store reference each other, staff is created first and its foreign key to store is added at the end
+	assert.Equal(t, []string{"staff", "staff.staff_boss", "rental", "rental.rental_staff", "store", "store.store_manager", "store.store_country"}, calls)
+	assert.Equal(t, []string{"CREATE SEQUENCE rental_seq", "CREATE TABLE staff", "CREATE TABLE rental", "CREATE TABLE store", "ALTER TABLE staff"}, statements)
+	applier.AssertExpectations(t)
+}
@@ -0,0 +1,69 @@
+// Copyright (C) 2021 CGI France
+//
+// This file is part of LINO.
+//
+// LINO is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// LINO is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with LINO.  If not, see <http://www.gnu.org/licenses/>.
+
+package schema
+
+// Schema holds the structure of a database: its tables and sequences.
+type Schema struct {
+	Tables    []Table
+	Sequences []Sequence
+}
+
+// Table holds the columns and the constraints of a table.
+type Table struct {
+	Name        string
+	Columns     []Column
+	PrimaryKey  []string
+	UniqueKeys  [][]string
+	ForeignKeys []ForeignKey
+}
+
+// Column holds the definition of a table column.
+// Type is a portable type (smallint, integer, bigint, numeric(p,s), float, double, varchar(n), char(n), text, boolean, date, timestamp, timestamptz, blob)
+// or the type of the source database if it can't be mapped.
+// Default is a literal, CURRENT_DATE or CURRENT_TIMESTAMP, Sequence is the name of the sequence giving the default value.
+type Column struct {
+	Name     string
+	Type     string
+	Nullable bool
+	Default  string
+	Sequence string
+}
+
+// ForeignKey holds the columns of a table referencing the columns of another table.
+type ForeignKey struct {
+	Name              string
+	Columns           []string
+	References        string
+	ReferencedColumns []string
+}
+
+// Sequence holds the definition of a sequence.
+type Sequence struct {
+	Name      string
+	Start     int64
+	Increment int64
+}
+
+// Error is the error type returned by the domain
+type Error struct {
+	Description string
+}
+
+func (e *Error) Error() string {
+	return e.Description
+}
@@ -17,14 +17,17 @@
 
 package table
 
+import "github.com/cgi-fr/lino/pkg/dataconnector"
+
 // ExtractorFactory exposes methods to create new extractors.
 type ExtractorFactory interface {
-	New(url string, schema string) Extractor
+	New(url string, schema string, options dataconnector.Options) Extractor
 }
 
-// Extractor allows to extract primary keys from a relational database.
+// Extractor allows to extract primary and unique keys from a relational database, tables without primary key have no Keys.
 type Extractor interface {
-	Extract() ([]Table, *Error)
+	Extract(filter Filter) ([]Table, *Error)
+	ExtractColumns() ([]Table, *Error)
 }
 
 // Storage allows to store and retrieve Relations objects.
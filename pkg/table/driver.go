@@ -17,15 +17,161 @@
 
 package table
 
-// Extract table metadatas from a relational database.
-func Extract(e Extractor, s Storage) *Error {
-	tables, err := e.Extract()
+import (
+	"fmt"
+
+	"github.com/cgi-fr/lino/pkg/identifier"
+)
+
+// Extract table metadatas from a relational database, restricted to the tables matching filter.
+// A table without primary key uses its first unique key, a table with neither is not stored.
+// Stored virtual tables are kept, and so are the stored physical tables outside a non empty filter.
+func Extract(e Extractor, s Storage, filter Filter) (ExtractReport, *Error) {
+	report := ExtractReport{UniqueKeyTables: []string{}, NoKeyTables: []string{}}
+
+	tables, err := e.Extract(filter)
 	if err != nil {
+		return report, err
+	}
+
+	stored, err := s.List()
+	if err != nil {
+		return report, err
+	}
+
+	extracted := map[string]bool{}
+	for _, table := range tables {
+		extracted[table.Name] = true
+	}
+
+	result := []Table{}
+	virtual := map[string]bool{}
+	for _, table := range stored {
+		switch {
+		case table.View != "" || table.Query != "":
+			result = append(result, table)
+			virtual[table.Name] = true
+		case !filter.empty() && !extracted[table.Name]:
+			result = append(result, table)
+		}
+	}
+
+	for _, table := range tables {
+		if virtual[table.Name] {
+			continue
+		}
+		if len(table.Keys) == 0 {
+			if len(table.AlternateKeys) == 0 {
+				report.NoKeyTables = append(report.NoKeyTables, table.Name)
+				continue
+			}
+			table.Keys = table.AlternateKeys[0]
+			report.UniqueKeyTables = append(report.UniqueKeyTables, table.Name)
+		}
+		result = append(result, table)
+	}
+
+	err = s.Store(result)
+	if err != nil {
+		return report, err
+	}
+	return report, nil
+}
+
+// Add a table to the storage, the name and the keys must be valid identifiers and the table must not exist.
+func Add(s Storage, t Table) *Error {
+	if err := validate(t); err != nil {
 		return err
 	}
-	err = s.Store(tables)
+
+	tables, err := s.List()
+	if err != nil {
+		return err
+	}
+
+	for _, table := range tables {
+		if table.Name == t.Name {
+			return &Error{Description: fmt.Sprintf("table %s already exists", t.Name)}
+		}
+	}
+
+	return s.Store(append(tables, t))
+}
+
+// Remove a table from the storage.
+func Remove(s Storage, name string) *Error {
+	tables, err := s.List()
 	if err != nil {
 		return err
 	}
+
+	result := []Table{}
+	for _, table := range tables {
+		if table.Name != name {
+			result = append(result, table)
+		}
+	}
+
+	if len(result) == len(tables) {
+		return &Error{Description: fmt.Sprintf("no table named %s", name)}
+	}
+
+	return s.Store(result)
+}
+
+// Get a table from the storage, nil if it does not exist.
+func Get(s Storage, name string) (*Table, *Error) {
+	tables, err := s.List()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, table := range tables {
+		if table.Name == name {
+			return &table, nil
+		}
+	}
+	return nil, nil
+}
+
+// List all stored tables.
+func List(s Storage) ([]Table, *Error) {
+	tables, err := s.List()
+	if err != nil {
+		return nil, err
+	}
+	if tables == nil {
+		tables = []Table{}
+	}
+	return tables, nil
+}
+
+func validate(t Table) *Error {
+	if !identifier.ValidTableName(t.Name) {
+		return &Error{Description: fmt.Sprintf("invalid table name '%s'", t.Name)}
+	}
+
+	if t.View != "" && t.Query != "" {
+		return &Error{Description: fmt.Sprintf("table %s can't be backed by both a view and a query", t.Name)}
+	}
+
+	if t.View != "" && !identifier.ValidTableName(t.View) {
+		return &Error{Description: fmt.Sprintf("invalid view name '%s' for table %s", t.View, t.Name)}
+	}
+
+	if len(t.Keys) == 0 {
+		return &Error{Description: fmt.Sprintf("table %s must have at least one key", t.Name)}
+	}
+
+	seen := map[string]bool{}
+	for _, key := range t.Keys {
+		if !identifier.Valid(key) {
+			return &Error{Description: fmt.Sprintf("invalid key '%s' for table %s", key, t.Name)}
+		}
+		if seen[key] {
+			return &Error{Description: fmt.Sprintf("duplicate key '%s' for table %s", key, t.Name)}
+		}
+		seen[key] = true
+	}
 	return nil
 }
@@ -0,0 +1,122 @@
+// Copyright (C) 2021 CGI France
+//
+// This file is part of LINO.
+//
+// LINO is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// LINO is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with LINO.  If not, see <http://www.gnu.org/licenses/>.
+
+package table_test
+
+import (
+	"testing"
+
+	"github.com/cgi-fr/lino/pkg/table"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExtractAlternateKeys(t *testing.T) {
+	extractor := &table.MockExtractor{}
+	extractor.On("Extract", table.Filter{}).Return([]table.Table{
+		{Name: "A", Keys: []string{"id"}, AlternateKeys: [][]string{{"code"}}},
+		{Name: "B", Keys: []string{}, AlternateKeys: [][]string{{"code", "version"}, {"uuid"}}},
+		{Name: "C", Keys: []string{}},
+	}, nil)
+
+	storage := &table.MockStorage{}
+	storage.On("List").Return([]table.Table{}, nil)
+	storage.On("Store", []table.Table{
+		{Name: "A", Keys: []string{"id"}, AlternateKeys: [][]string{{"code"}}},
+		{Name: "B", Keys: []string{"code", "version"}, AlternateKeys: [][]string{{"code", "version"}, {"uuid"}}},
+	}).Return(nil)
+
+	report, err := table.Extract(extractor, storage, table.Filter{})
+
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"B"}, report.UniqueKeyTables)
+	assert.Equal(t, []string{"C"}, report.NoKeyTables)
+	storage.AssertExpectations(t)
+}
+
+func TestAddRemoveTable(t *testing.T) {
+	film := table.Table{Name: "public.film", Keys: []string{"film_id"}}
+	actor := table.Table{Name: "actor", Keys: []string{"actor_id"}}
+
+	storage := &table.MockStorage{}
+	storage.On("List").Return([]table.Table{film}, nil)
+	storage.On("Store", []table.Table{film, actor}).Return(nil)
+	storage.On("Store", []table.Table{}).Return(nil)
+
+	assert.Nil(t, table.Add(storage, actor))
+	assert.Equal(t, "table public.film already exists", table.Add(storage, film).Description)
+	assert.Equal(t, "invalid table name 'public.film-2'", table.Add(storage, table.Table{Name: "public.film-2", Keys: []string{"id"}}).Description)
+	assert.Equal(t, "invalid table name 'public.\"Film\"'", table.Add(storage, table.Table{Name: `public."Film"`, Keys: []string{"id"}}).Description)
+	assert.Equal(t, "table staff must have at least one key", table.Add(storage, table.Table{Name: "staff"}).Description)
+	assert.Equal(t, "duplicate key 'id' for table staff", table.Add(storage, table.Table{Name: "staff", Keys: []string{"id", "id"}}).Description)
+
+	assert.Nil(t, table.Remove(storage, "public.film"))
+	assert.Equal(t, "no table named actor", table.Remove(storage, "actor").Description)
+
+	got, err := table.Get(storage, "public.film")
+	assert.Nil(t, err)
+	assert.Equal(t, &film, got)
+	storage.AssertExpectations(t)
+}
+
+func TestExtractKeepsVirtualTables(t *testing.T) {
+	claims := table.Table{Name: "customer_open_claim", Keys: []string{"customer_id"}, Query: "SELECT c.* FROM customer c WHERE c.claim_status = 'open'"}
+	active := table.Table{Name: "active_customer", Keys: []string{"customer_id"}, View: "v_active_customer"}
+
+	extractor := &table.MockExtractor{}
+	extractor.On("Extract", table.Filter{}).Return([]table.Table{
+		{Name: "customer", Keys: []string{"customer_id"}},
+		{Name: "active_customer", Keys: []string{"id"}},
+	}, nil)
+
+	storage := &table.MockStorage{}
+	storage.On("List").Return([]table.Table{{Name: "customer", Keys: []string{"id"}}, claims, active}, nil)
+	storage.On("Store", []table.Table{claims, active, {Name: "customer", Keys: []string{"customer_id"}}}).Return(nil)
+
+	_, err := table.Extract(extractor, storage, table.Filter{})
+
+	assert.Nil(t, err)
+	storage.AssertExpectations(t)
+}
+
+func TestExtractWithFilterKeepsOtherTables(t *testing.T) {
+	filter := table.Filter{Include: []string{"order*"}}
+
+	extractor := &table.MockExtractor{}
+	extractor.On("Extract", filter).Return([]table.Table{
+		{Name: "order", Keys: []string{"order_id"}},
+		{Name: "order_line", Keys: []string{}},
+		{Name: "order_status", Keys: []string{"code"}},
+	}, nil)
+
+	storage := &table.MockStorage{}
+	storage.On("List").Return([]table.Table{
+		{Name: "customer", Keys: []string{"customer_id"}},
+		{Name: "order", Keys: []string{"id"}},
+		{Name: "order_line", Keys: []string{"order_id", "line"}},
+	}, nil)
+	storage.On("Store", []table.Table{
+		{Name: "customer", Keys: []string{"customer_id"}},
+		{Name: "order", Keys: []string{"order_id"}},
+		{Name: "order_status", Keys: []string{"code"}},
+	}).Return(nil)
+
+	report, err := table.Extract(extractor, storage, filter)
+
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"order_line"}, report.NoKeyTables)
+	storage.AssertExpectations(t)
+}
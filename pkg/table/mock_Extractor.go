@@ -9,8 +9,33 @@ type MockExtractor struct {
 	mock.Mock
 }
 
-// Extract provides a mock function with given fields:
-func (_m *MockExtractor) Extract() ([]Table, *Error) {
+// Extract provides a mock function with given fields: filter
+func (_m *MockExtractor) Extract(filter Filter) ([]Table, *Error) {
+	ret := _m.Called(filter)
+
+	var r0 []Table
+	if rf, ok := ret.Get(0).(func(Filter) []Table); ok {
+		r0 = rf(filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]Table)
+		}
+	}
+
+	var r1 *Error
+	if rf, ok := ret.Get(1).(func(Filter) *Error); ok {
+		r1 = rf(filter)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*Error)
+		}
+	}
+
+	return r0, r1
+}
+
+// ExtractColumns provides a mock function with given fields:
+func (_m *MockExtractor) ExtractColumns() ([]Table, *Error) {
 	ret := _m.Called()
 
 	var r0 []Table
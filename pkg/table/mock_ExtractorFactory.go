@@ -2,20 +2,23 @@
 
 package table
 
-import mock "github.com/stretchr/testify/mock"
+import (
+	dataconnector "github.com/cgi-fr/lino/pkg/dataconnector"
+	mock "github.com/stretchr/testify/mock"
+)
 
 // MockExtractorFactory is an autogenerated mock type for the ExtractorFactory type
 type MockExtractorFactory struct {
 	mock.Mock
 }
 
-// New provides a mock function with given fields: url, schema
-func (_m *MockExtractorFactory) New(url string, schema string) Extractor {
-	ret := _m.Called(url, schema)
+// New provides a mock function with given fields: url, schema, options
+func (_m *MockExtractorFactory) New(url string, schema string, options dataconnector.Options) Extractor {
+	ret := _m.Called(url, schema, options)
 
 	var r0 Extractor
-	if rf, ok := ret.Get(0).(func(string, string) Extractor); ok {
-		r0 = rf(url, schema)
+	if rf, ok := ret.Get(0).(func(string, string, dataconnector.Options) Extractor); ok {
+		r0 = rf(url, schema, options)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(Extractor)
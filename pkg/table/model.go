@@ -18,9 +18,43 @@
 package table
 
 // Table holds a name (table name) and a list of keys (table columns).
+// AlternateKeys are the unique keys of the table, Keys is the first one if the table has no primary key.
+// A virtual table is backed by a View or a SQL Query instead of a table of the same name, its keys are declared by the user.
 type Table struct {
-	Name string
-	Keys []string
+	Name          string
+	Keys          []string
+	AlternateKeys [][]string
+	Columns       []Column
+	View          string
+	Query         string
+}
+
+// ExtractReport lists the tables extracted without primary key.
+type ExtractReport struct {
+	// UniqueKeyTables have no primary key, their first unique key is used instead
+	UniqueKeyTables []string
+	// NoKeyTables have neither primary nor unique key, they are not stored
+	NoKeyTables []string
+}
+
+// Column holds the metadata of a table column.
+type Column struct {
+	Name       string
+	Type       string
+	Nullable   bool
+	HasDefault bool
+}
+
+// Filter restricts the extraction to the tables of Schemas (the dataconnector schema if empty) whose name matches an Include pattern (all if empty) and no Exclude pattern.
+// A pattern is a glob (with * and ?) or a regular expression between slashes (/^sales_/).
+type Filter struct {
+	Schemas []string
+	Include []string
+	Exclude []string
+}
+
+func (f Filter) empty() bool {
+	return len(f.Schemas) == 0 && len(f.Include) == 0 && len(f.Exclude) == 0
 }
 
 // Error is the error type returned by the domain